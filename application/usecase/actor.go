@@ -0,0 +1,16 @@
+package usecase
+
+import "context"
+
+type actorKey struct{}
+
+// WithActor returns a context whose writes are attributed to actor
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor of ctx, or an empty string when none is set
+func ActorFrom(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
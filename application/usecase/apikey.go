@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/domain"
+)
+
+// A token is the prefix followed by "_" and the secret, such as vk_0123456789ab_<48 hex digits>
+const (
+	apiKeyTokenPrefix = "vk_"
+	apiKeyIDBytes     = 6
+	apiKeySecretBytes = 24
+)
+
+// ErrInvalidAPIKey is returned for a token that is malformed, unknown, revoked or expired
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+type APIKeyUsecase struct {
+	Repository APIKeyRepository
+
+	// The last use of a key is recorded at most once per interval, to spare a write on every request
+	LastUsedInterval time.Duration
+}
+
+func NewAPIKeyUsecase(repository APIKeyRepository, lastUsedInterval time.Duration) *APIKeyUsecase {
+	return &APIKeyUsecase{
+		Repository:       repository,
+		LastUsedInterval: lastUsedInterval,
+	}
+}
+
+// CreateAPIKey stores a new key with the name, scopes and expiry of key, and returns its token.
+// The token is shown only once; the key keeps its hash.
+func (u *APIKeyUsecase) CreateAPIKey(ctx context.Context, key *domain.APIKey) (string, error) {
+	id, err := randomHex(apiKeyIDBytes)
+	if err != nil {
+		return "", err
+	}
+	secret, err := randomHex(apiKeySecretBytes)
+	if err != nil {
+		return "", err
+	}
+
+	key.Prefix = apiKeyTokenPrefix + id
+	token := key.Prefix + "_" + secret
+	key.SecretHash = hashToken(token)
+	key.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+
+	keyID, err := u.Repository.InsertAPIKey(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	key.KeyID = keyID
+
+	return token, nil
+}
+
+func (u *APIKeyUsecase) FetchAPIKeyList(ctx context.Context) ([]*domain.APIKey, error) {
+	return u.Repository.SelectAllAPIKeys(ctx)
+}
+
+// RevokeAPIKey disables the key for good. It stays listed with the time of its revocation.
+func (u *APIKeyUsecase) RevokeAPIKey(ctx context.Context, keyID int64) (int64, error) {
+	return u.Repository.RevokeAPIKey(ctx, keyID, time.Now())
+}
+
+// Authenticate returns the active key of token and records its use
+func (u *APIKeyUsecase) Authenticate(ctx context.Context, token string) (*domain.APIKey, error) {
+	// The prefix finds the key, and the hash of the whole token proves it
+	prefix, ok := tokenPrefix(token)
+	if !ok {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := u.Repository.SelectAPIKeyByPrefix(ctx, prefix)
+	if errors.Is(err, domain.ErrAPIKeyNotFound) {
+		return nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashToken(token)), []byte(key.SecretHash)) != 1 {
+		slog.WarnContext(ctx, "api key secret mismatch", slog.String("prefix", prefix))
+		return nil, ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if !key.Active(now) {
+		slog.WarnContext(ctx, "revoked or expired api key used", slog.String("prefix", prefix))
+		return nil, ErrInvalidAPIKey
+	}
+
+	// A failure to record the use does not fail the request
+	if now.Sub(key.LastUsedAt) >= u.LastUsedInterval {
+		if err := u.Repository.UpdateAPIKeyLastUsed(ctx, key.KeyID, now); err != nil {
+			slog.WarnContext(ctx, "failed to record the use of an api key", slog.String("error", err.Error()))
+		} else {
+			key.LastUsedAt = now
+		}
+	}
+
+	return key, nil
+}
+
+type apiKeyKey struct{}
+
+// WithAPIKey returns a context authenticated with key
+func WithAPIKey(ctx context.Context, key *domain.APIKey) context.Context {
+	return context.WithValue(ctx, apiKeyKey{}, key)
+}
+
+// APIKeyFrom returns the API key of ctx, or nil when the request is anonymous
+func APIKeyFrom(ctx context.Context) *domain.APIKey {
+	key, _ := ctx.Value(apiKeyKey{}).(*domain.APIKey)
+	return key
+}
+
+// tokenPrefix returns the prefix of a well-formed token
+func tokenPrefix(token string) (string, bool) {
+	rest, ok := strings.CutPrefix(token, apiKeyTokenPrefix)
+	if !ok {
+		return "", false
+	}
+	id, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(id) != 2*apiKeyIDBytes || len(secret) != 2*apiKeySecretBytes {
+		return "", false
+	}
+	return apiKeyTokenPrefix + id, true
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,22 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/domain"
+)
+
+type APIKeyRepository interface {
+	InsertAPIKey(ctx context.Context, key *domain.APIKey) (int64, error)
+
+	// SelectAPIKeyByPrefix returns the key, revoked or expired ones included, or domain.ErrAPIKeyNotFound
+	SelectAPIKeyByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error)
+
+	SelectAllAPIKeys(ctx context.Context) ([]*domain.APIKey, error)
+
+	// RevokeAPIKey keeps the time of the first revocation when the key is already revoked
+	RevokeAPIKey(ctx context.Context, keyID int64, revokedAt time.Time) (int64, error)
+
+	UpdateAPIKeyLastUsed(ctx context.Context, keyID int64, lastUsedAt time.Time) error
+}
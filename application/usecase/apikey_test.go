@@ -0,0 +1,129 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+)
+
+// fakeAPIKeyRepository keeps the keys in a slice, indexed by keyID - 1
+type fakeAPIKeyRepository struct {
+	keys    []*domain.APIKey
+	touches int
+}
+
+func (r *fakeAPIKeyRepository) InsertAPIKey(_ context.Context, key *domain.APIKey) (int64, error) {
+	stored := *key
+	stored.KeyID = int64(len(r.keys) + 1)
+	r.keys = append(r.keys, &stored)
+	return stored.KeyID, nil
+}
+
+func (r *fakeAPIKeyRepository) SelectAPIKeyByPrefix(_ context.Context, prefix string) (*domain.APIKey, error) {
+	for _, key := range r.keys {
+		if key.Prefix == prefix {
+			found := *key
+			return &found, nil
+		}
+	}
+	return nil, domain.ErrAPIKeyNotFound
+}
+
+func (r *fakeAPIKeyRepository) SelectAllAPIKeys(context.Context) ([]*domain.APIKey, error) {
+	return r.keys, nil
+}
+
+func (r *fakeAPIKeyRepository) RevokeAPIKey(_ context.Context, keyID int64, revokedAt time.Time) (int64, error) {
+	if keyID < 1 || keyID > int64(len(r.keys)) {
+		return 0, domain.ErrAPIKeyNotFound
+	}
+	r.keys[keyID-1].RevokedAt = revokedAt
+	return 1, nil
+}
+
+func (r *fakeAPIKeyRepository) UpdateAPIKeyLastUsed(_ context.Context, keyID int64, lastUsedAt time.Time) error {
+	r.touches++
+	r.keys[keyID-1].LastUsedAt = lastUsedAt
+	return nil
+}
+
+func TestAPIKeyAuthenticate(t *testing.T) {
+	ctx := context.Background()
+	repository := &fakeAPIKeyRepository{}
+	u := usecase.NewAPIKeyUsecase(repository, time.Minute)
+
+	key := &domain.APIKey{Name: "importer", Scopes: []domain.Scope{domain.ScopeVocabRead}}
+	token, err := u.CreateAPIKey(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the hash of the token is stored, and the prefix is visible
+	stored := repository.keys[0]
+	if !strings.HasPrefix(token, stored.Prefix+"_") || !strings.HasPrefix(stored.Prefix, "vk_") {
+		t.Errorf("token %q does not start with the prefix %q", token, stored.Prefix)
+	}
+	if stored.SecretHash == "" || strings.Contains(token, stored.SecretHash) || strings.Contains(stored.SecretHash, token) {
+		t.Errorf("SecretHash = %q for the token %q", stored.SecretHash, token)
+	}
+
+	authenticated, err := u.Authenticate(ctx, token)
+	if err != nil || authenticated.KeyID != key.KeyID || !authenticated.HasScope(domain.ScopeVocabRead) {
+		t.Fatalf("Authenticate = %+v, %v", authenticated, err)
+	}
+
+	// The last use is recorded once per interval
+	if _, err := u.Authenticate(ctx, token); err != nil {
+		t.Fatal(err)
+	}
+	if repository.touches != 1 || stored.LastUsedAt.IsZero() {
+		t.Errorf("last use recorded %d times, at %v, want once", repository.touches, stored.LastUsedAt)
+	}
+
+	invalid := []string{
+		"",
+		"not-a-key",
+		stored.Prefix + "_" + strings.Repeat("0", 48),
+		"vk_000000000000_" + strings.Repeat("0", 48),
+		token + "0",
+	}
+	for _, token := range invalid {
+		if _, err := u.Authenticate(ctx, token); !errors.Is(err, usecase.ErrInvalidAPIKey) {
+			t.Errorf("Authenticate(%q) = %v, want ErrInvalidAPIKey", token, err)
+		}
+	}
+
+	// A revoked key stops working
+	if _, err := u.RevokeAPIKey(ctx, key.KeyID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := u.Authenticate(ctx, token); !errors.Is(err, usecase.ErrInvalidAPIKey) {
+		t.Errorf("Authenticate of a revoked key = %v, want ErrInvalidAPIKey", err)
+	}
+}
+
+func TestAPIKeyExpiry(t *testing.T) {
+	ctx := context.Background()
+	u := usecase.NewAPIKeyUsecase(&fakeAPIKeyRepository{}, time.Minute)
+
+	expired, err := u.CreateAPIKey(ctx, &domain.APIKey{Name: "old", ExpiresAt: time.Now().Add(-time.Second)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := u.Authenticate(ctx, expired); !errors.Is(err, usecase.ErrInvalidAPIKey) {
+		t.Errorf("Authenticate of an expired key = %v, want ErrInvalidAPIKey", err)
+	}
+
+	valid, err := u.CreateAPIKey(ctx, &domain.APIKey{Name: "new", ExpiresAt: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := u.Authenticate(ctx, valid); err != nil {
+		t.Errorf("Authenticate of a key before its expiry = %v", err)
+	}
+}
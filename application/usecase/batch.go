@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/takumi616/golang-backend-sample/domain"
+)
+
+// ErrBatchAborted is the result of the operations rolled back because another operation of an atomic batch failed
+var ErrBatchAborted = errors.New("batch aborted by another operation")
+
+type BatchOperationType string
+
+const (
+	BatchCreate BatchOperationType = "create"
+	BatchUpdate BatchOperationType = "update"
+	BatchDelete BatchOperationType = "delete"
+)
+
+type BatchOperation struct {
+	Type BatchOperationType
+
+	// Target of an update or a delete
+	VocabularyNo int64
+
+	// Content of a create or an update
+	Vocabulary *domain.Vocabulary
+}
+
+type BatchResult struct {
+	// Added, updated or deleted vocabulary
+	VocabularyNo int64
+
+	// nil when the operation succeeded
+	Err error
+}
+
+// ApplyBatch runs the operations in order in a single transaction and returns a result for each of them.
+// When atomic is true, the first failure rolls back every operation; otherwise the failed operations are
+// skipped and the others are committed. The error is only for a transaction that could not be committed.
+func (u *VocabularyUsecase) ApplyBatch(ctx context.Context, operations []BatchOperation, atomic bool) ([]BatchResult, error) {
+	var results []BatchResult
+	var events []VocabularyEvent
+	failed := -1
+
+	err := u.TxManager.WithinTx(ctx, TxOptions{}, func(ctx context.Context) error {
+		// Start over when the transaction is retried
+		results = make([]BatchResult, len(operations))
+		events = nil
+		failed = -1
+
+		// Hold the events until the transaction commits
+		ctx = withPendingEvents(ctx, &events)
+
+		for i, operation := range operations {
+			vocabularyNo, err := u.applyOperation(ctx, operation)
+			results[i] = BatchResult{VocabularyNo: vocabularyNo, Err: err}
+
+			if err != nil && atomic {
+				failed = i
+				return err
+			}
+		}
+		return nil
+	})
+
+	if failed >= 0 && errors.Is(err, results[failed].Err) {
+		for i := range results {
+			if i != failed {
+				results[i] = BatchResult{Err: ErrBatchAborted}
+			}
+		}
+		return results, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	u.publishEvents(ctx, events)
+	return results, nil
+}
+
+func (u *VocabularyUsecase) applyOperation(ctx context.Context, operation BatchOperation) (int64, error) {
+	switch operation.Type {
+	case BatchCreate:
+		return u.AddVocabulary(ctx, operation.Vocabulary)
+	case BatchUpdate:
+		return u.UpdateVocabulary(ctx, operation.VocabularyNo, operation.Vocabulary)
+	case BatchDelete:
+		if _, err := u.DeleteVocabulary(ctx, operation.VocabularyNo); err != nil {
+			return 0, err
+		}
+		return operation.VocabularyNo, nil
+	default:
+		return 0, fmt.Errorf("unknown batch operation %q", operation.Type)
+	}
+}
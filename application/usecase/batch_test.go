@@ -0,0 +1,132 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db/repository/memory"
+)
+
+func newUsecase(t *testing.T) *usecase.VocabularyUsecase {
+	t.Helper()
+
+	repository := memory.NewVocabularyRepository()
+	u := usecase.NewVocabularyUsecase(repository, memory.NewTxManager(repository), nil, nil)
+	for _, title := range []string{"apple", "pear"} {
+		if _, err := u.AddVocabulary(context.Background(), &domain.Vocabulary{Title: title, Meaning: "fruit", Sentence: "s"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return u
+}
+
+// operations creates a vocabulary, renames apple to a duplicate title and deletes pear
+var operations = []usecase.BatchOperation{
+	{Type: usecase.BatchCreate, Vocabulary: &domain.Vocabulary{Title: "grape", Meaning: "fruit", Sentence: "s"}},
+	{Type: usecase.BatchUpdate, VocabularyNo: 1, Vocabulary: &domain.Vocabulary{Title: "grape", Meaning: "fruit", Sentence: "s"}},
+	{Type: usecase.BatchDelete, VocabularyNo: 2},
+}
+
+func TestApplyBatch(t *testing.T) {
+	ctx := context.Background()
+	u := newUsecase(t)
+
+	results, err := u.ApplyBatch(ctx, operations, false)
+	if err != nil {
+		t.Fatalf("ApplyBatch returned an error: %v", err)
+	}
+
+	if results[0].Err != nil || results[0].VocabularyNo != 3 {
+		t.Errorf("create: %+v", results[0])
+	}
+	if !errors.Is(results[1].Err, domain.ErrVocabularyDuplicate) {
+		t.Errorf("update: %+v, want ErrVocabularyDuplicate", results[1])
+	}
+	if results[2].Err != nil || results[2].VocabularyNo != 2 {
+		t.Errorf("delete: %+v", results[2])
+	}
+
+	// The successful operations are committed
+	vocabularyList, err := u.FetchVocabularyList(ctx, usecase.VocabularyQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var titles []string
+	for _, vocabulary := range vocabularyList {
+		titles = append(titles, vocabulary.Title)
+	}
+	if len(titles) != 2 || titles[0] != "apple" || titles[1] != "grape" {
+		t.Errorf("titles = %v, want [apple grape]", titles)
+	}
+}
+
+func TestApplyBatchAtomic(t *testing.T) {
+	ctx := context.Background()
+	u := newUsecase(t)
+
+	results, err := u.ApplyBatch(ctx, operations, true)
+	if err != nil {
+		t.Fatalf("ApplyBatch returned an error: %v", err)
+	}
+
+	if !errors.Is(results[0].Err, usecase.ErrBatchAborted) {
+		t.Errorf("create: %+v, want ErrBatchAborted", results[0])
+	}
+	if !errors.Is(results[1].Err, domain.ErrVocabularyDuplicate) {
+		t.Errorf("update: %+v, want ErrVocabularyDuplicate", results[1])
+	}
+	if !errors.Is(results[2].Err, usecase.ErrBatchAborted) {
+		t.Errorf("delete: %+v, want ErrBatchAborted", results[2])
+	}
+
+	// Nothing is committed
+	vocabularyList, err := u.FetchVocabularyList(ctx, usecase.VocabularyQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vocabularyList) != 2 {
+		t.Errorf("got %d vocabularies, want 2", len(vocabularyList))
+	}
+}
+
+// recordingBroker keeps the published events
+type recordingBroker struct {
+	events []usecase.VocabularyEvent
+}
+
+func (b *recordingBroker) Publish(event usecase.VocabularyEvent) {
+	b.events = append(b.events, event)
+}
+
+func (b *recordingBroker) Subscribe(string) *usecase.VocabularySubscription {
+	return nil
+}
+
+func TestApplyBatchEvents(t *testing.T) {
+	for _, atomic := range []bool{false, true} {
+		broker := &recordingBroker{}
+		u := newUsecase(t)
+		u.Events = broker
+
+		if _, err := u.ApplyBatch(usecase.WithActor(context.Background(), "alice"), operations, atomic); err != nil {
+			t.Fatalf("ApplyBatch returned an error: %v", err)
+		}
+
+		// Only the committed operations are published
+		var got []string
+		for _, event := range broker.events {
+			got = append(got, fmt.Sprintf("%s %d by %s", event.Type, event.VocabularyNo, event.Actor))
+		}
+		var want []string
+		if !atomic {
+			want = []string{"vocabulary.created 3 by alice", "vocabulary.deleted 2 by alice"}
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("atomic=%v: events = %v, want %v", atomic, got, want)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"context"
+	"time"
+)
+
+// IdempotencyRecord is the response recorded for an Idempotency-Key
+type IdempotencyRecord struct {
+	// Hash of the method, path and body of the first request
+	RequestHash string
+
+	// 0 while the first request is still being handled
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
+
+// IdempotencyStore keeps the responses of the requests sent with an Idempotency-Key
+type IdempotencyStore interface {
+	// Reserve claims key for a new request. When key is already claimed and has not expired,
+	// it returns the existing record and false instead.
+	Reserve(ctx context.Context, key, requestHash string, expiresAt time.Time) (*IdempotencyRecord, bool, error)
+
+	// Complete records the response of the request that reserved key
+	Complete(ctx context.Context, key string, record IdempotencyRecord) error
+
+	// Release forgets key when its request failed, so that the client can retry it
+	Release(ctx context.Context, key string) error
+}
@@ -0,0 +1,271 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrJobsDisabled is returned when the backend keeps no jobs
+var ErrJobsDisabled = errors.New("jobs are not supported by the backend")
+
+// JobStatus is the state of a Job
+type JobStatus string
+
+const (
+	// Waiting for its next attempt
+	JobPending JobStatus = "pending"
+
+	// Completed by its handler
+	JobSucceeded JobStatus = "succeeded"
+
+	// Given up after too many failed attempts, or because no handler knows its kind
+	JobDead JobStatus = "dead"
+)
+
+// Job is a unit of work run in the background by a JobHandler of its kind
+type Job struct {
+	JobID int64
+	Kind  string
+
+	// Arguments of the handler in JSON
+	Payload json.RawMessage
+
+	Status JobStatus
+
+	// Number of attempts started so far
+	Attempts int
+
+	// Time the job is due for its next attempt. Now when it is zero on enqueue.
+	RunAt time.Time
+
+	// A job is not enqueued while another one with the same key is kept. Empty for no key.
+	UniqueKey string
+
+	LastError string
+
+	CreatedAt time.Time
+
+	// Zero until the job succeeds or is given up
+	FinishedAt time.Time
+}
+
+// NewJob returns a job of the kind with the args encoded as its payload
+func NewJob[T any](kind string, args T) (*Job, error) {
+	payload, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the arguments of a %s job: %w", kind, err)
+	}
+	return &Job{Kind: kind, Payload: payload}, nil
+}
+
+// JobHandler runs the jobs of a kind
+type JobHandler struct {
+	Kind string
+
+	// Handle returns an error for the job to be retried
+	Handle func(ctx context.Context, payload json.RawMessage) error
+
+	// Replaces the RetryPolicy of the JobUsecase for the kind when MaxAttempts is set
+	Retry RetryPolicy
+}
+
+// HandleJob returns a handler of the kind that decodes the payload into the arguments of fn
+func HandleJob[T any](kind string, fn func(ctx context.Context, args T) error) JobHandler {
+	return JobHandler{
+		Kind: kind,
+		Handle: func(ctx context.Context, payload json.RawMessage) error {
+			var args T
+			if err := json.Unmarshal(payload, &args); err != nil {
+				return fmt.Errorf("failed to decode the arguments of a %s job: %w", kind, err)
+			}
+			return fn(ctx, args)
+		},
+	}
+}
+
+// JobQueue enqueues the jobs. A job enqueued in TxManager.WithinTx joins its transaction,
+// so it runs if and only if the domain write is committed.
+type JobQueue interface {
+	// EnqueueJob returns the ID of the job, or 0 when a job with the same UniqueKey is already kept
+	EnqueueJob(ctx context.Context, job *Job) (int64, error)
+}
+
+type JobRepository interface {
+	JobQueue
+
+	// ClaimJobs takes up to limit pending jobs of the kinds that are due, counts an attempt for each of them and
+	// postpones them by lease, so that no other runner takes them while they run. A job whose runner stops
+	// before recording the outcome is retried after the lease.
+	ClaimJobs(ctx context.Context, kinds []string, limit int, lease time.Duration) ([]*Job, error)
+
+	// UpdateJob records the outcome of an attempt
+	UpdateJob(ctx context.Context, job *Job) error
+
+	// DeleteFinishedJobs deletes the succeeded and dead jobs finished before the time
+	DeleteFinishedJobs(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Kind of the job deleting the finished jobs
+const PruneJobsKind = "jobs.prune"
+
+type PruneJobsArgs struct {
+	// Finished jobs older than this are deleted
+	Retention time.Duration `json:"retention"`
+}
+
+type JobUsecase struct {
+	// nil when the backend keeps no jobs
+	Repository JobRepository
+
+	Retry RetryPolicy
+
+	// Time a claimed job is hidden from the other runners. It must exceed the time a job takes.
+	Lease time.Duration
+
+	mu       sync.RWMutex
+	handlers map[string]JobHandler
+}
+
+func NewJobUsecase(repository JobRepository, retry RetryPolicy, lease time.Duration) *JobUsecase {
+	return &JobUsecase{
+		Repository: repository,
+		Retry:      retry,
+		Lease:      lease,
+		handlers:   map[string]JobHandler{},
+	}
+}
+
+// Register adds the handler of a kind of job, replacing the previous one
+func (u *JobUsecase) Register(handler JobHandler) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.handlers[handler.Kind] = handler
+}
+
+// EnqueueJob adds the job in the transaction of ctx, if any
+func (u *JobUsecase) EnqueueJob(ctx context.Context, job *Job) (int64, error) {
+	if u.Repository == nil {
+		return 0, ErrJobsDisabled
+	}
+	return u.Repository.EnqueueJob(ctx, job)
+}
+
+// RunPending runs up to limit due jobs of the registered kinds concurrently and records their outcomes.
+// It returns the number of jobs attempted.
+func (u *JobUsecase) RunPending(ctx context.Context, limit int) (int, error) {
+	if u.Repository == nil {
+		return 0, ErrJobsDisabled
+	}
+
+	u.mu.RLock()
+	kinds := make([]string, 0, len(u.handlers))
+	for kind := range u.handlers {
+		kinds = append(kinds, kind)
+	}
+	u.mu.RUnlock()
+	slices.Sort(kinds)
+
+	jobs, err := u.Repository.ClaimJobs(ctx, kinds, limit, u.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(jobs))
+	for i, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = u.run(ctx, job)
+		}()
+	}
+	wg.Wait()
+
+	return len(jobs), errors.Join(errs...)
+}
+
+// PruneJobs deletes the jobs finished longer than the retention ago
+func (u *JobUsecase) PruneJobs(ctx context.Context, args PruneJobsArgs) error {
+	if u.Repository == nil {
+		return ErrJobsDisabled
+	}
+
+	deleted, err := u.Repository.DeleteFinishedJobs(ctx, time.Now().Add(-args.Retention))
+	if err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "finished jobs were pruned", slog.Int64("count", deleted))
+	return nil
+}
+
+// run makes an attempt and schedules the next one when it fails
+func (u *JobUsecase) run(ctx context.Context, job *Job) error {
+	u.mu.RLock()
+	handler, ok := u.handlers[job.Kind]
+	u.mu.RUnlock()
+
+	var err error
+	if ok {
+		err = runHandler(context.WithValue(ctx, jobIDKey{}, job.JobID), handler, job)
+	} else {
+		err = fmt.Errorf("no handler is registered for the kind %q", job.Kind)
+	}
+
+	retry := u.Retry
+	if handler.Retry.MaxAttempts > 0 {
+		retry = handler.Retry
+	}
+
+	switch {
+	case err == nil:
+		job.Status = JobSucceeded
+		job.LastError = ""
+		job.FinishedAt = time.Now()
+	case !ok || job.Attempts >= retry.MaxAttempts:
+		slog.WarnContext(
+			ctx, "the job became a dead letter",
+			slog.Int64("jobID", job.JobID), slog.String("kind", job.Kind), slog.Int("attempts", job.Attempts),
+			slog.String("error", err.Error()),
+		)
+		job.Status = JobDead
+		job.LastError = err.Error()
+		job.FinishedAt = time.Now()
+	default:
+		slog.WarnContext(
+			ctx, "the job failed",
+			slog.Int64("jobID", job.JobID), slog.String("kind", job.Kind), slog.Int("attempts", job.Attempts),
+			slog.String("error", err.Error()),
+		)
+		job.Status = JobPending
+		job.LastError = err.Error()
+		job.RunAt = time.Now().Add(retry.Backoff(job.Attempts))
+	}
+
+	// Record the outcome even when the job was cancelled by the end of the draining
+	return u.Repository.UpdateJob(context.WithoutCancel(ctx), job)
+}
+
+type jobIDKey struct{}
+
+// JobIDFrom returns the ID of the job a handler runs, or 0 outside a handler
+func JobIDFrom(ctx context.Context) int64 {
+	jobID, _ := ctx.Value(jobIDKey{}).(int64)
+	return jobID
+}
+
+// runHandler turns a panic of the handler into an error, so that the job is retried and the runner keeps running
+func runHandler(ctx context.Context, handler JobHandler, job *Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("the job handler panicked: %v", r)
+		}
+	}()
+	return handler.Handle(ctx, job.Payload)
+}
@@ -0,0 +1,149 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+)
+
+// fakeJobRepository hands out the jobs of the claimed kinds once and keeps their recorded outcomes
+type fakeJobRepository struct {
+	usecase.JobRepository
+
+	mu        sync.Mutex
+	claimable []*usecase.Job
+	kinds     []string
+	updated   map[int64]usecase.Job
+}
+
+func (r *fakeJobRepository) ClaimJobs(_ context.Context, kinds []string, limit int, _ time.Duration) ([]*usecase.Job, error) {
+	r.kinds = kinds
+	claimed := r.claimable[:min(limit, len(r.claimable))]
+	r.claimable = r.claimable[len(claimed):]
+	for _, job := range claimed {
+		job.Attempts++
+	}
+	return claimed, nil
+}
+
+func (r *fakeJobRepository) UpdateJob(_ context.Context, job *usecase.Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.updated[job.JobID] = *job
+	return nil
+}
+
+type greetArgs struct {
+	Name string `json:"name"`
+}
+
+func newJob(t *testing.T, jobID int64, kind string, args any, attempts int) *usecase.Job {
+	t.Helper()
+
+	job, err := usecase.NewJob(kind, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	job.JobID = jobID
+	job.Status = usecase.JobPending
+	job.Attempts = attempts
+	return job
+}
+
+func TestRunPending(t *testing.T) {
+	repository := &fakeJobRepository{
+		claimable: []*usecase.Job{
+			newJob(t, 1, "greet", greetArgs{Name: "alice"}, 0),
+			newJob(t, 2, "greet", greetArgs{Name: "bob"}, 0),
+			newJob(t, 3, "greet", greetArgs{Name: "bob"}, 2),
+			newJob(t, 4, "greet", greetArgs{Name: "panic"}, 0),
+			newJob(t, 5, "unknown", greetArgs{}, 0),
+		},
+		updated: map[int64]usecase.Job{},
+	}
+	retry := usecase.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Minute, MaxBackoff: time.Hour}
+	u := usecase.NewJobUsecase(repository, retry, time.Minute)
+
+	var mu sync.Mutex
+	var greeted []string
+	u.Register(usecase.HandleJob("greet", func(ctx context.Context, args greetArgs) error {
+		switch args.Name {
+		case "bob":
+			return errors.New("bob is away")
+		case "panic":
+			panic("unexpected")
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		greeted = append(greeted, args.Name)
+		return nil
+	}))
+	u.Register(usecase.HandleJob("noop", func(ctx context.Context, args struct{}) error { return nil }))
+
+	start := time.Now()
+	attempted, err := u.RunPending(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("RunPending returned an error: %v", err)
+	}
+	if attempted != 5 {
+		t.Errorf("attempted = %d, want 5", attempted)
+	}
+
+	// Only the registered kinds are claimed
+	if !slices.Equal(repository.kinds, []string{"greet", "noop"}) {
+		t.Errorf("claimed kinds = %v, want [greet noop]", repository.kinds)
+	}
+
+	if succeeded := repository.updated[1]; succeeded.Status != usecase.JobSucceeded || succeeded.FinishedAt.IsZero() {
+		t.Errorf("job 1 = %+v, want succeeded", succeeded)
+	}
+	if !slices.Equal(greeted, []string{"alice"}) {
+		t.Errorf("greeted = %v, want [alice]", greeted)
+	}
+
+	retried := repository.updated[2]
+	if retried.Status != usecase.JobPending || retried.LastError != "bob is away" || !retried.FinishedAt.IsZero() {
+		t.Errorf("job 2 = %+v, want pending with the error", retried)
+	}
+	if delay := retried.RunAt.Sub(start); delay < 30*time.Second || delay > time.Minute+time.Second {
+		t.Errorf("job 2 is retried after %v, want 30s to 1m", delay)
+	}
+
+	if dead := repository.updated[3]; dead.Status != usecase.JobDead || dead.Attempts != 3 {
+		t.Errorf("job 3 = %+v, want dead after 3 attempts", dead)
+	}
+
+	// A panic is retried like an error
+	if panicked := repository.updated[4]; panicked.Status != usecase.JobPending || panicked.LastError == "" {
+		t.Errorf("job 4 = %+v, want pending with the panic", panicked)
+	}
+
+	// A job nobody can run is given up at once
+	if unknown := repository.updated[5]; unknown.Status != usecase.JobDead {
+		t.Errorf("job 5 = %+v, want dead", unknown)
+	}
+}
+
+func TestHandleJobInvalidPayload(t *testing.T) {
+	handler := usecase.HandleJob("greet", func(ctx context.Context, args greetArgs) error { return nil })
+
+	if err := handler.Handle(context.Background(), []byte(`{"name":1}`)); err == nil {
+		t.Error("Handle succeeded with an invalid payload")
+	}
+}
+
+func TestJobsDisabled(t *testing.T) {
+	u := usecase.NewJobUsecase(nil, usecase.RetryPolicy{}, time.Minute)
+
+	if _, err := u.EnqueueJob(context.Background(), &usecase.Job{Kind: "greet"}); !errors.Is(err, usecase.ErrJobsDisabled) {
+		t.Errorf("EnqueueJob() = %v, want ErrJobsDisabled", err)
+	}
+	if _, err := u.RunPending(context.Background(), 10); !errors.Is(err, usecase.ErrJobsDisabled) {
+		t.Errorf("RunPending() = %v, want ErrJobsDisabled", err)
+	}
+}
@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy decides when a failed webhook delivery or job is attempted again
+type RetryPolicy struct {
+	// Number of attempts after which a failure is given up as a dead letter
+	MaxAttempts int
+
+	// Delay after the first failure, doubled after each of the next ones up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// Backoff returns the delay after the given number of failed attempts.
+// A random part of up to half of it keeps the retries of many failures apart.
+func (p RetryPolicy) Backoff(attempts int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 1; i < attempts && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	backoff = min(backoff, p.MaxBackoff)
+
+	half := backoff / 2
+	return backoff - half + rand.N(half+1)
+}
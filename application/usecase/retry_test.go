@@ -0,0 +1,31 @@
+package usecase_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	retry := usecase.RetryPolicy{MaxAttempts: 10, InitialBackoff: 10 * time.Second, MaxBackoff: time.Minute}
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: 10 * time.Second},
+		{attempts: 2, want: 20 * time.Second},
+		{attempts: 3, want: 40 * time.Second},
+		{attempts: 4, want: time.Minute},
+		{attempts: 9, want: time.Minute},
+	}
+
+	for _, tt := range tests {
+		for range 20 {
+			if got := retry.Backoff(tt.attempts); got < tt.want/2 || got > tt.want {
+				t.Errorf("Backoff(%d) = %v, want %v to %v", tt.attempts, got, tt.want/2, tt.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+)
+
+// IsolationLevel of a transaction
+type IsolationLevel int
+
+const (
+	// IsolationDefault uses the default level of the TxManager
+	IsolationDefault IsolationLevel = iota
+	IsolationReadCommitted
+	IsolationRepeatableRead
+	IsolationSerializable
+)
+
+var isolationLevelNames = map[IsolationLevel]string{
+	IsolationDefault:        "default",
+	IsolationReadCommitted:  "read_committed",
+	IsolationRepeatableRead: "repeatable_read",
+	IsolationSerializable:   "serializable",
+}
+
+func (l IsolationLevel) String() string {
+	if name, ok := isolationLevelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("IsolationLevel(%d)", int(l))
+}
+
+// ParseIsolationLevel parses read_committed, repeatable_read or serializable
+func ParseIsolationLevel(name string) (IsolationLevel, error) {
+	for level, levelName := range isolationLevelNames {
+		if level != IsolationDefault && levelName == name {
+			return level, nil
+		}
+	}
+	return IsolationDefault, fmt.Errorf("unknown isolation level %q", name)
+}
+
+type TxOptions struct {
+	Isolation IsolationLevel
+}
+
+// TxManager runs a unit of work in one transaction.
+// The transaction is put into the context passed to fn, and the repository
+// methods called with that context join it instead of opening their own.
+type TxManager interface {
+	// WithinTx commits when fn returns nil and rolls back otherwise.
+	// fn may run more than once when the transaction is retried after a serialization failure.
+	// A WithinTx inside fn joins the outer transaction.
+	WithinTx(ctx context.Context, opts TxOptions, fn func(ctx context.Context) error) error
+}
@@ -8,30 +8,112 @@ import (
 
 type VocabularyUsecase struct {
 	Repository VocabularyRepository
+	TxManager  TxManager
+
+	// Receives the changes of the vocabularies. No event is published when nil.
+	Events VocabularyEventBroker
+
+	// Records the changes for the webhooks in their transactions. Nothing is recorded when nil.
+	Outbox VocabularyOutbox
 }
 
-func NewVocabularyUsecase(repository VocabularyRepository) *VocabularyUsecase {
+func NewVocabularyUsecase(
+	repository VocabularyRepository, txManager TxManager, events VocabularyEventBroker, outbox VocabularyOutbox,
+) *VocabularyUsecase {
 	return &VocabularyUsecase{
 		Repository: repository,
+		TxManager:  txManager,
+		Events:     events,
+		Outbox:     outbox,
 	}
 }
 
 func (u *VocabularyUsecase) AddVocabulary(ctx context.Context, vocabulary *domain.Vocabulary) (int64, error) {
-	return u.Repository.Insert(ctx, vocabulary)
+	vocabulary.CreatedBy = ActorFrom(ctx)
+	vocabulary.UpdatedBy = vocabulary.CreatedBy
+
+	var vocabularyNo int64
+	err := u.write(ctx, func(ctx context.Context) error {
+		var err error
+		vocabularyNo, err = u.Repository.Insert(ctx, vocabulary)
+		if err != nil {
+			return err
+		}
+		return u.record(ctx, VocabularyCreated, vocabularyNo, vocabulary)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return vocabularyNo, nil
+}
+
+func (u *VocabularyUsecase) AddVocabularies(ctx context.Context, vocabularies []*domain.Vocabulary) (int64, error) {
+	actor := ActorFrom(ctx)
+	for _, vocabulary := range vocabularies {
+		vocabulary.CreatedBy = actor
+		vocabulary.UpdatedBy = actor
+	}
+
+	var inserted int64
+	err := u.write(ctx, func(ctx context.Context) error {
+		var err error
+		inserted, err = u.Repository.InsertAll(ctx, vocabularies)
+		if err != nil {
+			return err
+		}
+
+		// One event for each vocabulary, as if they were added one by one
+		for _, vocabulary := range vocabularies {
+			if err := u.record(ctx, VocabularyCreated, vocabulary.VocabularyNo, vocabulary); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return inserted, nil
 }
 
 func (u *VocabularyUsecase) FetchVocabularyByNo(ctx context.Context, vocabularyNo int64) (*domain.Vocabulary, error) {
 	return u.Repository.SelectByVocabularyNo(ctx, vocabularyNo)
 }
 
-func (u *VocabularyUsecase) FetchVocabularyList(ctx context.Context) ([]*domain.Vocabulary, error) {
-	return u.Repository.SelectAll(ctx)
+func (u *VocabularyUsecase) FetchVocabularyList(ctx context.Context, query VocabularyQuery) ([]*domain.Vocabulary, error) {
+	return u.Repository.SelectAll(ctx, query)
 }
 
 func (u *VocabularyUsecase) UpdateVocabulary(ctx context.Context, vocabularyNo int64, vocabulary *domain.Vocabulary) (int64, error) {
-	return u.Repository.Update(ctx, vocabularyNo, vocabulary)
+	vocabulary.UpdatedBy = ActorFrom(ctx)
+
+	var updated int64
+	err := u.write(ctx, func(ctx context.Context) error {
+		var err error
+		updated, err = u.Repository.Update(ctx, vocabularyNo, vocabulary)
+		if err != nil {
+			return err
+		}
+		return u.record(ctx, VocabularyUpdated, updated, vocabulary)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return updated, nil
 }
 
 func (u *VocabularyUsecase) DeleteVocabulary(ctx context.Context, vocabularyNo int64) (int64, error) {
-	return u.Repository.Delete(ctx, vocabularyNo)
+	var rowsAffected int64
+	err := u.write(ctx, func(ctx context.Context) error {
+		var err error
+		rowsAffected, err = u.Repository.Delete(ctx, vocabularyNo)
+		if err != nil {
+			return err
+		}
+		return u.record(ctx, VocabularyDeleted, vocabularyNo, nil)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return rowsAffected, nil
 }
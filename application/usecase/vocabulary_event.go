@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/domain"
+)
+
+// ErrEventsDisabled is returned for a subscription when no broker is configured
+var ErrEventsDisabled = errors.New("vocabulary events are disabled")
+
+// VocabularyEventType is the kind of change a VocabularyEvent reports
+type VocabularyEventType string
+
+const (
+	VocabularyCreated VocabularyEventType = "vocabulary.created"
+	VocabularyUpdated VocabularyEventType = "vocabulary.updated"
+	VocabularyDeleted VocabularyEventType = "vocabulary.deleted"
+)
+
+// VocabularyEventTypes lists every VocabularyEventType
+var VocabularyEventTypes = []VocabularyEventType{VocabularyCreated, VocabularyUpdated, VocabularyDeleted}
+
+// VocabularyEvent reports a committed change of a vocabulary
+type VocabularyEvent struct {
+	// Assigned by the broker when the event is published
+	ID string
+
+	Type         VocabularyEventType
+	VocabularyNo int64
+
+	// Content written by a create or an update. nil for a delete.
+	Vocabulary *domain.Vocabulary
+
+	// Client that made the change
+	Actor string
+
+	OccurredAt time.Time
+}
+
+// VocabularySubscription delivers the events published after it was opened
+type VocabularySubscription struct {
+	// Events published after the requested event ID, oldest first
+	Replay []VocabularyEvent
+
+	// false when some of the events after the requested event ID are no longer kept,
+	// in which case the subscriber has to reload the vocabularies
+	Complete bool
+
+	// ID of the latest event published before the subscription. Empty when there is none.
+	LastEventID string
+
+	// Events published from now on. It is closed when the subscriber falls behind or the broker is closed.
+	Events <-chan VocabularyEvent
+
+	// Stops the delivery and releases the subscription
+	Unsubscribe func()
+}
+
+// VocabularyEventBroker fans out the events to the subscribers.
+// Publish must not block on slow subscribers.
+type VocabularyEventBroker interface {
+	Publish(event VocabularyEvent)
+
+	// Subscribe replays the events after lastEventID, or none when it is empty
+	Subscribe(lastEventID string) *VocabularySubscription
+}
+
+type pendingEventsKey struct{}
+
+// withPendingEvents returns a context whose events are held in pending instead of being published,
+// so that the events of a transaction are published only after it commits
+func withPendingEvents(ctx context.Context, pending *[]VocabularyEvent) context.Context {
+	return context.WithValue(ctx, pendingEventsKey{}, pending)
+}
+
+// write runs fn, which makes a change and records it. With an outbox, fn runs in a transaction
+// so that the change and its record are committed together.
+// The events of fn are published after the transaction commits.
+func (u *VocabularyUsecase) write(ctx context.Context, fn func(ctx context.Context) error) error {
+	if u.Outbox == nil {
+		return fn(ctx)
+	}
+
+	// Join the unit of work of the caller, which publishes the events when it commits
+	if _, ok := ctx.Value(pendingEventsKey{}).(*[]VocabularyEvent); ok {
+		return u.TxManager.WithinTx(ctx, TxOptions{}, fn)
+	}
+
+	var events []VocabularyEvent
+	err := u.TxManager.WithinTx(ctx, TxOptions{}, func(ctx context.Context) error {
+		// Start over when the transaction is retried
+		events = nil
+		return fn(withPendingEvents(ctx, &events))
+	})
+	if err != nil {
+		return err
+	}
+
+	u.publishEvents(ctx, events)
+	return nil
+}
+
+// record adds a change to the outbox and publishes it, or holds it until the transaction of ctx commits
+func (u *VocabularyUsecase) record(
+	ctx context.Context, eventType VocabularyEventType, vocabularyNo int64, vocabulary *domain.Vocabulary,
+) error {
+	event := VocabularyEvent{
+		Type:         eventType,
+		VocabularyNo: vocabularyNo,
+		Actor:        ActorFrom(ctx),
+		OccurredAt:   time.Now(),
+	}
+
+	// Keep the content as written even if the caller reuses the vocabulary
+	if vocabulary != nil {
+		written := *vocabulary
+		written.VocabularyNo = vocabularyNo
+		event.Vocabulary = &written
+	}
+
+	if u.Outbox != nil {
+		if err := u.Outbox.EnqueueEvent(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	u.publishEvents(ctx, []VocabularyEvent{event})
+	return nil
+}
+
+func (u *VocabularyUsecase) publishEvents(ctx context.Context, events []VocabularyEvent) {
+	if u.Events == nil {
+		return
+	}
+
+	if pending, ok := ctx.Value(pendingEventsKey{}).(*[]VocabularyEvent); ok {
+		*pending = append(*pending, events...)
+		return
+	}
+
+	for _, event := range events {
+		u.Events.Publish(event)
+	}
+}
+
+// SubscribeVocabularyEvents streams the changes of the vocabularies, resuming after lastEventID when it is set
+func (u *VocabularyUsecase) SubscribeVocabularyEvents(lastEventID string) (*VocabularySubscription, error) {
+	if u.Events == nil {
+		return nil, ErrEventsDisabled
+	}
+	return u.Events.Subscribe(lastEventID), nil
+}
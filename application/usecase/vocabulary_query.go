@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// VocabularySortField is a field the vocabulary list can be sorted by
+type VocabularySortField string
+
+const (
+	SortByVocabularyNo VocabularySortField = "vocabulary_no"
+	SortByTitle        VocabularySortField = "title"
+	SortByCreatedAt    VocabularySortField = "created_at"
+	SortByUpdatedAt    VocabularySortField = "updated_at"
+)
+
+var vocabularySortFields = []VocabularySortField{SortByVocabularyNo, SortByTitle, SortByCreatedAt, SortByUpdatedAt}
+
+type VocabularySort struct {
+	Field      VocabularySortField
+	Descending bool
+}
+
+// VocabularyQuery filters and sorts the vocabulary list.
+// The zero value selects every vocabulary in vocabulary_no order.
+type VocabularyQuery struct {
+	// Prefix of the title. The case of ASCII letters is ignored; other letters match exactly.
+	TitlePrefix string
+
+	// Term the sentence contains, ignoring the case of ASCII letters only
+	SentenceContains string
+
+	// Time ranges, each including its start and excluding its end. A zero time leaves that side open.
+	CreatedFrom  time.Time
+	CreatedUntil time.Time
+	UpdatedFrom  time.Time
+	UpdatedUntil time.Time
+
+	// Sort keys in priority order. Ties are broken by vocabulary_no in ascending order.
+	// Titles are sorted by their bytes, so upper case comes before lower case and ASCII before the rest.
+	Sort []VocabularySort
+}
+
+// IsZero reports whether the query selects every vocabulary in the default order
+func (q VocabularyQuery) IsZero() bool {
+	return q.TitlePrefix == "" && q.SentenceContains == "" &&
+		q.CreatedFrom.IsZero() && q.CreatedUntil.IsZero() && q.UpdatedFrom.IsZero() && q.UpdatedUntil.IsZero() &&
+		len(q.Sort) == 0
+}
+
+// ParseVocabularySort parses a comma-separated list of fields, each descending when prefixed with "-",
+// for example "title,-vocabulary_no"
+func ParseVocabularySort(value string) ([]VocabularySort, error) {
+	var sorts []VocabularySort
+	seen := make(map[VocabularySortField]bool)
+
+	for _, key := range strings.Split(value, ",") {
+		name, descending := strings.CutPrefix(strings.TrimSpace(key), "-")
+
+		field := VocabularySortField(name)
+		if !slices.Contains(vocabularySortFields, field) {
+			return nil, fmt.Errorf("unknown sort field %q", name)
+		}
+		if seen[field] {
+			return nil, fmt.Errorf("duplicate sort field %q", name)
+		}
+		seen[field] = true
+
+		sorts = append(sorts, VocabularySort{Field: field, Descending: descending})
+	}
+
+	return sorts, nil
+}
@@ -9,9 +9,13 @@ import (
 type VocabularyRepository interface {
 	Insert(ctx context.Context, vocabulary *domain.Vocabulary) (int64, error)
 
+	// InsertAll inserts every vocabulary or none of them, sets their VocabularyNo and returns the number of inserted rows
+	InsertAll(ctx context.Context, vocabularies []*domain.Vocabulary) (int64, error)
+
 	SelectByVocabularyNo(ctx context.Context, vocabularyNo int64) (*domain.Vocabulary, error)
 
-	SelectAll(ctx context.Context) ([]*domain.Vocabulary, error)
+	// SelectAll returns the vocabularies matching the query in its order
+	SelectAll(ctx context.Context, query VocabularyQuery) ([]*domain.Vocabulary, error)
 
 	Update(ctx context.Context, vocabularyNo int64, vocabulary *domain.Vocabulary) (int64, error)
 
@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/domain"
+)
+
+// ErrWebhooksDisabled is returned when the backend keeps no webhooks
+var ErrWebhooksDisabled = errors.New("webhooks are not supported by the backend")
+
+// Kind of the job posting an event to a webhook
+const DeliverWebhookKind = "webhook.deliver"
+
+// DeliverWebhookArgs are the arguments of a DeliverWebhookKind job: the event as it was written, and its webhook
+type DeliverWebhookArgs struct {
+	WebhookID    int64               `json:"webhook_id"`
+	Type         VocabularyEventType `json:"type"`
+	VocabularyNo int64               `json:"vocabulary_no"`
+
+	// nil for a delete
+	Vocabulary *DeliverWebhookContent `json:"vocabulary,omitempty"`
+
+	Actor      string    `json:"actor"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+type DeliverWebhookContent struct {
+	Title     string `json:"title"`
+	Meaning   string `json:"meaning"`
+	Sentence  string `json:"sentence"`
+	CreatedBy string `json:"created_by"`
+	UpdatedBy string `json:"updated_by"`
+}
+
+// WebhookSender posts a delivery to its webhook
+type WebhookSender interface {
+	// Send returns the status code of the response, or 0 when none was received.
+	// The error is nil only for a 2xx response.
+	Send(ctx context.Context, delivery *WebhookDelivery) (int, error)
+}
+
+type WebhookUsecase struct {
+	// nil when the backend keeps no webhooks
+	Repository WebhookRepository
+
+	// Queue of the deliveries. nil when the backend keeps no jobs.
+	Jobs JobQueue
+
+	Sender WebhookSender
+	Retry  RetryPolicy
+}
+
+func NewWebhookUsecase(repository WebhookRepository, jobs JobQueue, sender WebhookSender, retry RetryPolicy) *WebhookUsecase {
+	return &WebhookUsecase{
+		Repository: repository,
+		Jobs:       jobs,
+		Sender:     sender,
+		Retry:      retry,
+	}
+}
+
+func (u *WebhookUsecase) AddWebhook(ctx context.Context, webhook *domain.Webhook) (int64, error) {
+	if u.Repository == nil {
+		return 0, ErrWebhooksDisabled
+	}
+	return u.Repository.InsertWebhook(ctx, webhook)
+}
+
+func (u *WebhookUsecase) FetchWebhookByID(ctx context.Context, webhookID int64) (*domain.Webhook, error) {
+	if u.Repository == nil {
+		return nil, ErrWebhooksDisabled
+	}
+	return u.Repository.SelectWebhookByID(ctx, webhookID)
+}
+
+func (u *WebhookUsecase) FetchWebhookList(ctx context.Context) ([]*domain.Webhook, error) {
+	if u.Repository == nil {
+		return nil, ErrWebhooksDisabled
+	}
+	return u.Repository.SelectAllWebhooks(ctx)
+}
+
+func (u *WebhookUsecase) DeleteWebhook(ctx context.Context, webhookID int64) (int64, error) {
+	if u.Repository == nil {
+		return 0, ErrWebhooksDisabled
+	}
+	return u.Repository.DeleteWebhook(ctx, webhookID)
+}
+
+// FetchDeliveryList returns the latest deliveries of the webhook, newest first
+func (u *WebhookUsecase) FetchDeliveryList(
+	ctx context.Context, webhookID int64, status WebhookDeliveryStatus, limit int,
+) ([]*WebhookDelivery, error) {
+	if u.Repository == nil {
+		return nil, ErrWebhooksDisabled
+	}
+
+	// Tell an unknown webhook from one without deliveries
+	if _, err := u.Repository.SelectWebhookByID(ctx, webhookID); err != nil {
+		return nil, err
+	}
+	return u.Repository.SelectDeliveries(ctx, webhookID, status, limit)
+}
+
+// EnqueueEvent adds a delivery job for every webhook subscribed to the type of the event.
+// In the transaction of ctx, the jobs are committed with the change.
+func (u *WebhookUsecase) EnqueueEvent(ctx context.Context, event VocabularyEvent) error {
+	if u.Repository == nil || u.Jobs == nil {
+		return ErrWebhooksDisabled
+	}
+
+	webhooks, err := u.Repository.SelectAllWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, webhook := range webhooks {
+		if !slices.Contains(webhook.EventTypes, string(event.Type)) {
+			continue
+		}
+
+		job, err := NewJob(DeliverWebhookKind, NewDeliverWebhookArgs(webhook.WebhookID, event))
+		if err != nil {
+			return err
+		}
+		if _, err := u.Jobs.EnqueueJob(ctx, job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// JobHandler returns the handler of the delivery jobs, retried with the policy of the webhooks
+func (u *WebhookUsecase) JobHandler() JobHandler {
+	handler := HandleJob(DeliverWebhookKind, u.DeliverWebhook)
+	handler.Retry = u.Retry
+	return handler
+}
+
+// DeliverWebhook posts the event of a delivery job to its webhook. The ID of the job identifies the delivery.
+func (u *WebhookUsecase) DeliverWebhook(ctx context.Context, args DeliverWebhookArgs) error {
+	if u.Repository == nil {
+		return ErrWebhooksDisabled
+	}
+
+	// A deleted webhook takes its jobs with it, so a missing one is retried
+	webhook, err := u.Repository.SelectWebhookByID(ctx, args.WebhookID)
+	if err != nil {
+		return err
+	}
+
+	delivery := &WebhookDelivery{
+		DeliveryID: JobIDFrom(ctx),
+		WebhookID:  webhook.WebhookID,
+		Event:      args.VocabularyEvent(),
+		Webhook:    webhook,
+	}
+	if _, err := u.Sender.Send(ctx, delivery); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "the webhook delivery succeeded", slog.Int64("deliveryID", delivery.DeliveryID))
+	return nil
+}
+
+// NewDeliverWebhookArgs returns the arguments of the delivery of the event to the webhook
+func NewDeliverWebhookArgs(webhookID int64, event VocabularyEvent) DeliverWebhookArgs {
+	args := DeliverWebhookArgs{
+		WebhookID:    webhookID,
+		Type:         event.Type,
+		VocabularyNo: event.VocabularyNo,
+		Actor:        event.Actor,
+		OccurredAt:   event.OccurredAt,
+	}
+
+	if event.Vocabulary != nil {
+		args.Vocabulary = &DeliverWebhookContent{
+			Title:     event.Vocabulary.Title,
+			Meaning:   event.Vocabulary.Meaning,
+			Sentence:  event.Vocabulary.Sentence,
+			CreatedBy: event.Vocabulary.CreatedBy,
+			UpdatedBy: event.Vocabulary.UpdatedBy,
+		}
+	}
+	return args
+}
+
+// VocabularyEvent returns the event of the delivery
+func (a DeliverWebhookArgs) VocabularyEvent() VocabularyEvent {
+	event := VocabularyEvent{
+		Type:         a.Type,
+		VocabularyNo: a.VocabularyNo,
+		Actor:        a.Actor,
+		OccurredAt:   a.OccurredAt,
+	}
+
+	if content := a.Vocabulary; content != nil {
+		event.Vocabulary = &domain.Vocabulary{
+			VocabularyNo: a.VocabularyNo,
+			Title:        content.Title,
+			Meaning:      content.Meaning,
+			Sentence:     content.Sentence,
+			CreatedBy:    content.CreatedBy,
+			UpdatedBy:    content.UpdatedBy,
+		}
+	}
+	return event
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/domain"
+)
+
+// WebhookDeliveryStatus is the state of a WebhookDelivery
+type WebhookDeliveryStatus string
+
+const (
+	// Waiting for its next attempt
+	DeliveryPending WebhookDeliveryStatus = "pending"
+
+	// Accepted by the endpoint with a 2xx response
+	DeliveryDelivered WebhookDeliveryStatus = "delivered"
+
+	// Given up after too many failed attempts. It stays in the delivery log as a dead letter.
+	DeliveryDead WebhookDeliveryStatus = "dead"
+)
+
+// WebhookDelivery is an event posted to a webhook by a job of DeliverWebhookKind
+type WebhookDelivery struct {
+	// ID of the job
+	DeliveryID int64
+	WebhookID  int64
+	Event      VocabularyEvent
+
+	Status WebhookDeliveryStatus
+
+	// Number of attempts started so far
+	Attempts int
+
+	// Time the delivery is due for its next attempt
+	NextAttemptAt time.Time
+
+	// Outcome of the last attempt
+	LastError string
+
+	CreatedAt time.Time
+
+	// Zero until the delivery succeeds
+	DeliveredAt time.Time
+
+	// Target of the delivery. Only set while it is sent.
+	Webhook *domain.Webhook
+}
+
+// VocabularyOutbox records the events in the transaction of the change, so that they are delivered
+// if and only if the change is committed
+type VocabularyOutbox interface {
+	// EnqueueEvent adds a delivery of the event for every webhook subscribed to its type
+	EnqueueEvent(ctx context.Context, event VocabularyEvent) error
+}
+
+type WebhookRepository interface {
+	InsertWebhook(ctx context.Context, webhook *domain.Webhook) (int64, error)
+
+	SelectWebhookByID(ctx context.Context, webhookID int64) (*domain.Webhook, error)
+
+	SelectAllWebhooks(ctx context.Context) ([]*domain.Webhook, error)
+
+	// DeleteWebhook deletes the webhook with its deliveries
+	DeleteWebhook(ctx context.Context, webhookID int64) (int64, error)
+
+	// SelectDeliveries returns the latest deliveries of the webhook, newest first.
+	// An empty status selects every status.
+	SelectDeliveries(ctx context.Context, webhookID int64, status WebhookDeliveryStatus, limit int) ([]*WebhookDelivery, error)
+}
@@ -0,0 +1,298 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db/repository/memory"
+)
+
+// recordingOutbox keeps the enqueued events, or fails with err
+type recordingOutbox struct {
+	events []usecase.VocabularyEvent
+	err    error
+}
+
+func (o *recordingOutbox) EnqueueEvent(_ context.Context, event usecase.VocabularyEvent) error {
+	if o.err != nil {
+		return o.err
+	}
+	o.events = append(o.events, event)
+	return nil
+}
+
+func TestVocabularyOutbox(t *testing.T) {
+	ctx := usecase.WithActor(context.Background(), "alice")
+	repository := memory.NewVocabularyRepository()
+	outbox := &recordingOutbox{}
+	broker := &recordingBroker{}
+	u := usecase.NewVocabularyUsecase(repository, memory.NewTxManager(repository), broker, outbox)
+
+	vocabularyNo, err := u.AddVocabulary(ctx, &domain.Vocabulary{Title: "apple", Meaning: "fruit", Sentence: "s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := u.UpdateVocabulary(ctx, vocabularyNo, &domain.Vocabulary{Title: "apple", Meaning: "red fruit", Sentence: "s"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := u.DeleteVocabulary(ctx, vocabularyNo); err != nil {
+		t.Fatal(err)
+	}
+
+	// A failed change enqueues nothing
+	if _, err := u.DeleteVocabulary(ctx, vocabularyNo); !errors.Is(err, domain.ErrVocabularyNotFound) {
+		t.Fatalf("DeleteVocabulary() = %v, want ErrVocabularyNotFound", err)
+	}
+
+	var got []string
+	for _, event := range outbox.events {
+		got = append(got, fmt.Sprintf("%s %d by %s", event.Type, event.VocabularyNo, event.Actor))
+	}
+	want := []string{"vocabulary.created 1 by alice", "vocabulary.updated 1 by alice", "vocabulary.deleted 1 by alice"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("enqueued = %v, want %v", got, want)
+	}
+	if len(broker.events) != len(want) {
+		t.Errorf("published %d events, want %d", len(broker.events), len(want))
+	}
+}
+
+func TestVocabularyOutboxFailure(t *testing.T) {
+	ctx := context.Background()
+	repository := memory.NewVocabularyRepository()
+	broker := &recordingBroker{}
+	u := usecase.NewVocabularyUsecase(repository, memory.NewTxManager(repository), broker, &recordingOutbox{err: errors.New("outbox is down")})
+
+	if _, err := u.AddVocabulary(ctx, &domain.Vocabulary{Title: "apple", Meaning: "fruit", Sentence: "s"}); err == nil {
+		t.Fatal("AddVocabulary succeeded while the outbox is down")
+	}
+
+	// The change is rolled back with the event
+	vocabularyList, err := u.FetchVocabularyList(ctx, usecase.VocabularyQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vocabularyList) != 0 {
+		t.Errorf("%d vocabularies were kept, want 0", len(vocabularyList))
+	}
+	if len(broker.events) != 0 {
+		t.Errorf("%d events were published, want 0", len(broker.events))
+	}
+}
+
+func TestAddVocabulariesQueuesDeliveries(t *testing.T) {
+	ctx := usecase.WithActor(context.Background(), "importer")
+	repository := memory.NewVocabularyRepository()
+	jobs := &recordingJobQueue{}
+	outbox := usecase.NewWebhookUsecase(
+		&fakeWebhookRepository{webhooks: []*domain.Webhook{{WebhookID: 1, EventTypes: []string{"vocabulary.created"}}}},
+		jobs, &fakeSender{}, usecase.RetryPolicy{},
+	)
+	broker := &recordingBroker{}
+	u := usecase.NewVocabularyUsecase(repository, memory.NewTxManager(repository), broker, outbox)
+
+	// An import adds the vocabularies in bulk
+	inserted, err := u.AddVocabularies(ctx, []*domain.Vocabulary{
+		{Title: "apple", Meaning: "fruit", Sentence: "s"},
+		{Title: "pear", Meaning: "fruit", Sentence: "s"},
+	})
+	if err != nil || inserted != 2 {
+		t.Fatalf("AddVocabularies() = %d, %v, want 2", inserted, err)
+	}
+
+	// A failed import queues nothing
+	if _, err := u.AddVocabularies(ctx, []*domain.Vocabulary{
+		{Title: "grape", Meaning: "fruit", Sentence: "s"},
+		{Title: "apple", Meaning: "fruit", Sentence: "s"},
+	}); !errors.Is(err, domain.ErrVocabularyDuplicate) {
+		t.Fatalf("AddVocabularies() = %v, want ErrVocabularyDuplicate", err)
+	}
+
+	var got []string
+	for _, job := range jobs.jobs {
+		var args usecase.DeliverWebhookArgs
+		if err := json.Unmarshal(job.Payload, &args); err != nil {
+			t.Fatal(err)
+		}
+		event := args.VocabularyEvent()
+		got = append(got, fmt.Sprintf("%s %d %s by %s", event.Type, event.VocabularyNo, event.Vocabulary.Title, event.Actor))
+	}
+	want := []string{"vocabulary.created 1 apple by importer", "vocabulary.created 2 pear by importer"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("queued = %v, want %v", got, want)
+	}
+	if len(broker.events) != len(want) {
+		t.Errorf("published %d events, want %d", len(broker.events), len(want))
+	}
+}
+
+// fakeWebhookRepository keeps the webhooks by their IDs
+type fakeWebhookRepository struct {
+	usecase.WebhookRepository
+
+	webhooks []*domain.Webhook
+}
+
+func (r *fakeWebhookRepository) SelectWebhookByID(_ context.Context, webhookID int64) (*domain.Webhook, error) {
+	for _, webhook := range r.webhooks {
+		if webhook.WebhookID == webhookID {
+			return webhook, nil
+		}
+	}
+	return nil, domain.ErrWebhookNotFound
+}
+
+func (r *fakeWebhookRepository) SelectAllWebhooks(_ context.Context) ([]*domain.Webhook, error) {
+	return r.webhooks, nil
+}
+
+// recordingJobQueue keeps the enqueued jobs
+type recordingJobQueue struct {
+	jobs []*usecase.Job
+}
+
+func (q *recordingJobQueue) EnqueueJob(_ context.Context, job *usecase.Job) (int64, error) {
+	q.jobs = append(q.jobs, job)
+	return int64(len(q.jobs)), nil
+}
+
+// fakeSender responds with the status codes keyed by the delivery IDs, and keeps the sent deliveries.
+// 0 fails without a response.
+type fakeSender struct {
+	mu         sync.Mutex
+	statusCode map[int64]int
+	sent       []usecase.WebhookDelivery
+}
+
+func (s *fakeSender) Send(_ context.Context, delivery *usecase.WebhookDelivery) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, *delivery)
+
+	statusCode := s.statusCode[delivery.DeliveryID]
+	switch {
+	case statusCode == 0:
+		return 0, errors.New("connection refused")
+	case statusCode > 299:
+		return statusCode, fmt.Errorf("the webhook responded with status %d", statusCode)
+	}
+	return statusCode, nil
+}
+
+func TestEnqueueEvent(t *testing.T) {
+	repository := &fakeWebhookRepository{
+		webhooks: []*domain.Webhook{
+			{WebhookID: 1, EventTypes: []string{"vocabulary.created", "vocabulary.deleted"}},
+			{WebhookID: 2, EventTypes: []string{"vocabulary.updated"}},
+			{WebhookID: 3, EventTypes: []string{"vocabulary.created"}},
+		},
+	}
+	jobs := &recordingJobQueue{}
+	u := usecase.NewWebhookUsecase(repository, jobs, &fakeSender{}, usecase.RetryPolicy{})
+
+	event := usecase.VocabularyEvent{
+		Type:         usecase.VocabularyCreated,
+		VocabularyNo: 7,
+		Vocabulary:   &domain.Vocabulary{VocabularyNo: 7, Title: "apple", Meaning: "fruit", Sentence: "s", CreatedBy: "alice", UpdatedBy: "alice"},
+		Actor:        "alice",
+		OccurredAt:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	if err := u.EnqueueEvent(context.Background(), event); err != nil {
+		t.Fatalf("EnqueueEvent returned an error: %v", err)
+	}
+
+	// One delivery job for each subscribed webhook, which carries the whole event
+	var webhookIDs []int64
+	for _, job := range jobs.jobs {
+		if job.Kind != usecase.DeliverWebhookKind {
+			t.Errorf("kind = %q, want %q", job.Kind, usecase.DeliverWebhookKind)
+		}
+
+		var args usecase.DeliverWebhookArgs
+		if err := json.Unmarshal(job.Payload, &args); err != nil {
+			t.Fatal(err)
+		}
+		if got := args.VocabularyEvent(); !reflect.DeepEqual(got, event) {
+			t.Errorf("event of the job = %+v, want %+v", got, event)
+		}
+		webhookIDs = append(webhookIDs, args.WebhookID)
+	}
+	if !slices.Equal(webhookIDs, []int64{1, 3}) {
+		t.Errorf("enqueued for the webhooks %v, want [1 3]", webhookIDs)
+	}
+}
+
+func TestDeliverWebhookJobs(t *testing.T) {
+	webhookRepository := &fakeWebhookRepository{webhooks: []*domain.Webhook{{WebhookID: 1, URL: "https://example.com/hook"}}}
+	sender := &fakeSender{statusCode: map[int64]int{1: http.StatusNoContent, 2: http.StatusInternalServerError}}
+	webhookUsecase := usecase.NewWebhookUsecase(
+		webhookRepository, nil, sender, usecase.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Minute, MaxBackoff: time.Hour},
+	)
+
+	event := usecase.VocabularyEvent{Type: usecase.VocabularyDeleted, VocabularyNo: 7}
+	jobRepository := &fakeJobRepository{
+		claimable: []*usecase.Job{
+			newJob(t, 1, usecase.DeliverWebhookKind, usecase.NewDeliverWebhookArgs(1, event), 0),
+			newJob(t, 2, usecase.DeliverWebhookKind, usecase.NewDeliverWebhookArgs(1, event), 0),
+			newJob(t, 3, usecase.DeliverWebhookKind, usecase.NewDeliverWebhookArgs(1, event), 2),
+			newJob(t, 4, usecase.DeliverWebhookKind, usecase.NewDeliverWebhookArgs(2, event), 0),
+		},
+		updated: map[int64]usecase.Job{},
+	}
+
+	// The retry policy of the webhooks replaces the one of the jobs
+	jobUsecase := usecase.NewJobUsecase(jobRepository, usecase.RetryPolicy{MaxAttempts: 10}, time.Minute)
+	jobUsecase.Register(webhookUsecase.JobHandler())
+
+	if _, err := jobUsecase.RunPending(context.Background(), 10); err != nil {
+		t.Fatalf("RunPending returned an error: %v", err)
+	}
+
+	// The job ID identifies the delivery
+	var deliveryIDs []int64
+	for _, delivery := range sender.sent {
+		if delivery.Webhook == nil || delivery.Webhook.URL != "https://example.com/hook" || delivery.Event.VocabularyNo != 7 {
+			t.Errorf("sent delivery %d = %+v", delivery.DeliveryID, delivery)
+		}
+		deliveryIDs = append(deliveryIDs, delivery.DeliveryID)
+	}
+	if slices.Sort(deliveryIDs); !slices.Equal(deliveryIDs, []int64{1, 2, 3}) {
+		t.Errorf("sent the deliveries %v, want [1 2 3]", deliveryIDs)
+	}
+
+	if delivered := jobRepository.updated[1]; delivered.Status != usecase.JobSucceeded {
+		t.Errorf("job 1 = %+v, want succeeded", delivered)
+	}
+	if retried := jobRepository.updated[2]; retried.Status != usecase.JobPending || retried.LastError == "" {
+		t.Errorf("job 2 = %+v, want pending with the error", retried)
+	}
+	if dead := jobRepository.updated[3]; dead.Status != usecase.JobDead || dead.Attempts != 3 {
+		t.Errorf("job 3 = %+v, want dead after 3 attempts", dead)
+	}
+
+	// An unknown webhook is retried, since a replica may not have caught up
+	if unknown := jobRepository.updated[4]; unknown.Status != usecase.JobPending {
+		t.Errorf("job 4 = %+v, want pending", unknown)
+	}
+}
+
+func TestWebhooksDisabled(t *testing.T) {
+	u := usecase.NewWebhookUsecase(nil, nil, nil, usecase.RetryPolicy{})
+
+	if _, err := u.AddWebhook(context.Background(), &domain.Webhook{}); !errors.Is(err, usecase.ErrWebhooksDisabled) {
+		t.Errorf("AddWebhook() = %v, want ErrWebhooksDisabled", err)
+	}
+	if err := u.EnqueueEvent(context.Background(), usecase.VocabularyEvent{}); !errors.Is(err, usecase.ErrWebhooksDisabled) {
+		t.Errorf("EnqueueEvent() = %v, want ErrWebhooksDisabled", err)
+	}
+}
@@ -1,31 +1,385 @@
 package config
 
 import (
-	"context"
-	"log/slog"
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"time"
+)
 
-	"github.com/caarlos0/env"
+// Supported values of DB_DRIVER
+const (
+	DBDriverPostgres = "postgres"
+	DBDriverSQLite   = "sqlite"
+	DBDriverMemory   = "memory"
 )
 
+// Config is built from the layers described in Load.
+// Every field can be set by the environment variable in its env tag, by the same name
+// in lower case in the config file, or by the flag "--" + the name in kebab case.
+// Fields tagged secret are redacted by Redacted.
 type Config struct {
+	// Repository backend: postgres, sqlite or memory
+	DBDriver string `env:"DB_DRIVER" envDefault:"postgres"`
+
+	// SQLite database file, used when DB_DRIVER is sqlite
+	SQLitePath string `env:"SQLITE_PATH" envDefault:"vocabulary.db"`
+
+	// Full PostgreSQL connection URL. Overrides the POSTGRES_* connection info when set.
+	DatabaseURL string `env:"DATABASE_URL" secret:"true"`
+
+	// PostgreSQL read replica URL. Queries go to the replica when set.
+	DatabaseReplicaURL string `env:"DATABASE_REPLICA_URL" secret:"true"`
+
+	// How often to check the replica. Reads fall back to the primary while it is unhealthy.
+	DBReplicaHealthInterval time.Duration `env:"DB_REPLICA_HEALTH_INTERVAL" envDefault:"5s"`
+
+	// Send the reads of a request to the primary after the request writes. An HTTP client that wrote also
+	// reads from the primary for DB_READ_YOUR_WRITES_WINDOW, which must exceed the replication lag.
+	DBReadYourWrites       bool          `env:"DB_READ_YOUR_WRITES" envDefault:"true"`
+	DBReadYourWritesWindow time.Duration `env:"DB_READ_YOUR_WRITES_WINDOW" envDefault:"5s"`
+
 	// DB connection info
 	DBHost     string `env:"POSTGRES_HOST"`
-	DBPort     string `env:"POSTGRES_PORT"`
+	DBPort     string `env:"POSTGRES_PORT" envDefault:"5432"`
 	DBUser     string `env:"POSTGRES_USER"`
-	DBPassword string `env:"POSTGRES_PASSWORD"`
+	DBPassword string `env:"POSTGRES_PASSWORD" secret:"true"`
 	DBName     string `env:"POSTGRES_DB"`
-	DBSslmode  string `env:"POSTGRES_SSLMODE"`
+	DBSslmode  string `env:"POSTGRES_SSLMODE" envDefault:"disable"`
+
+	// DB connection pool settings. 0 keeps the pgxpool default.
+	DBMaxOpenConns    int           `env:"DB_MAX_OPEN_CONNS" envDefault:"10"`
+	DBMinIdleConns    int           `env:"DB_MIN_IDLE_CONNS" envDefault:"2"`
+	DBConnMaxLifetime time.Duration `env:"DB_CONN_MAX_LIFETIME" envDefault:"30m"`
+	DBConnMaxIdleTime time.Duration `env:"DB_CONN_MAX_IDLE_TIME" envDefault:"5m"`
+
+	// How long to keep retrying while PostgreSQL is still starting up
+	DBConnectTimeout time.Duration `env:"DB_CONNECT_TIMEOUT" envDefault:"30s"`
+
+	// Statement timeout applied to each connection. 0 disables it.
+	DBStatementTimeout time.Duration `env:"DB_STATEMENT_TIMEOUT" envDefault:"30s"`
+
+	// Default isolation level of transactions: read_committed, repeatable_read or serializable
+	DBTxIsolation string `env:"DB_TX_ISOLATION" envDefault:"read_committed"`
+
+	// How many times a transaction is retried after a serialization failure or a deadlock
+	DBTxMaxRetries int `env:"DB_TX_MAX_RETRIES" envDefault:"3"`
+
+	// In-process LRU cache of the repository queries. Disable it when other processes write to the database.
+	CacheEnabled bool          `env:"CACHE_ENABLED" envDefault:"false"`
+	CacheSize    int           `env:"CACHE_SIZE" envDefault:"1000"`
+	CacheTTL     time.Duration `env:"CACHE_TTL" envDefault:"30s"`
 
 	// Application port number
-	Port string `env:"APP_PORT"`
+	Port string `env:"APP_PORT" envDefault:"8080"`
+
+	// gRPC VocabularyService, served on its own port with the TLS settings of the http server.
+	// Off by default since it has neither the rate limits nor the handler timeout of the http server.
+	GRPCEnabled bool   `env:"GRPC_ENABLED" envDefault:"false"`
+	GRPCPort    string `env:"GRPC_PORT" envDefault:"9090"`
+
+	// Serve HTTPS when both files are set. They are reloaded on SIGHUP or when they change.
+	TLSCertFile string `env:"TLS_CERT_FILE"`
+	TLSKeyFile  string `env:"TLS_KEY_FILE"`
+
+	// Require client certificates signed by this CA bundle (mTLS)
+	TLSClientCAFile string `env:"TLS_CLIENT_CA_FILE"`
+
+	// HTTP server timeouts
+	ReadHeaderTimeout time.Duration `env:"HTTP_READ_HEADER_TIMEOUT" envDefault:"5s"`
+	ReadTimeout       time.Duration `env:"HTTP_READ_TIMEOUT" envDefault:"15s"`
+	WriteTimeout      time.Duration `env:"HTTP_WRITE_TIMEOUT" envDefault:"30s"`
+	IdleTimeout       time.Duration `env:"HTTP_IDLE_TIMEOUT" envDefault:"60s"`
+	ShutdownTimeout   time.Duration `env:"HTTP_SHUTDOWN_TIMEOUT" envDefault:"10s"`
+
+	// Time limit of a handler. The request context is cancelled when it is exceeded.
+	HandlerTimeout time.Duration `env:"HTTP_HANDLER_TIMEOUT" envDefault:"10s"`
+
+	// Maximum size of a request body in bytes
+	MaxBodyBytes int64 `env:"MAX_REQUEST_BODY_BYTES" envDefault:"1048576"`
+
+	// Maximum number of operations in a POST /api/vocabularies:batch request
+	BatchMaxOperations int `env:"BATCH_MAX_OPERATIONS" envDefault:"100"`
+
+	// Token bucket rate limits per client, separate for read (GET) and write routes
+	RateLimitEnabled    bool    `env:"RATE_LIMIT_ENABLED" envDefault:"true"`
+	RateLimitReadRate   float64 `env:"RATE_LIMIT_READ_RATE" envDefault:"10"`
+	RateLimitReadBurst  int     `env:"RATE_LIMIT_READ_BURST" envDefault:"20"`
+	RateLimitWriteRate  float64 `env:"RATE_LIMIT_WRITE_RATE" envDefault:"2"`
+	RateLimitWriteBurst int     `env:"RATE_LIMIT_WRITE_BURST" envDefault:"5"`
+
+	// Replay the response of a POST request retried with the same Idempotency-Key within the TTL
+	IdempotencyEnabled bool          `env:"IDEMPOTENCY_ENABLED" envDefault:"true"`
+	IdempotencyKeyTTL  time.Duration `env:"IDEMPOTENCY_KEY_TTL" envDefault:"24h"`
+
+	// Server-sent events of GET /api/vocabularies/events. The latest EVENTS_REPLAY_SIZE events are kept
+	// for the clients resuming with Last-Event-ID, and a client falling EVENTS_SUBSCRIBER_BUFFER events
+	// behind is disconnected.
+	EventsReplaySize        int           `env:"EVENTS_REPLAY_SIZE" envDefault:"1000"`
+	EventsSubscriberBuffer  int           `env:"EVENTS_SUBSCRIBER_BUFFER" envDefault:"64"`
+	EventsHeartbeatInterval time.Duration `env:"EVENTS_HEARTBEAT_INTERVAL" envDefault:"15s"`
+
+	// Outbound webhooks, delivered as background jobs with the PostgreSQL backend.
+	// A failed delivery is retried after WEBHOOK_INITIAL_BACKOFF, doubled after each failure up to
+	// WEBHOOK_MAX_BACKOFF, and becomes a dead letter after WEBHOOK_MAX_ATTEMPTS attempts.
+	// Webhooks may not reach private, loopback, link-local, shared or reserved addresses outside WEBHOOK_ALLOWED_CIDRS.
+	WebhookTimeout        time.Duration `env:"WEBHOOK_TIMEOUT" envDefault:"10s"`
+	WebhookMaxAttempts    int           `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"10"`
+	WebhookInitialBackoff time.Duration `env:"WEBHOOK_INITIAL_BACKOFF" envDefault:"10s"`
+	WebhookMaxBackoff     time.Duration `env:"WEBHOOK_MAX_BACKOFF" envDefault:"1h"`
+	WebhookAllowedCIDRs   []string      `env:"WEBHOOK_ALLOWED_CIDRS" envSeparator:","`
+
+	// Background jobs, run by a worker in every server instance with the PostgreSQL backend.
+	// A claimed job is hidden from the other instances for JOBS_LEASE, so it must exceed the time
+	// a job takes. On shutdown the running jobs get JOBS_DRAIN_TIMEOUT to finish. The finished jobs
+	// are deleted after JOBS_RETENTION by a job scheduled with the cron expression JOBS_PRUNE_SCHEDULE
+	// in UTC, or never when it is empty.
+	JobsWorkerEnabled  bool          `env:"JOBS_WORKER_ENABLED" envDefault:"true"`
+	JobsPollInterval   time.Duration `env:"JOBS_POLL_INTERVAL" envDefault:"1s"`
+	JobsConcurrency    int           `env:"JOBS_CONCURRENCY" envDefault:"10"`
+	JobsLease          time.Duration `env:"JOBS_LEASE" envDefault:"5m"`
+	JobsDrainTimeout   time.Duration `env:"JOBS_DRAIN_TIMEOUT" envDefault:"30s"`
+	JobsMaxAttempts    int           `env:"JOBS_MAX_ATTEMPTS" envDefault:"10"`
+	JobsInitialBackoff time.Duration `env:"JOBS_INITIAL_BACKOFF" envDefault:"10s"`
+	JobsMaxBackoff     time.Duration `env:"JOBS_MAX_BACKOFF" envDefault:"1h"`
+	JobsRetention      time.Duration `env:"JOBS_RETENTION" envDefault:"168h"`
+	JobsPruneSchedule  string        `env:"JOBS_PRUNE_SCHEDULE" envDefault:"0 3 * * *"`
+
+	// API keys of the clients. Every route but the documentation takes a key, except for the vocabulary
+	// reads when API_KEYS_ANONYMOUS_READ is set. It is set by default for one release so that existing
+	// clients keep reading, and will then default to false. The last use of a key is recorded at most
+	// once per API_KEYS_LAST_USED_INTERVAL.
+	APIKeysAnonymousRead    bool          `env:"API_KEYS_ANONYMOUS_READ" envDefault:"true"`
+	APIKeysLastUsedInterval time.Duration `env:"API_KEYS_LAST_USED_INTERVAL" envDefault:"1m"`
+
+	// CORS settings. CORS is disabled when no origin is allowed.
+	CORSAllowedOrigins   []string      `env:"CORS_ALLOWED_ORIGINS" envSeparator:","`
+	CORSAllowedMethods   []string      `env:"CORS_ALLOWED_METHODS" envSeparator:"," envDefault:"GET,POST,PUT,DELETE"`
+	CORSAllowedHeaders   []string      `env:"CORS_ALLOWED_HEADERS" envSeparator:"," envDefault:"Content-Type,Authorization,X-API-Key,Idempotency-Key,If-None-Match,Last-Event-ID"`
+	CORSAllowCredentials bool          `env:"CORS_ALLOW_CREDENTIALS" envDefault:"false"`
+	CORSMaxAge           time.Duration `env:"CORS_MAX_AGE" envDefault:"10m"`
 }
 
-func NewConfig(ctx context.Context) (*Config, error) {
-	cfg := &Config{}
-	if err := env.Parse(cfg); err != nil {
-		slog.ErrorContext(ctx, "failed to load the environment variables", "error", err)
-		return nil, err
+// Validate checks every field and reports all invalid ones at once
+func (c *Config) Validate() error {
+	var errs []error
+	invalid := func(field, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...)))
+	}
+
+	switch c.DBDriver {
+	case DBDriverPostgres:
+		if c.DatabaseURL != "" {
+			break
+		}
+
+		for _, required := range []struct {
+			field string
+			value string
+		}{
+			{"POSTGRES_HOST", c.DBHost},
+			{"POSTGRES_PORT", c.DBPort},
+			{"POSTGRES_USER", c.DBUser},
+			{"POSTGRES_DB", c.DBName},
+		} {
+			if required.value == "" {
+				invalid(required.field, "is required when DB_DRIVER is %s and DATABASE_URL is not set", DBDriverPostgres)
+			}
+		}
+	case DBDriverSQLite:
+		if c.SQLitePath == "" {
+			invalid("SQLITE_PATH", "is required when DB_DRIVER is %s", DBDriverSQLite)
+		}
+	case DBDriverMemory:
+	default:
+		invalid("DB_DRIVER", "must be one of %s, %s or %s, got %q", DBDriverPostgres, DBDriverSQLite, DBDriverMemory, c.DBDriver)
+	}
+
+	if c.DatabaseReplicaURL != "" && c.DBDriver != DBDriverPostgres {
+		invalid("DATABASE_REPLICA_URL", "requires DB_DRIVER %s, got %q", DBDriverPostgres, c.DBDriver)
+	}
+
+	if c.DBMaxOpenConns < 0 {
+		invalid("DB_MAX_OPEN_CONNS", "must not be negative, got %d", c.DBMaxOpenConns)
+	}
+	if c.DBMinIdleConns < 0 {
+		invalid("DB_MIN_IDLE_CONNS", "must not be negative, got %d", c.DBMinIdleConns)
+	}
+	if c.DBMaxOpenConns > 0 && c.DBMinIdleConns > c.DBMaxOpenConns {
+		invalid("DB_MIN_IDLE_CONNS", "must not exceed DB_MAX_OPEN_CONNS (%d), got %d", c.DBMaxOpenConns, c.DBMinIdleConns)
+	}
+	for _, duration := range []struct {
+		field string
+		value time.Duration
+	}{
+		{"DB_CONN_MAX_LIFETIME", c.DBConnMaxLifetime},
+		{"DB_CONN_MAX_IDLE_TIME", c.DBConnMaxIdleTime},
+		{"DB_CONNECT_TIMEOUT", c.DBConnectTimeout},
+		{"DB_STATEMENT_TIMEOUT", c.DBStatementTimeout},
+		{"DB_REPLICA_HEALTH_INTERVAL", c.DBReplicaHealthInterval},
+		{"DB_READ_YOUR_WRITES_WINDOW", c.DBReadYourWritesWindow},
+	} {
+		if duration.value < 0 {
+			invalid(duration.field, "must not be negative, got %s", duration.value)
+		}
+	}
+
+	if !slices.Contains([]string{"read_committed", "repeatable_read", "serializable"}, c.DBTxIsolation) {
+		invalid("DB_TX_ISOLATION", "must be read_committed, repeatable_read or serializable, got %q", c.DBTxIsolation)
+	}
+	if c.DBTxMaxRetries < 0 {
+		invalid("DB_TX_MAX_RETRIES", "must not be negative, got %d", c.DBTxMaxRetries)
+	}
+
+	if c.CacheEnabled {
+		if c.CacheSize <= 0 {
+			invalid("CACHE_SIZE", "must be positive, got %d", c.CacheSize)
+		}
+		if c.CacheTTL <= 0 {
+			invalid("CACHE_TTL", "must be positive, got %s", c.CacheTTL)
+		}
+	}
+
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+		invalid("APP_PORT", "must be a port number between 1 and 65535, got %q", c.Port)
+	}
+
+	if c.GRPCEnabled {
+		if port, err := strconv.Atoi(c.GRPCPort); err != nil || port < 1 || port > 65535 {
+			invalid("GRPC_PORT", "must be a port number between 1 and 65535, got %q", c.GRPCPort)
+		} else if c.GRPCPort == c.Port {
+			invalid("GRPC_PORT", "must differ from APP_PORT, got %q", c.GRPCPort)
+		}
+	}
+
+	if c.APIKeysLastUsedInterval < 0 {
+		invalid("API_KEYS_LAST_USED_INTERVAL", "must not be negative, got %s", c.APIKeysLastUsedInterval)
+	}
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		invalid("TLS_CERT_FILE", "must be set together with TLS_KEY_FILE")
+	}
+
+	if c.TLSClientCAFile != "" && c.TLSCertFile == "" {
+		invalid("TLS_CLIENT_CA_FILE", "requires TLS_CERT_FILE and TLS_KEY_FILE")
+	}
+
+	for _, timeout := range []struct {
+		field string
+		value time.Duration
+	}{
+		{"HTTP_READ_HEADER_TIMEOUT", c.ReadHeaderTimeout},
+		{"HTTP_READ_TIMEOUT", c.ReadTimeout},
+		{"HTTP_WRITE_TIMEOUT", c.WriteTimeout},
+		{"HTTP_IDLE_TIMEOUT", c.IdleTimeout},
+		{"HTTP_SHUTDOWN_TIMEOUT", c.ShutdownTimeout},
+		{"HTTP_HANDLER_TIMEOUT", c.HandlerTimeout},
+	} {
+		if timeout.value <= 0 {
+			invalid(timeout.field, "must be positive, got %s", timeout.value)
+		}
+	}
+
+	// The timeout response must be written before the connection's write deadline
+	if c.HandlerTimeout >= c.WriteTimeout {
+		invalid("HTTP_HANDLER_TIMEOUT", "must be shorter than HTTP_WRITE_TIMEOUT (%s), got %s", c.WriteTimeout, c.HandlerTimeout)
+	}
+
+	if c.MaxBodyBytes <= 0 {
+		invalid("MAX_REQUEST_BODY_BYTES", "must be positive, got %d", c.MaxBodyBytes)
+	}
+
+	if c.BatchMaxOperations <= 0 {
+		invalid("BATCH_MAX_OPERATIONS", "must be positive, got %d", c.BatchMaxOperations)
+	}
+
+	if c.RateLimitEnabled {
+		if c.RateLimitReadRate <= 0 {
+			invalid("RATE_LIMIT_READ_RATE", "must be positive, got %g", c.RateLimitReadRate)
+		}
+		if c.RateLimitReadBurst <= 0 {
+			invalid("RATE_LIMIT_READ_BURST", "must be positive, got %d", c.RateLimitReadBurst)
+		}
+		if c.RateLimitWriteRate <= 0 {
+			invalid("RATE_LIMIT_WRITE_RATE", "must be positive, got %g", c.RateLimitWriteRate)
+		}
+		if c.RateLimitWriteBurst <= 0 {
+			invalid("RATE_LIMIT_WRITE_BURST", "must be positive, got %d", c.RateLimitWriteBurst)
+		}
+	}
+
+	if c.IdempotencyEnabled && c.IdempotencyKeyTTL <= 0 {
+		invalid("IDEMPOTENCY_KEY_TTL", "must be positive, got %s", c.IdempotencyKeyTTL)
+	}
+
+	if c.EventsReplaySize < 0 {
+		invalid("EVENTS_REPLAY_SIZE", "must not be negative, got %d", c.EventsReplaySize)
+	}
+	if c.EventsSubscriberBuffer <= 0 {
+		invalid("EVENTS_SUBSCRIBER_BUFFER", "must be positive, got %d", c.EventsSubscriberBuffer)
+	}
+	if c.EventsHeartbeatInterval <= 0 {
+		invalid("EVENTS_HEARTBEAT_INTERVAL", "must be positive, got %s", c.EventsHeartbeatInterval)
+	}
+
+	if c.WebhookTimeout <= 0 {
+		invalid("WEBHOOK_TIMEOUT", "must be positive, got %s", c.WebhookTimeout)
+	}
+	if c.WebhookMaxAttempts <= 0 {
+		invalid("WEBHOOK_MAX_ATTEMPTS", "must be positive, got %d", c.WebhookMaxAttempts)
+	}
+	if c.WebhookInitialBackoff <= 0 {
+		invalid("WEBHOOK_INITIAL_BACKOFF", "must be positive, got %s", c.WebhookInitialBackoff)
+	}
+	if c.WebhookMaxBackoff < c.WebhookInitialBackoff {
+		invalid(
+			"WEBHOOK_MAX_BACKOFF", "must not be shorter than WEBHOOK_INITIAL_BACKOFF (%s), got %s",
+			c.WebhookInitialBackoff, c.WebhookMaxBackoff,
+		)
+	}
+	for _, cidr := range c.WebhookAllowedCIDRs {
+		if _, err := netip.ParsePrefix(cidr); err != nil {
+			invalid("WEBHOOK_ALLOWED_CIDRS", "%q is not a CIDR", cidr)
+		}
+	}
+
+	if c.JobsPollInterval <= 0 {
+		invalid("JOBS_POLL_INTERVAL", "must be positive, got %s", c.JobsPollInterval)
+	}
+	if c.JobsConcurrency <= 0 {
+		invalid("JOBS_CONCURRENCY", "must be positive, got %d", c.JobsConcurrency)
+	}
+	if c.JobsLease <= 0 {
+		invalid("JOBS_LEASE", "must be positive, got %s", c.JobsLease)
+	} else if c.JobsLease <= c.WebhookTimeout {
+		// A webhook delivery is a job
+		invalid("JOBS_LEASE", "must be longer than WEBHOOK_TIMEOUT (%s), got %s", c.WebhookTimeout, c.JobsLease)
+	}
+	if c.JobsDrainTimeout < 0 {
+		invalid("JOBS_DRAIN_TIMEOUT", "must not be negative, got %s", c.JobsDrainTimeout)
+	}
+	if c.JobsMaxAttempts <= 0 {
+		invalid("JOBS_MAX_ATTEMPTS", "must be positive, got %d", c.JobsMaxAttempts)
+	}
+	if c.JobsInitialBackoff <= 0 {
+		invalid("JOBS_INITIAL_BACKOFF", "must be positive, got %s", c.JobsInitialBackoff)
+	}
+	if c.JobsMaxBackoff < c.JobsInitialBackoff {
+		invalid(
+			"JOBS_MAX_BACKOFF", "must not be shorter than JOBS_INITIAL_BACKOFF (%s), got %s",
+			c.JobsInitialBackoff, c.JobsMaxBackoff,
+		)
+	}
+	if c.JobsRetention <= 0 {
+		invalid("JOBS_RETENTION", "must be positive, got %s", c.JobsRetention)
+	}
+
+	// Browsers reject credentials with a wildcard origin
+	if c.CORSAllowCredentials && slices.Contains(c.CORSAllowedOrigins, "*") {
+		invalid("CORS_ALLOW_CREDENTIALS", "cannot be used with CORS_ALLOWED_ORIGINS=*")
 	}
 
-	return cfg, nil
+	return errors.Join(errs...)
 }
@@ -0,0 +1,259 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Path of the config file when --config is not given
+const configFileEnv = "CONFIG_FILE"
+
+type field struct {
+	Name      string
+	Value     reflect.Value
+	Default   string
+	Separator string
+	Secret    bool
+}
+
+// Load builds the configuration from these layers, each one overriding the previous:
+//
+//  1. defaults in the envDefault tags
+//  2. the YAML or TOML file given by --config or CONFIG_FILE
+//  3. environment variables, or the contents of the file named by NAME_FILE
+//  4. flags before the command, e.g. --app-port 8080
+//
+// It returns the arguments left after the flags. Every invalid value is reported at once;
+// fields with an invalid value keep the value of the previous layer, so that the returned
+// configuration can still be validated and printed.
+func Load(args []string) (*Config, []string, error) {
+	cfg := &Config{}
+	fields := fieldsOf(cfg)
+	var errs []error
+
+	// Flags are parsed first to find --config, and applied last
+	fs := flag.NewFlagSet("config", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	configFile := fs.String("config", os.Getenv(configFileEnv), "YAML or TOML config file")
+	flagValues := make(map[string]*string)
+	for _, f := range fields {
+		flagValues[f.Name] = fs.String(flagName(f.Name), "", "overrides "+f.Name)
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, fmt.Errorf("invalid flag: %w", err)
+	}
+
+	// 1. Defaults
+	for _, f := range fields {
+		if f.Default != "" {
+			errs = append(errs, setField(f, f.Default))
+		}
+	}
+
+	// 2. Config file
+	if *configFile != "" {
+		values, err := readFile(*configFile)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, f := range fields {
+			key := strings.ToLower(f.Name)
+			if value, ok := values[key]; ok {
+				errs = append(errs, setField(f, value))
+				delete(values, key)
+			}
+		}
+
+		// Report typos instead of silently ignoring them
+		for key := range values {
+			errs = append(errs, fmt.Errorf("%s: unknown key in %s", key, *configFile))
+		}
+	}
+
+	// 3. Environment variables
+	for _, f := range fields {
+		value, ok, err := lookupEnv(f.Name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if ok {
+			errs = append(errs, setField(f, value))
+		}
+	}
+
+	// 4. Flags that were actually given
+	fs.Visit(func(fl *flag.Flag) {
+		for _, f := range fields {
+			if fl.Name == flagName(f.Name) {
+				errs = append(errs, setField(f, *flagValues[f.Name]))
+			}
+		}
+	})
+
+	return cfg, fs.Args(), errors.Join(errs...)
+}
+
+// Redacted returns every setting by its environment variable name, with secrets masked
+func (c *Config) Redacted() map[string]string {
+	values := make(map[string]string)
+	for _, f := range fieldsOf(c) {
+		value := formatField(f)
+		if f.Secret && value != "" {
+			value = "REDACTED"
+		}
+		values[f.Name] = value
+	}
+
+	return values
+}
+
+func fieldsOf(cfg *Config) []field {
+	v := reflect.ValueOf(cfg).Elem()
+	t := v.Type()
+
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag
+		name := tag.Get("env")
+		if name == "" {
+			continue
+		}
+
+		fields = append(fields, field{
+			Name:      name,
+			Value:     v.Field(i),
+			Default:   tag.Get("envDefault"),
+			Separator: tag.Get("envSeparator"),
+			Secret:    tag.Get("secret") == "true",
+		})
+	}
+
+	return fields
+}
+
+// lookupEnv reads NAME, or the file whose path is in NAME_FILE as Docker secrets provide it
+func lookupEnv(name string) (string, bool, error) {
+	value, ok := os.LookupEnv(name)
+	path, fileOK := os.LookupEnv(name + "_FILE")
+
+	if ok && fileOK {
+		return "", false, fmt.Errorf("%s: cannot be set together with %s_FILE", name, name)
+	}
+
+	if !fileOK {
+		return value, ok, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("%s_FILE: %w", name, err)
+	}
+
+	return strings.TrimRight(string(content), "\r\n"), true, nil
+}
+
+// readFile reads a flat YAML or TOML file into values keyed by lower case setting names
+func readFile(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the config file: %w", err)
+	}
+
+	raw := make(map[string]any)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &raw)
+	case ".toml":
+		err = toml.Unmarshal(content, &raw)
+	default:
+		return nil, fmt.Errorf("unsupported config file format %q, use .yaml, .yml or .toml", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the config file %s: %w", path, err)
+	}
+
+	values := make(map[string]string)
+	for key, value := range raw {
+		values[strings.ToLower(key)] = formatRaw(value)
+	}
+
+	return values, nil
+}
+
+// formatRaw turns a decoded file value into the same string form as an environment variable
+func formatRaw(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = formatRaw(item)
+		}
+		return strings.Join(items, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func setField(f field, value string) error {
+	var parsed any
+	var err error
+	switch f.Value.Interface().(type) {
+	case string:
+		parsed = value
+	case bool:
+		parsed, err = strconv.ParseBool(value)
+	case int:
+		parsed, err = strconv.Atoi(value)
+	case int64:
+		parsed, err = strconv.ParseInt(value, 10, 64)
+	case float64:
+		parsed, err = strconv.ParseFloat(value, 64)
+	case time.Duration:
+		parsed, err = time.ParseDuration(value)
+	case []string:
+		var items []string
+		for item := range strings.SplitSeq(value, f.Separator) {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		parsed = items
+	default:
+		err = fmt.Errorf("unsupported type %s", f.Value.Type())
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: invalid value %q", f.Name, value)
+	}
+
+	f.Value.Set(reflect.ValueOf(parsed))
+	return nil
+}
+
+func formatField(f field) string {
+	switch v := f.Value.Interface().(type) {
+	case []string:
+		return strings.Join(v, f.Separator)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func flagName(name string) string {
+	return strings.ReplaceAll(strings.ToLower(name), "_", "-")
+}
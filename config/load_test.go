@@ -0,0 +1,159 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeTempFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadLayers(t *testing.T) {
+	file := writeTempFile(t, "config.yaml", `
+app_port: 7000
+postgres_host: file-host
+postgres_user: file-user
+http_read_timeout: 20s
+cors_allowed_origins:
+  - https://a.example.com
+  - https://b.example.com
+`)
+
+	// The environment overrides the file, and flags override the environment
+	t.Setenv("POSTGRES_HOST", "env-host")
+	t.Setenv("APP_PORT", "7500")
+
+	cfg, rest, err := Load([]string{"--config", file, "--app-port", "9000", "serve", "--ignored"})
+	if err != nil {
+		t.Fatalf("Load returned an error: %v", err)
+	}
+
+	if !slices.Equal(rest, []string{"serve", "--ignored"}) {
+		t.Errorf("rest = %q, want [serve --ignored]", rest)
+	}
+
+	checks := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{"default", cfg.DBSslmode, "disable"},
+		{"file", cfg.DBUser, "file-user"},
+		{"file duration", cfg.ReadTimeout, 20 * time.Second},
+		{"file list", strings.Join(cfg.CORSAllowedOrigins, " "), "https://a.example.com https://b.example.com"},
+		{"env over file", cfg.DBHost, "env-host"},
+		{"flag over env", cfg.Port, "9000"},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestLoadTOML(t *testing.T) {
+	file := writeTempFile(t, "config.toml", `
+db_driver = "memory"
+rate_limit_read_burst = 40
+`)
+
+	cfg, _, err := Load([]string{"--config", file})
+	if err != nil {
+		t.Fatalf("Load returned an error: %v", err)
+	}
+
+	if cfg.DBDriver != DBDriverMemory || cfg.RateLimitReadBurst != 40 {
+		t.Errorf("DBDriver = %q, RateLimitReadBurst = %d, want memory and 40", cfg.DBDriver, cfg.RateLimitReadBurst)
+	}
+}
+
+func TestLoadSecretFile(t *testing.T) {
+	t.Setenv("POSTGRES_PASSWORD_FILE", writeTempFile(t, "password", "s3cret\n"))
+
+	cfg, _, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load returned an error: %v", err)
+	}
+	if cfg.DBPassword != "s3cret" {
+		t.Errorf("DBPassword = %q, want s3cret", cfg.DBPassword)
+	}
+
+	if got := cfg.Redacted()["POSTGRES_PASSWORD"]; got != "REDACTED" {
+		t.Errorf("Redacted()[POSTGRES_PASSWORD] = %q, want REDACTED", got)
+	}
+	if got := cfg.Redacted()["POSTGRES_SSLMODE"]; got != "disable" {
+		t.Errorf("Redacted()[POSTGRES_SSLMODE] = %q, want disable", got)
+	}
+
+	// Setting both is ambiguous
+	t.Setenv("POSTGRES_PASSWORD", "other")
+	if _, _, err := Load(nil); err == nil || !strings.Contains(err.Error(), "POSTGRES_PASSWORD") {
+		t.Errorf("Load with both POSTGRES_PASSWORD and POSTGRES_PASSWORD_FILE returned %v", err)
+	}
+}
+
+func TestLoadReportsEveryInvalidValue(t *testing.T) {
+	file := writeTempFile(t, "config.yaml", "app_prot: 8080\n")
+	t.Setenv("HTTP_READ_TIMEOUT", "soon")
+	t.Setenv("RATE_LIMIT_READ_BURST", "many")
+
+	cfg, _, err := Load([]string{"--config", file})
+	if err == nil {
+		t.Fatal("Load returned no error")
+	}
+
+	for _, want := range []string{"app_prot: unknown key", "HTTP_READ_TIMEOUT", "RATE_LIMIT_READ_BURST"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+
+	// Invalid values keep the default
+	if cfg.ReadTimeout != 15*time.Second {
+		t.Errorf("ReadTimeout = %v, want the default 15s", cfg.ReadTimeout)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cfg, _, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load returned an error: %v", err)
+	}
+
+	cfg.Port = "0"
+	cfg.GRPCEnabled = true
+	cfg.GRPCPort = "grpc"
+	cfg.HandlerTimeout = time.Minute
+	cfg.TLSKeyFile = "key.pem"
+	cfg.JobsLease = cfg.WebhookTimeout
+
+	err = cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate returned no error")
+	}
+
+	for _, want := range []string{
+		"POSTGRES_HOST", "POSTGRES_USER", "POSTGRES_DB", "APP_PORT", "GRPC_PORT", "HTTP_HANDLER_TIMEOUT",
+		"TLS_CERT_FILE", "JOBS_LEASE",
+	} {
+		if !strings.Contains(err.Error(), want+":") {
+			t.Errorf("error %q does not name %s", err, want)
+		}
+	}
+
+	cfg, _, _ = Load([]string{"--db-driver", "memory"})
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate of the memory defaults returned an error: %v", err)
+	}
+}
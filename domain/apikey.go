@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Scope is a permission granted to an API key
+type Scope string
+
+const (
+	ScopeVocabRead    Scope = "vocab:read"
+	ScopeVocabWrite   Scope = "vocab:write"
+	ScopeWebhookRead  Scope = "webhook:read"
+	ScopeWebhookWrite Scope = "webhook:write"
+
+	// Creating, listing and revoking the API keys
+	ScopeAPIKeyAdmin Scope = "apikey:admin"
+)
+
+// Scopes lists every scope an API key can carry
+var Scopes = []Scope{ScopeVocabRead, ScopeVocabWrite, ScopeWebhookRead, ScopeWebhookWrite, ScopeAPIKeyAdmin}
+
+// APIKey authenticates a machine client. Only the hash of its token is kept.
+type APIKey struct {
+	KeyID int64
+
+	// Label given by the creator, such as the name of the client
+	Name string
+
+	// Start of the token, kept in clear to identify the key in listings and logs
+	Prefix string
+
+	// SHA-256 of the whole token, hex encoded
+	SecretHash string
+
+	Scopes []Scope
+
+	CreatedAt time.Time
+
+	// Zero when the key never expires
+	ExpiresAt time.Time
+
+	// Zero until the key is first used
+	LastUsedAt time.Time
+
+	// Zero until the key is revoked
+	RevokedAt time.Time
+}
+
+// HasScope reports whether the key grants scope
+func (k *APIKey) HasScope(scope Scope) bool {
+	return slices.Contains(k.Scopes, scope)
+}
+
+// Active reports whether the key can be used at now
+func (k *APIKey) Active(now time.Time) bool {
+	if !k.RevokedAt.IsZero() {
+		return false
+	}
+	return k.ExpiresAt.IsZero() || now.Before(k.ExpiresAt)
+}
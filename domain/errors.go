@@ -0,0 +1,17 @@
+package domain
+
+import "errors"
+
+var (
+	// ErrVocabularyNotFound is returned when no vocabulary matches the specified vocabularyNo
+	ErrVocabularyNotFound = errors.New("vocabulary not found")
+
+	// ErrVocabularyDuplicate is returned when the title is already registered
+	ErrVocabularyDuplicate = errors.New("vocabulary already exists")
+
+	// ErrWebhookNotFound is returned when no webhook matches the specified webhookID
+	ErrWebhookNotFound = errors.New("webhook not found")
+
+	// ErrAPIKeyNotFound is returned when no API key matches the specified keyID
+	ErrAPIKeyNotFound = errors.New("api key not found")
+)
@@ -1,8 +1,20 @@
 package domain
 
+import "time"
+
 type Vocabulary struct {
 	VocabularyNo int64
 	Title        string
 	Meaning      string
 	Sentence     string
+
+	// Incremented on every update, starting at 1
+	Version int64
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Who added and who last updated the vocabulary
+	CreatedBy string
+	UpdatedBy string
 }
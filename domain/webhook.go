@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// Webhook is a subscription of an external endpoint to the vocabulary events
+type Webhook struct {
+	WebhookID int64
+
+	// Endpoint the events are posted to
+	URL string
+
+	// Key of the HMAC-SHA256 signature of the payloads
+	Secret string
+
+	// Types of the events delivered, such as vocabulary.created
+	EventTypes []string
+
+	CreatedAt time.Time
+}
@@ -2,38 +2,128 @@ package db
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"log/slog"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/takumi616/golang-backend-sample/config"
 )
 
-func Open(ctx context.Context, cfg *config.Config) (*sql.DB, error) {
-	dataSourceName := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		cfg.DBHost, cfg.DBPort, cfg.DBUser,
-		cfg.DBPassword, cfg.DBName, cfg.DBSslmode,
-	)
+// Backoff between connection attempts while PostgreSQL is starting up
+const (
+	initialRetryInterval = 500 * time.Millisecond
+	maxRetryInterval     = 5 * time.Second
+)
 
-	// Note: sql.Open does not establish any actual connections
-	db, err := sql.Open("postgres", dataSourceName)
+func Open(ctx context.Context, cfg *config.Config) (*pgxpool.Pool, error) {
+	poolConfig, err := PoolConfig(cfg)
 	if err != nil {
-		slog.ErrorContext(ctx, "failed to open the database", "error", err)
+		slog.ErrorContext(ctx, "invalid database connection settings", "error", err)
 		return nil, err
 	}
 
-	// Set connection pool options
-	db.SetMaxOpenConns(10)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(30 * time.Minute)
+	// Note: connections are established lazily, except MinIdleConns in the background
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to open the database", "error", err)
+		return nil, err
+	}
 
 	// Confirm connection is alive
-	if err := db.PingContext(ctx); err != nil {
+	if err := ping(ctx, pool, cfg.DBConnectTimeout); err != nil {
 		slog.ErrorContext(ctx, "connection to the database is not alive", "error", err)
+		pool.Close()
+		return nil, err
+	}
+
+	return pool, nil
+}
+
+// PoolConfig builds the pgxpool settings from DATABASE_URL or the POSTGRES_* settings
+func PoolConfig(cfg *config.Config) (*pgxpool.Config, error) {
+	poolConfig, err := pgxpool.ParseConfig(ConnString(cfg))
+	if err != nil {
+		if cfg.DatabaseURL != "" {
+			return nil, fmt.Errorf("invalid DATABASE_URL: %w", err)
+		}
 		return nil, err
 	}
 
-	return db, nil
+	// Set connection pool options
+	if cfg.DBMaxOpenConns > 0 {
+		poolConfig.MaxConns = int32(cfg.DBMaxOpenConns)
+	}
+	poolConfig.MinIdleConns = int32(cfg.DBMinIdleConns)
+	if cfg.DBConnMaxLifetime > 0 {
+		poolConfig.MaxConnLifetime = cfg.DBConnMaxLifetime
+	}
+	if cfg.DBConnMaxIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = cfg.DBConnMaxIdleTime
+	}
+
+	// Run-time parameters are applied to every connection
+	if cfg.DBStatementTimeout > 0 {
+		poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(cfg.DBStatementTimeout.Milliseconds(), 10)
+	}
+
+	return poolConfig, nil
+}
+
+// ConnString returns DATABASE_URL, or a key=value connection string built from the POSTGRES_* settings.
+// Values are quoted, so passwords may contain spaces and quotes.
+func ConnString(cfg *config.Config) string {
+	if cfg.DatabaseURL != "" {
+		return cfg.DatabaseURL
+	}
+
+	return strings.Join([]string{
+		"host=" + quote(cfg.DBHost),
+		"port=" + quote(cfg.DBPort),
+		"user=" + quote(cfg.DBUser),
+		"password=" + quote(cfg.DBPassword),
+		"dbname=" + quote(cfg.DBName),
+		"sslmode=" + quote(cfg.DBSslmode),
+	}, " ")
+}
+
+// quote escapes a value for the key=value connection string
+func quote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
+}
+
+// ping retries with exponential backoff until the database answers or timeout passes.
+// A zero timeout pings only once.
+func ping(ctx context.Context, pool *pgxpool.Pool, timeout time.Duration) error {
+	if timeout <= 0 {
+		return pool.Ping(ctx)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	interval := initialRetryInterval
+	for attempt := 1; ; attempt++ {
+		err := pool.Ping(ctx)
+		if err == nil {
+			return nil
+		}
+
+		slog.WarnContext(
+			ctx, "database is not ready yet", slog.Int("attempt", attempt),
+			slog.Duration("retryIn", interval), slog.String("error", err.Error()),
+		)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("gave up connecting to the database after %d attempts: %w", attempt, err)
+		case <-time.After(interval):
+		}
+
+		interval = min(interval*2, maxRetryInterval)
+	}
 }
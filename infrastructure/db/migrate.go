@@ -0,0 +1,137 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type migration struct {
+	Version int64
+	Name    string
+}
+
+// Migrate applies every pending "*.up.sql" file in fsys.
+// The schema_migrations table has the same layout as golang-migrate uses,
+// so databases migrated by the migrate CLI are picked up where they stopped.
+func Migrate(ctx context.Context, db *sql.DB, fsys fs.FS) (int, error) {
+	migrations, err := readMigrations(fsys)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to read the migration files", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	// Create the version table if it does not exist yet
+	if _, err := db.ExecContext(
+		ctx, "CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)",
+	); err != nil {
+		slog.ErrorContext(ctx, "failed to create the schema_migrations table", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	// Get the current version
+	var current int64
+	var dirty bool
+	err = db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&current, &dirty)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		slog.ErrorContext(ctx, "failed to get the current schema version", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	if dirty {
+		err := fmt.Errorf("schema version %d is dirty, fix it manually before migrating", current)
+		slog.ErrorContext(ctx, "failed to migrate the database", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	applied := 0
+	for _, m := range migrations {
+		if m.Version <= current {
+			continue
+		}
+
+		if err := applyMigration(ctx, db, fsys, m); err != nil {
+			return applied, err
+		}
+		applied++
+	}
+
+	slog.InfoContext(ctx, "database migration finished", slog.Int("applied", applied))
+	return applied, nil
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, fsys fs.FS, m migration) error {
+	query, err := fs.ReadFile(fsys, m.Name)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to read the migration file", slog.String("file", m.Name), slog.String("error", err.Error()))
+		return err
+	}
+
+	// Begin a transaction
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to begin a transaction", slog.String("error", err.Error()))
+		return err
+	}
+	defer tx.Rollback()
+
+	// Execute the migration and record the new version
+	if _, err := tx.ExecContext(ctx, string(query)); err != nil {
+		slog.ErrorContext(ctx, "failed to apply the migration", slog.String("file", m.Name), slog.String("error", err.Error()))
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations"); err != nil {
+		slog.ErrorContext(ctx, "failed to clear the schema version", slog.String("error", err.Error()))
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)", m.Version); err != nil {
+		slog.ErrorContext(ctx, "failed to record the schema version", slog.String("error", err.Error()))
+		return err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		slog.ErrorContext(ctx, "failed to commit the transaction", slog.String("error", err.Error()))
+		return err
+	}
+
+	slog.InfoContext(ctx, "migration was applied successfully", slog.String("file", m.Name))
+	return nil
+}
+
+// readMigrations lists "<version>_<name>.up.sql" files sorted by version
+func readMigrations(fsys fs.FS) ([]migration, error) {
+	names, err := fs.Glob(fsys, "*.up.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []migration
+	for _, name := range names {
+		prefix, _, ok := strings.Cut(name, "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration file name: %s", name)
+		}
+
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", name, err)
+		}
+
+		migrations = append(migrations, migration{Version: version, Name: name})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/takumi616/golang-backend-sample/domain"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db"
+)
+
+// Columns scanned by scanAPIKey
+const apiKeyColumns = "key_id, name, prefix, secret_hash, scopes, created_at, expires_at, last_used_at, revoked_at"
+
+// APIKeyRepository keeps the API keys. The keys are read from the primary, so that a revocation
+// takes effect at once, and without pinning the other reads of the request to it.
+type APIKeyRepository struct {
+	DB *db.Router
+}
+
+func NewAPIKeyRepository(router *db.Router) *APIKeyRepository {
+	return &APIKeyRepository{
+		DB: router,
+	}
+}
+
+func (r *APIKeyRepository) InsertAPIKey(ctx context.Context, key *domain.APIKey) (int64, error) {
+	scopes := make([]string, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+
+	var keyID int64
+	err := r.DB.Primary.QueryRow(
+		ctx,
+		`INSERT INTO api_keys (name, prefix, secret_hash, scopes, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING key_id`,
+		key.Name, key.Prefix, key.SecretHash, scopes, key.CreatedAt, nullTime(key.ExpiresAt),
+	).Scan(&keyID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to insert an api key", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	slog.InfoContext(ctx, "new api key was inserted successfully", slog.Int64("keyID", keyID), slog.String("prefix", key.Prefix))
+	return keyID, nil
+}
+
+func (r *APIKeyRepository) SelectAPIKeyByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	key, err := scanAPIKey(r.DB.Primary.QueryRow(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE prefix = $1", prefix))
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		slog.WarnContext(ctx, "no api key found", slog.String("prefix", prefix))
+		return nil, domain.ErrAPIKeyNotFound
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get the api key", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (r *APIKeyRepository) SelectAllAPIKeys(ctx context.Context) ([]*domain.APIKey, error) {
+	rows, err := r.DB.Primary.Query(ctx, "SELECT "+apiKeyColumns+" FROM api_keys ORDER BY key_id")
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to query all api keys", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	keys, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*domain.APIKey, error) {
+		return scanAPIKey(row)
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to scan api key rows", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (r *APIKeyRepository) RevokeAPIKey(ctx context.Context, keyID int64, revokedAt time.Time) (int64, error) {
+	result, err := r.DB.Primary.Exec(
+		ctx, "UPDATE api_keys SET revoked_at = COALESCE(revoked_at, $2) WHERE key_id = $1",
+		keyID, revokedAt.UTC().Truncate(time.Microsecond),
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to revoke the api key", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	if result.RowsAffected() == 0 {
+		slog.WarnContext(ctx, "no api key was revoked", slog.Int64("keyID", keyID))
+		return 0, domain.ErrAPIKeyNotFound
+	}
+
+	slog.InfoContext(ctx, "the api key was revoked successfully", slog.Int64("keyID", keyID))
+	return result.RowsAffected(), nil
+}
+
+func (r *APIKeyRepository) UpdateAPIKeyLastUsed(ctx context.Context, keyID int64, lastUsedAt time.Time) error {
+	_, err := r.DB.Primary.Exec(
+		ctx, "UPDATE api_keys SET last_used_at = $2 WHERE key_id = $1",
+		keyID, lastUsedAt.UTC().Truncate(time.Microsecond),
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to update the last use of the api key", slog.String("error", err.Error()))
+	}
+	return err
+}
+
+// scanAPIKey copies a row of apiKeyColumns into an API key
+func scanAPIKey(row pgx.Row) (*domain.APIKey, error) {
+	var key domain.APIKey
+	var scopes []string
+	var expiresAt, lastUsedAt, revokedAt *time.Time
+
+	err := row.Scan(
+		&key.KeyID, &key.Name, &key.Prefix, &key.SecretHash, &scopes, &key.CreatedAt,
+		&expiresAt, &lastUsedAt, &revokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, scope := range scopes {
+		key.Scopes = append(key.Scopes, domain.Scope(scope))
+	}
+	if expiresAt != nil {
+		key.ExpiresAt = *expiresAt
+	}
+	if lastUsedAt != nil {
+		key.LastUsedAt = *lastUsedAt
+	}
+	if revokedAt != nil {
+		key.RevokedAt = *revokedAt
+	}
+	return &key, nil
+}
+
+// nullTime stores the zero time as NULL
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	t = t.UTC().Truncate(time.Microsecond)
+	return &t
+}
@@ -0,0 +1,18 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db/repository/repositorytest"
+)
+
+func TestAPIKeyRepository(t *testing.T) {
+	repositorytest.RunAPIKeys(t, func(t *testing.T) usecase.APIKeyRepository {
+		return NewAPIKeyRepository(db.NewRouter(context.Background(), openThrowawayPool(t), nil, 0))
+	})
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUEviction(t *testing.T) {
+	c := newLRU(2, time.Minute)
+
+	c.add("a", 1, c.currentEpoch())
+	c.add("b", 2, c.currentEpoch())
+	c.get("a")
+	c.add("c", 3, c.currentEpoch())
+
+	// b is the least recently used
+	if _, ok := c.get("b"); ok {
+		t.Error("b was not evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.get(key); !ok {
+			t.Errorf("%s was evicted", key)
+		}
+	}
+}
+
+func TestLRUExpiry(t *testing.T) {
+	now := time.Now()
+	c := newLRU(10, time.Second)
+	c.now = func() time.Time { return now }
+
+	c.add("a", 1, c.currentEpoch())
+	now = now.Add(999 * time.Millisecond)
+	if _, ok := c.get("a"); !ok {
+		t.Error("a expired before its ttl")
+	}
+
+	now = now.Add(time.Millisecond)
+	if _, ok := c.get("a"); ok {
+		t.Error("a did not expire after its ttl")
+	}
+}
+
+func TestLRUStaleAdd(t *testing.T) {
+	c := newLRU(10, time.Minute)
+
+	// A value read before an invalidation must not be stored
+	epoch := c.currentEpoch()
+	c.remove("a")
+	c.add("a", "old", epoch)
+
+	if _, ok := c.get("a"); ok {
+		t.Error("a value read before an invalidation was stored")
+	}
+}
@@ -0,0 +1,177 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db"
+)
+
+// Key of the result of SelectAll for the zero VocabularyQuery
+const listKey = "list"
+
+func vocabularyKey(vocabularyNo int64) string {
+	return "vocabulary:" + strconv.FormatInt(vocabularyNo, 10)
+}
+
+// VocabularyRepository caches the queries of another repository in process memory.
+// Update and Delete invalidate the vocabulary and the list, and Insert and InsertAll invalidate the list.
+// Inside a transaction of TxManager the cache is bypassed, and so is it for filtered or sorted lists.
+// It is also bypassed by reads pinned to the primary, and misses are read from the primary,
+// so that a lagging replica cannot put an invalidated value back.
+type VocabularyRepository struct {
+	Next usecase.VocabularyRepository
+
+	cache *lru
+}
+
+// NewVocabularyRepository caches up to size results for ttl each
+func NewVocabularyRepository(next usecase.VocabularyRepository, size int, ttl time.Duration) *VocabularyRepository {
+	return &VocabularyRepository{
+		Next:  next,
+		cache: newLRU(size, ttl),
+	}
+}
+
+func (r *VocabularyRepository) Insert(ctx context.Context, vocabulary *domain.Vocabulary) (int64, error) {
+	vocabularyNo, err := r.Next.Insert(ctx, vocabulary)
+	r.invalidate(ctx, listKey)
+	return vocabularyNo, err
+}
+
+func (r *VocabularyRepository) InsertAll(ctx context.Context, vocabularies []*domain.Vocabulary) (int64, error) {
+	inserted, err := r.Next.InsertAll(ctx, vocabularies)
+	r.invalidate(ctx, listKey)
+	return inserted, err
+}
+
+func (r *VocabularyRepository) SelectByVocabularyNo(ctx context.Context, vocabularyNo int64) (*domain.Vocabulary, error) {
+	if inTx(ctx) || db.Pinned(ctx) {
+		return r.Next.SelectByVocabularyNo(ctx, vocabularyNo)
+	}
+
+	key := vocabularyKey(vocabularyNo)
+	if cached, ok := r.cache.get(key); ok {
+		vocabulary := cached.(domain.Vocabulary)
+		return &vocabulary, nil
+	}
+
+	epoch := r.cache.currentEpoch()
+	vocabulary, err := r.Next.SelectByVocabularyNo(db.WithPrimary(ctx), vocabularyNo)
+	if err != nil {
+		return nil, err
+	}
+
+	// Store a copy so that the caller cannot modify the cached value
+	r.cache.add(key, *vocabulary, epoch)
+	return vocabulary, nil
+}
+
+func (r *VocabularyRepository) SelectAll(ctx context.Context, query usecase.VocabularyQuery) ([]*domain.Vocabulary, error) {
+	if inTx(ctx) || db.Pinned(ctx) || !query.IsZero() {
+		return r.Next.SelectAll(ctx, query)
+	}
+
+	if cached, ok := r.cache.get(listKey); ok {
+		return copyList(cached.([]domain.Vocabulary)), nil
+	}
+
+	epoch := r.cache.currentEpoch()
+	vocabularyList, err := r.Next.SelectAll(db.WithPrimary(ctx), query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Store a copy so that the caller cannot modify the cached value
+	values := make([]domain.Vocabulary, 0, len(vocabularyList))
+	for _, vocabulary := range vocabularyList {
+		values = append(values, *vocabulary)
+	}
+	r.cache.add(listKey, values, epoch)
+
+	return vocabularyList, nil
+}
+
+func (r *VocabularyRepository) Update(ctx context.Context, vocabularyNo int64, vocabulary *domain.Vocabulary) (int64, error) {
+	updated, err := r.Next.Update(ctx, vocabularyNo, vocabulary)
+	r.invalidate(ctx, vocabularyKey(vocabularyNo), listKey)
+	return updated, err
+}
+
+func (r *VocabularyRepository) Delete(ctx context.Context, vocabularyNo int64) (int64, error) {
+	rowsAffected, err := r.Next.Delete(ctx, vocabularyNo)
+	r.invalidate(ctx, vocabularyKey(vocabularyNo), listKey)
+	return rowsAffected, err
+}
+
+// invalidate removes the keys now, and once more when the transaction of ctx ends,
+// since other requests may cache the old values until it commits
+func (r *VocabularyRepository) invalidate(ctx context.Context, keys ...string) {
+	r.cache.remove(keys...)
+
+	if written, ok := ctx.Value(txKey{}).(*writtenKeys); ok {
+		written.add(keys...)
+	}
+}
+
+func copyList(values []domain.Vocabulary) []*domain.Vocabulary {
+	vocabularyList := make([]*domain.Vocabulary, 0, len(values))
+	for _, value := range values {
+		vocabulary := value
+		vocabularyList = append(vocabularyList, &vocabulary)
+	}
+	return vocabularyList
+}
+
+type txKey struct{}
+
+// writtenKeys collects the keys invalidated in a transaction
+type writtenKeys struct {
+	mu   sync.Mutex
+	keys []string
+}
+
+func (w *writtenKeys) add(keys ...string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.keys = append(w.keys, keys...)
+}
+
+func inTx(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*writtenKeys)
+	return ok
+}
+
+// TxManager lets a cached VocabularyRepository take part in the transactions of another TxManager
+type TxManager struct {
+	Next       usecase.TxManager
+	Repository *VocabularyRepository
+}
+
+func NewTxManager(next usecase.TxManager, repository *VocabularyRepository) *TxManager {
+	return &TxManager{
+		Next:       next,
+		Repository: repository,
+	}
+}
+
+func (m *TxManager) WithinTx(ctx context.Context, opts usecase.TxOptions, fn func(ctx context.Context) error) error {
+	// Join the transaction of an outer WithinTx
+	if inTx(ctx) {
+		return m.Next.WithinTx(ctx, opts, fn)
+	}
+
+	written := &writtenKeys{}
+	err := m.Next.WithinTx(ctx, opts, func(ctx context.Context) error {
+		return fn(context.WithValue(ctx, txKey{}, written))
+	})
+
+	// The transaction has committed or rolled back
+	m.Repository.cache.remove(written.keys...)
+	return err
+}
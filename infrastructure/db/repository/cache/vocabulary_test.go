@@ -0,0 +1,143 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db/repository/memory"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db/repository/repositorytest"
+)
+
+func TestVocabularyRepository(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) usecase.VocabularyRepository {
+		return NewVocabularyRepository(memory.NewVocabularyRepository(), 100, time.Minute)
+	})
+}
+
+func TestTxManager(t *testing.T) {
+	repositorytest.RunTx(t, func(t *testing.T) (usecase.VocabularyRepository, usecase.TxManager) {
+		next := memory.NewVocabularyRepository()
+		repo := NewVocabularyRepository(next, 100, time.Minute)
+		return repo, NewTxManager(memory.NewTxManager(next), repo)
+	})
+}
+
+// countingRepository counts the queries that reach the backend
+type countingRepository struct {
+	usecase.VocabularyRepository
+	selects int
+}
+
+func (r *countingRepository) SelectByVocabularyNo(ctx context.Context, vocabularyNo int64) (*domain.Vocabulary, error) {
+	r.selects++
+	return r.VocabularyRepository.SelectByVocabularyNo(ctx, vocabularyNo)
+}
+
+func TestCacheHitAndInvalidation(t *testing.T) {
+	ctx := context.Background()
+	next := &countingRepository{VocabularyRepository: memory.NewVocabularyRepository()}
+	repo := NewVocabularyRepository(next, 100, time.Minute)
+
+	vocabularyNo, err := repo.Insert(ctx, &domain.Vocabulary{Title: "apple"})
+	if err != nil {
+		t.Fatalf("Insert returned an error: %v", err)
+	}
+
+	// The second query is served from the cache, and modifying a result does not affect it
+	for range 2 {
+		got, err := repo.SelectByVocabularyNo(ctx, vocabularyNo)
+		if err != nil {
+			t.Fatalf("SelectByVocabularyNo returned an error: %v", err)
+		}
+		if got.Title != "apple" {
+			t.Errorf("SelectByVocabularyNo = %+v, want the title apple", got)
+		}
+		got.Title = "modified"
+	}
+	if next.selects != 1 {
+		t.Errorf("backend was queried %d times, want 1", next.selects)
+	}
+
+	// Update invalidates the cached vocabulary
+	if _, err := repo.Update(ctx, vocabularyNo, &domain.Vocabulary{Title: "apricot"}); err != nil {
+		t.Fatalf("Update returned an error: %v", err)
+	}
+	got, err := repo.SelectByVocabularyNo(ctx, vocabularyNo)
+	if err != nil {
+		t.Fatalf("SelectByVocabularyNo returned an error: %v", err)
+	}
+	if got.Title != "apricot" || got.Version != 2 || next.selects != 2 {
+		t.Errorf("SelectByVocabularyNo after Update = %+v with %d queries, want apricot version 2 with 2 queries", got, next.selects)
+	}
+}
+
+// laggingRepository writes to the primary and reads from a replica that has not caught up yet,
+// unless the reads are pinned to the primary like those of the PostgreSQL repository
+type laggingRepository struct {
+	usecase.VocabularyRepository
+	replica usecase.VocabularyRepository
+}
+
+func (r *laggingRepository) reader(ctx context.Context) usecase.VocabularyRepository {
+	if db.Pinned(ctx) {
+		return r.VocabularyRepository
+	}
+	return r.replica
+}
+
+func (r *laggingRepository) SelectByVocabularyNo(ctx context.Context, vocabularyNo int64) (*domain.Vocabulary, error) {
+	return r.reader(ctx).SelectByVocabularyNo(ctx, vocabularyNo)
+}
+
+func (r *laggingRepository) SelectAll(ctx context.Context, query usecase.VocabularyQuery) ([]*domain.Vocabulary, error) {
+	return r.reader(ctx).SelectAll(ctx, query)
+}
+
+func TestCacheIgnoresLaggingReplica(t *testing.T) {
+	ctx := context.Background()
+	next := &laggingRepository{VocabularyRepository: memory.NewVocabularyRepository(), replica: memory.NewVocabularyRepository()}
+	repo := NewVocabularyRepository(next, 100, time.Minute)
+
+	// The replica has the vocabulary as inserted, and the cache has it too
+	vocabularyNo, err := repo.Insert(ctx, &domain.Vocabulary{Title: "apple"})
+	if err != nil {
+		t.Fatalf("Insert returned an error: %v", err)
+	}
+	if _, err := next.replica.Insert(ctx, &domain.Vocabulary{Title: "apple"}); err != nil {
+		t.Fatalf("Insert into the replica returned an error: %v", err)
+	}
+	if _, err := repo.SelectByVocabularyNo(ctx, vocabularyNo); err != nil {
+		t.Fatalf("SelectByVocabularyNo returned an error: %v", err)
+	}
+	if _, err := repo.SelectAll(ctx, usecase.VocabularyQuery{}); err != nil {
+		t.Fatalf("SelectAll returned an error: %v", err)
+	}
+
+	// The update invalidates the cache but does not reach the replica
+	if _, err := repo.Update(ctx, vocabularyNo, &domain.Vocabulary{Title: "apricot"}); err != nil {
+		t.Fatalf("Update returned an error: %v", err)
+	}
+
+	pinned := db.WithReadYourWritesUntil(ctx, time.Now().Add(time.Minute))
+	for _, readCtx := range []context.Context{ctx, ctx, pinned} {
+		got, err := repo.SelectByVocabularyNo(readCtx, vocabularyNo)
+		if err != nil {
+			t.Fatalf("SelectByVocabularyNo returned an error: %v", err)
+		}
+		if got.Title != "apricot" {
+			t.Errorf("SelectByVocabularyNo = %+v, want the title apricot", got)
+		}
+
+		list, err := repo.SelectAll(readCtx, usecase.VocabularyQuery{})
+		if err != nil {
+			t.Fatalf("SelectAll returned an error: %v", err)
+		}
+		if len(list) != 1 || list[0].Title != "apricot" {
+			t.Errorf("SelectAll = %+v, want apricot", list)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db"
+)
+
+// IdempotencyStore keeps the idempotency keys in PostgreSQL so that every instance shares them
+type IdempotencyStore struct {
+	DB *db.Router
+
+	// Unix time of the last deletion of expired keys
+	lastSweep atomic.Int64
+}
+
+func NewIdempotencyStore(router *db.Router) *IdempotencyStore {
+	return &IdempotencyStore{
+		DB: router,
+	}
+}
+
+func (s *IdempotencyStore) Reserve(
+	ctx context.Context, key, requestHash string, expiresAt time.Time,
+) (*usecase.IdempotencyRecord, bool, error) {
+	now := time.Now()
+	s.sweep(ctx, now)
+
+	// Insert the key, or take over an expired one
+	var reserved string
+	err := s.DB.Writer(ctx).QueryRow(
+		ctx,
+		`INSERT INTO idempotency_keys (key, request_hash, expires_at) VALUES ($1, $2, $3)
+		ON CONFLICT (key) DO UPDATE SET
+			request_hash = EXCLUDED.request_hash, status_code = 0, content_type = '', body = NULL, expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= $4
+		RETURNING key`,
+		key, requestHash, expiresAt, now,
+	).Scan(&reserved)
+	if err == nil {
+		return nil, true, nil
+	}
+
+	if !errors.Is(err, pgx.ErrNoRows) {
+		slog.ErrorContext(ctx, "failed to reserve the idempotency key", slog.String("error", err.Error()))
+		return nil, false, err
+	}
+
+	// The key is in use
+	var record usecase.IdempotencyRecord
+	err = s.DB.Writer(ctx).QueryRow(
+		ctx, "SELECT request_hash, status_code, content_type, body FROM idempotency_keys WHERE key = $1", key,
+	).Scan(&record.RequestHash, &record.StatusCode, &record.ContentType, &record.Body)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get the idempotency key", slog.String("error", err.Error()))
+		return nil, false, err
+	}
+
+	return &record, false, nil
+}
+
+func (s *IdempotencyStore) Complete(ctx context.Context, key string, record usecase.IdempotencyRecord) error {
+	_, err := s.DB.Writer(ctx).Exec(
+		ctx, "UPDATE idempotency_keys SET status_code = $2, content_type = $3, body = $4 WHERE key = $1",
+		key, record.StatusCode, record.ContentType, record.Body,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to record the response of the idempotency key", slog.String("error", err.Error()))
+	}
+	return err
+}
+
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	_, err := s.DB.Writer(ctx).Exec(ctx, "DELETE FROM idempotency_keys WHERE key = $1 AND status_code = 0", key)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to release the idempotency key", slog.String("error", err.Error()))
+	}
+	return err
+}
+
+// sweep deletes the expired keys, at most once a minute
+func (s *IdempotencyStore) sweep(ctx context.Context, now time.Time) {
+	last := s.lastSweep.Load()
+	if now.Unix()-last < 60 || !s.lastSweep.CompareAndSwap(last, now.Unix()) {
+		return
+	}
+
+	result, err := s.DB.Writer(ctx).Exec(ctx, "DELETE FROM idempotency_keys WHERE expires_at <= $1", now)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to delete the expired idempotency keys", slog.String("error", err.Error()))
+		return
+	}
+
+	slog.InfoContext(ctx, "expired idempotency keys were deleted", slog.Int64("rowsAffected", result.RowsAffected()))
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db"
+)
+
+// Columns scanned by scanJob
+const jobColumns = "job_id, kind, payload, status, attempts, run_at, unique_key, last_error, created_at, finished_at"
+
+// JobRepository keeps the queue of the background jobs
+type JobRepository struct {
+	DB *db.Router
+}
+
+func NewJobRepository(router *db.Router) *JobRepository {
+	return &JobRepository{
+		DB: router,
+	}
+}
+
+func (r *JobRepository) EnqueueJob(ctx context.Context, job *usecase.Job) (int64, error) {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	runAt := job.RunAt
+	if runAt.IsZero() {
+		runAt = now
+	}
+
+	var uniqueKey *string
+	if job.UniqueKey != "" {
+		uniqueKey = &job.UniqueKey
+	}
+
+	// Joins the transaction of the domain write when ctx has one
+	var jobID int64
+	err := r.writer(ctx).QueryRow(
+		ctx,
+		`INSERT INTO jobs (kind, payload, run_at, unique_key, created_at) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (unique_key) DO NOTHING RETURNING job_id`,
+		job.Kind, job.Payload, runAt, uniqueKey, now,
+	).Scan(&jobID)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		slog.InfoContext(ctx, "the job is already enqueued", slog.String("uniqueKey", job.UniqueKey))
+		return 0, nil
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to enqueue a job", slog.String("kind", job.Kind), slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	slog.InfoContext(ctx, "new job was enqueued", slog.String("kind", job.Kind), slog.Int64("jobID", jobID))
+	return jobID, nil
+}
+
+func (r *JobRepository) ClaimJobs(ctx context.Context, kinds []string, limit int, lease time.Duration) ([]*usecase.Job, error) {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	// SKIP LOCKED lets the runners of every instance claim different jobs
+	rows, err := r.writer(ctx).Query(
+		ctx,
+		`UPDATE jobs SET attempts = attempts + 1, run_at = $2
+		WHERE job_id IN (
+			SELECT job_id FROM jobs
+			WHERE status = 'pending' AND run_at <= $1 AND kind = ANY ($3)
+			ORDER BY run_at, job_id
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+jobColumns,
+		now, now.Add(lease), kinds, limit,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to claim the jobs", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	jobs, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*usecase.Job, error) {
+		return scanJob(row)
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to scan the claimed jobs", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+func (r *JobRepository) UpdateJob(ctx context.Context, job *usecase.Job) error {
+	var finishedAt *time.Time
+	if !job.FinishedAt.IsZero() {
+		finishedAt = &job.FinishedAt
+	}
+
+	_, err := r.writer(ctx).Exec(
+		ctx,
+		"UPDATE jobs SET status = $2, run_at = $3, last_error = $4, finished_at = $5 WHERE job_id = $1",
+		job.JobID, string(job.Status), job.RunAt, job.LastError, finishedAt,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to record the job", slog.String("error", err.Error()))
+	}
+	return err
+}
+
+func (r *JobRepository) DeleteFinishedJobs(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.writer(ctx).Exec(
+		ctx, "DELETE FROM jobs WHERE status <> 'pending' AND finished_at < $1", before,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to delete the finished jobs", slog.String("error", err.Error()))
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+// writer returns the transaction of ctx, or the primary
+func (r *JobRepository) writer(ctx context.Context) execQuerier {
+	if tx, ok := txFrom(ctx); ok {
+		return tx
+	}
+	return r.DB.Writer(ctx)
+}
+
+// scanJob copies a row of jobColumns into the job
+func scanJob(row pgx.Row) (*usecase.Job, error) {
+	var job usecase.Job
+	var status string
+	var uniqueKey *string
+	var finishedAt *time.Time
+
+	err := row.Scan(
+		&job.JobID, &job.Kind, &job.Payload, &status, &job.Attempts, &job.RunAt, &uniqueKey,
+		&job.LastError, &job.CreatedAt, &finishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	job.Status = usecase.JobStatus(status)
+	if uniqueKey != nil {
+		job.UniqueKey = *uniqueKey
+	}
+	if finishedAt != nil {
+		job.FinishedAt = *finishedAt
+	}
+	return &job, nil
+}
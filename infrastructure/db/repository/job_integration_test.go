@@ -0,0 +1,87 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db"
+)
+
+func TestJobRepository(t *testing.T) {
+	ctx := context.Background()
+	jobs := NewJobRepository(db.NewRouter(ctx, openThrowawayPool(t), nil, 0))
+
+	jobID, err := jobs.EnqueueJob(ctx, &usecase.Job{Kind: "greet", Payload: []byte(`{"name":"alice"}`), UniqueKey: "key"})
+	if err != nil || jobID == 0 {
+		t.Fatalf("EnqueueJob = %d, %v", jobID, err)
+	}
+
+	// A job with the same unique key is not enqueued
+	if duplicateID, err := jobs.EnqueueJob(ctx, &usecase.Job{Kind: "greet", Payload: []byte(`{}`), UniqueKey: "key"}); err != nil || duplicateID != 0 {
+		t.Errorf("EnqueueJob of a duplicate = %d, %v, want 0, nil", duplicateID, err)
+	}
+
+	// A scheduled job is not due yet
+	if _, err := jobs.EnqueueJob(ctx, &usecase.Job{Kind: "greet", Payload: []byte(`{}`), RunAt: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	claimed, err := jobs.ClaimJobs(ctx, []string{"greet"}, 10, time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimJobs returned an error: %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].JobID != jobID || claimed[0].Attempts != 1 || string(claimed[0].Payload) != `{"name": "alice"}` {
+		t.Fatalf("claimed = %+v, want job %d with one attempt", claimed, jobID)
+	}
+
+	// The claimed job is leased
+	if again, err := jobs.ClaimJobs(ctx, []string{"greet"}, 10, time.Minute); err != nil || len(again) != 0 {
+		t.Errorf("ClaimJobs during the lease = %d jobs, %v", len(again), err)
+	}
+
+	job := claimed[0]
+	job.Status = usecase.JobSucceeded
+	job.FinishedAt = time.Now().Add(-2 * time.Hour)
+	if err := jobs.UpdateJob(ctx, job); err != nil {
+		t.Fatalf("UpdateJob returned an error: %v", err)
+	}
+
+	deleted, err := jobs.DeleteFinishedJobs(ctx, time.Now().Add(-time.Hour))
+	if err != nil || deleted != 1 {
+		t.Errorf("DeleteFinishedJobs = %d, %v, want 1, nil", deleted, err)
+	}
+}
+
+func TestJobEnqueuedInTransaction(t *testing.T) {
+	ctx := context.Background()
+	router := db.NewRouter(ctx, openThrowawayPool(t), nil, 0)
+	vocabularies := NewVocabularyRepository(router)
+	jobs := NewJobRepository(router)
+	txManager := NewTxManager(router, usecase.IsolationReadCommitted, 0)
+
+	// The job is rolled back with the domain write
+	errAbort := errors.New("abort")
+	err := txManager.WithinTx(ctx, usecase.TxOptions{}, func(ctx context.Context) error {
+		if _, err := vocabularies.Insert(ctx, &domain.Vocabulary{Title: "apple", Meaning: "fruit", Sentence: "s"}); err != nil {
+			return err
+		}
+		if _, err := jobs.EnqueueJob(ctx, &usecase.Job{Kind: "greet", Payload: []byte(`{}`)}); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("WithinTx = %v, want errAbort", err)
+	}
+
+	claimed, err := jobs.ClaimJobs(ctx, []string{"greet"}, 10, time.Minute)
+	if err != nil || len(claimed) != 0 {
+		t.Errorf("ClaimJobs after the rollback = %d jobs, %v, want none", len(claimed), err)
+	}
+}
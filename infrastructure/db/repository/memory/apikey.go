@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/domain"
+)
+
+// APIKeyRepository keeps API keys in process memory. They are lost when the process exits.
+type APIKeyRepository struct {
+	mu     sync.RWMutex
+	nextID int64
+	keys   []domain.APIKey
+}
+
+func NewAPIKeyRepository() *APIKeyRepository {
+	return &APIKeyRepository{
+		nextID: 1,
+	}
+}
+
+func (r *APIKeyRepository) InsertAPIKey(ctx context.Context, key *domain.APIKey) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *key
+	stored.KeyID = r.nextID
+	stored.Scopes = slices.Clone(key.Scopes)
+	if !stored.ExpiresAt.IsZero() {
+		// Keep the precision of the database backends
+		stored.ExpiresAt = stored.ExpiresAt.UTC().Truncate(time.Microsecond)
+	}
+	r.nextID++
+	r.keys = append(r.keys, stored)
+
+	slog.InfoContext(ctx, "new api key was inserted successfully", slog.Int64("keyID", stored.KeyID), slog.String("prefix", key.Prefix))
+	return stored.KeyID, nil
+}
+
+func (r *APIKeyRepository) SelectAPIKeyByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := slices.IndexFunc(r.keys, func(key domain.APIKey) bool { return key.Prefix == prefix })
+	if i < 0 {
+		slog.WarnContext(ctx, "no api key found", slog.String("prefix", prefix))
+		return nil, domain.ErrAPIKeyNotFound
+	}
+
+	return cloneAPIKey(r.keys[i]), nil
+}
+
+func (r *APIKeyRepository) SelectAllAPIKeys(_ context.Context) ([]*domain.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]*domain.APIKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, cloneAPIKey(key))
+	}
+	return keys, nil
+}
+
+func (r *APIKeyRepository) RevokeAPIKey(ctx context.Context, keyID int64, revokedAt time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := r.find(keyID)
+	if key == nil {
+		slog.WarnContext(ctx, "no api key was revoked", slog.Int64("keyID", keyID))
+		return 0, domain.ErrAPIKeyNotFound
+	}
+
+	// A key keeps the time of its first revocation
+	if key.RevokedAt.IsZero() {
+		key.RevokedAt = revokedAt.UTC().Truncate(time.Microsecond)
+	}
+
+	slog.InfoContext(ctx, "the api key was revoked successfully", slog.Int64("keyID", keyID))
+	return 1, nil
+}
+
+func (r *APIKeyRepository) UpdateAPIKeyLastUsed(_ context.Context, keyID int64, lastUsedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if key := r.find(keyID); key != nil {
+		key.LastUsedAt = lastUsedAt.UTC().Truncate(time.Microsecond)
+	}
+	return nil
+}
+
+// find returns the stored key of keyID, or nil. The caller holds mu.
+func (r *APIKeyRepository) find(keyID int64) *domain.APIKey {
+	i := slices.IndexFunc(r.keys, func(key domain.APIKey) bool { return key.KeyID == keyID })
+	if i < 0 {
+		return nil
+	}
+	return &r.keys[i]
+}
+
+func cloneAPIKey(key domain.APIKey) *domain.APIKey {
+	key.Scopes = slices.Clone(key.Scopes)
+	return &key
+}
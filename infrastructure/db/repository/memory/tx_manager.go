@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"context"
+	"maps"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+)
+
+// txKey holds the repository whose transaction ctx runs in
+type txKey struct{}
+
+// TxManager runs units of work on a VocabularyRepository one at a time. A unit of work holds the write lock
+// of the repository until it ends, so that nobody sees its writes before the commit, and a rollback restores
+// the state from before it without losing the writes of others.
+// Inside a unit of work, the repository must be called with its ctx.
+type TxManager struct {
+	Repository *VocabularyRepository
+}
+
+func NewTxManager(repository *VocabularyRepository) *TxManager {
+	return &TxManager{
+		Repository: repository,
+	}
+}
+
+func (m *TxManager) WithinTx(ctx context.Context, _ usecase.TxOptions, fn func(ctx context.Context) error) error {
+	r := m.Repository
+
+	// Join the transaction of an outer WithinTx
+	if r.inTx(ctx) {
+		return fn(ctx)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Roll back on an error and on a panic
+	snapshot := r.snapshot()
+	committed := false
+	defer func() {
+		if !committed {
+			r.restore(snapshot)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, r)); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+// inTx reports whether ctx runs in a transaction of the repository
+func (r *VocabularyRepository) inTx(ctx context.Context) bool {
+	return ctx.Value(txKey{}) == r
+}
+
+type state struct {
+	nextNo       int64
+	vocabularies map[int64]domain.Vocabulary
+	titles       map[string]int64
+}
+
+// snapshot copies the state. The caller holds mu.
+func (r *VocabularyRepository) snapshot() state {
+	return state{
+		nextNo:       r.nextNo,
+		vocabularies: maps.Clone(r.vocabularies),
+		titles:       maps.Clone(r.titles),
+	}
+}
+
+// restore puts back a snapshot. The caller holds mu.
+func (r *VocabularyRepository) restore(s state) {
+	r.nextNo = s.nextNo
+	r.vocabularies = s.vocabularies
+	r.titles = s.titles
+}
@@ -0,0 +1,237 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+)
+
+// VocabularyRepository keeps vocabularies in process memory.
+// It is safe for concurrent use and returns the same domain errors as the database backends.
+type VocabularyRepository struct {
+	mu           sync.RWMutex
+	nextNo       int64
+	vocabularies map[int64]domain.Vocabulary
+	titles       map[string]int64
+}
+
+func NewVocabularyRepository() *VocabularyRepository {
+	return &VocabularyRepository{
+		nextNo:       1,
+		vocabularies: make(map[int64]domain.Vocabulary),
+		titles:       make(map[string]int64),
+	}
+}
+
+func (r *VocabularyRepository) Insert(ctx context.Context, vocabulary *domain.Vocabulary) (int64, error) {
+	defer r.lock(ctx)()
+
+	// Titles are unique like the UNIQUE constraint of the vocabularies table
+	if _, ok := r.titles[vocabulary.Title]; ok {
+		slog.WarnContext(ctx, "duplicate vocabulary detected", slog.String("title", vocabulary.Title))
+		return 0, domain.ErrVocabularyDuplicate
+	}
+
+	vocabularyNo := r.nextNo
+	r.nextNo++
+
+	stored := *vocabulary
+	stored.VocabularyNo = vocabularyNo
+	stored.Version = 1
+	stored.CreatedAt = now()
+	stored.UpdatedAt = stored.CreatedAt
+	r.vocabularies[vocabularyNo] = stored
+	r.titles[stored.Title] = vocabularyNo
+
+	slog.InfoContext(ctx, "new vocabulary was inserted successfully", slog.Int64("vocabularyNo", vocabularyNo))
+	return vocabularyNo, nil
+}
+
+func (r *VocabularyRepository) InsertAll(ctx context.Context, vocabularies []*domain.Vocabulary) (int64, error) {
+	defer r.lock(ctx)()
+
+	// Check every title first so that nothing is inserted on a duplicate
+	titles := make(map[string]bool, len(vocabularies))
+	for _, vocabulary := range vocabularies {
+		if _, ok := r.titles[vocabulary.Title]; ok || titles[vocabulary.Title] {
+			slog.WarnContext(ctx, "duplicate vocabulary detected", slog.String("title", vocabulary.Title))
+			return 0, domain.ErrVocabularyDuplicate
+		}
+		titles[vocabulary.Title] = true
+	}
+
+	insertedAt := now()
+	for _, vocabulary := range vocabularies {
+		stored := *vocabulary
+		stored.VocabularyNo = r.nextNo
+		stored.Version = 1
+		stored.CreatedAt = insertedAt
+		stored.UpdatedAt = insertedAt
+		r.nextNo++
+		r.vocabularies[stored.VocabularyNo] = stored
+		r.titles[stored.Title] = stored.VocabularyNo
+		vocabulary.VocabularyNo = stored.VocabularyNo
+	}
+
+	inserted := int64(len(vocabularies))
+	slog.InfoContext(ctx, "new vocabularies were inserted successfully", slog.Int64("count", inserted))
+	return inserted, nil
+}
+
+func (r *VocabularyRepository) SelectByVocabularyNo(ctx context.Context, vocabularyNo int64) (*domain.Vocabulary, error) {
+	defer r.rlock(ctx)()
+
+	stored, ok := r.vocabularies[vocabularyNo]
+	if !ok {
+		slog.WarnContext(ctx, "no vocabulary found", slog.Int64("vocabularyNo", vocabularyNo))
+		return nil, domain.ErrVocabularyNotFound
+	}
+
+	slog.InfoContext(ctx, "vocabulary fetched successfully", slog.Int64("vocabularyNo", vocabularyNo))
+	return &stored, nil
+}
+
+func (r *VocabularyRepository) SelectAll(ctx context.Context, query usecase.VocabularyQuery) ([]*domain.Vocabulary, error) {
+	defer r.rlock(ctx)()
+
+	var vocabularyList []*domain.Vocabulary
+	for _, stored := range r.vocabularies {
+		if !matches(&stored, query) {
+			continue
+		}
+		vocabulary := stored
+		vocabularyList = append(vocabularyList, &vocabulary)
+	}
+
+	// Same order as the ORDER BY of the database backends, which compare the titles byte by byte
+	slices.SortFunc(vocabularyList, func(a, b *domain.Vocabulary) int {
+		for _, s := range query.Sort {
+			var c int
+			switch s.Field {
+			case usecase.SortByTitle:
+				c = strings.Compare(a.Title, b.Title)
+			case usecase.SortByVocabularyNo:
+				c = cmp.Compare(a.VocabularyNo, b.VocabularyNo)
+			case usecase.SortByCreatedAt:
+				c = a.CreatedAt.Compare(b.CreatedAt)
+			case usecase.SortByUpdatedAt:
+				c = a.UpdatedAt.Compare(b.UpdatedAt)
+			}
+			if s.Descending {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return cmp.Compare(a.VocabularyNo, b.VocabularyNo)
+	})
+
+	slog.InfoContext(ctx, "all vocabularies were fetched successfully", slog.Int("count", len(vocabularyList)))
+	return vocabularyList, nil
+}
+
+// matches applies the filters of the query, ignoring the case of ASCII letters only like the database backends
+func matches(vocabulary *domain.Vocabulary, query usecase.VocabularyQuery) bool {
+	if query.TitlePrefix != "" && !strings.HasPrefix(foldASCII(vocabulary.Title), foldASCII(query.TitlePrefix)) {
+		return false
+	}
+	if query.SentenceContains != "" && !strings.Contains(foldASCII(vocabulary.Sentence), foldASCII(query.SentenceContains)) {
+		return false
+	}
+	if !inRange(vocabulary.CreatedAt, query.CreatedFrom, query.CreatedUntil) ||
+		!inRange(vocabulary.UpdatedAt, query.UpdatedFrom, query.UpdatedUntil) {
+		return false
+	}
+	return true
+}
+
+func (r *VocabularyRepository) Update(ctx context.Context, vocabularyNo int64, vocabulary *domain.Vocabulary) (int64, error) {
+	defer r.lock(ctx)()
+
+	current, ok := r.vocabularies[vocabularyNo]
+	if !ok {
+		slog.WarnContext(ctx, "no vocabulary found", slog.Int64("vocabularyNo", vocabularyNo))
+		return 0, domain.ErrVocabularyNotFound
+	}
+
+	// The new title must not be used by another vocabulary
+	if owner, ok := r.titles[vocabulary.Title]; ok && owner != vocabularyNo {
+		slog.WarnContext(ctx, "duplicate vocabulary detected", slog.String("title", vocabulary.Title))
+		return 0, domain.ErrVocabularyDuplicate
+	}
+
+	stored := *vocabulary
+	stored.VocabularyNo = vocabularyNo
+	stored.Version = current.Version + 1
+	stored.CreatedAt = current.CreatedAt
+	stored.CreatedBy = current.CreatedBy
+	stored.UpdatedAt = now()
+	delete(r.titles, current.Title)
+	r.vocabularies[vocabularyNo] = stored
+	r.titles[stored.Title] = vocabularyNo
+
+	slog.InfoContext(ctx, "the vocabulary was updated successfully", slog.Int64("vocabularyNo", vocabularyNo))
+	return vocabularyNo, nil
+}
+
+func (r *VocabularyRepository) Delete(ctx context.Context, vocabularyNo int64) (int64, error) {
+	defer r.lock(ctx)()
+
+	current, ok := r.vocabularies[vocabularyNo]
+	if !ok {
+		slog.WarnContext(ctx, "no vocabulary was deleted", slog.Int64("vocabularyNo", vocabularyNo))
+		return 0, domain.ErrVocabularyNotFound
+	}
+
+	delete(r.vocabularies, vocabularyNo)
+	delete(r.titles, current.Title)
+
+	slog.InfoContext(ctx, "the vocabulary was deleted successfully", slog.Int64("rowsAffected", 1))
+	return 1, nil
+}
+
+// lock takes the write lock and returns its release. A transaction of the repository already holds it.
+func (r *VocabularyRepository) lock(ctx context.Context) func() {
+	if r.inTx(ctx) {
+		return func() {}
+	}
+	r.mu.Lock()
+	return r.mu.Unlock
+}
+
+// rlock takes the read lock and returns its release. A transaction of the repository already holds the write lock.
+func (r *VocabularyRepository) rlock(ctx context.Context) func() {
+	if r.inTx(ctx) {
+		return func() {}
+	}
+	r.mu.RLock()
+	return r.mu.RUnlock
+}
+
+// foldASCII lowers the ASCII letters of s and keeps the other characters
+func foldASCII(s string) string {
+	return strings.Map(func(r rune) rune {
+		if 'A' <= r && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return r
+	}, s)
+}
+
+// inRange reports whether t is in [from, until), where a zero bound is open
+func inRange(t, from, until time.Time) bool {
+	return (from.IsZero() || !t.Before(from)) && (until.IsZero() || t.Before(until))
+}
+
+// now has the precision of the timestamp columns of the database backends
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
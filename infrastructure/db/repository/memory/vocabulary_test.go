@@ -0,0 +1,27 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db/repository/repositorytest"
+)
+
+func TestVocabularyRepository(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) usecase.VocabularyRepository {
+		return NewVocabularyRepository()
+	})
+}
+
+func TestTxManager(t *testing.T) {
+	repositorytest.RunTx(t, func(t *testing.T) (usecase.VocabularyRepository, usecase.TxManager) {
+		repo := NewVocabularyRepository()
+		return repo, NewTxManager(repo)
+	})
+}
+
+func TestAPIKeyRepository(t *testing.T) {
+	repositorytest.RunAPIKeys(t, func(t *testing.T) usecase.APIKeyRepository {
+		return NewAPIKeyRepository()
+	})
+}
@@ -1,9 +1,13 @@
 package model
 
+import "time"
+
 type VocabularyInput struct {
-	Title    string
-	Meaning  string
-	Sentence string
+	Title     string
+	Meaning   string
+	Sentence  string
+	CreatedBy string
+	UpdatedBy string
 }
 
 type VocabularyOutput struct {
@@ -11,4 +15,9 @@ type VocabularyOutput struct {
 	Title        string
 	Meaning      string
 	Sentence     string
+	Version      int64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	CreatedBy    string
+	UpdatedBy    string
 }
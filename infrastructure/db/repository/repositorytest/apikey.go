@@ -0,0 +1,95 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+)
+
+// RunAPIKeys runs the conformance suite of a usecase.APIKeyRepository.
+// newRepository must return an empty repository on every call.
+func RunAPIKeys(t *testing.T, newRepository func(t *testing.T) usecase.APIKeyRepository) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repo usecase.APIKeyRepository)
+	}{
+		{"CreateAndAuthenticate", testAPIKeyCreateAndAuthenticate},
+		{"Revoke", testAPIKeyRevoke},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newRepository(t))
+		})
+	}
+}
+
+func testAPIKeyCreateAndAuthenticate(t *testing.T, repo usecase.APIKeyRepository) {
+	ctx := context.Background()
+	u := usecase.NewAPIKeyUsecase(repo, time.Minute)
+
+	expiresAt := time.Now().Add(time.Hour)
+	key := &domain.APIKey{Name: "importer", Scopes: []domain.Scope{domain.ScopeVocabRead, domain.ScopeVocabWrite}, ExpiresAt: expiresAt}
+	token, err := u.CreateAPIKey(ctx, key)
+	if err != nil {
+		t.Fatalf("CreateAPIKey returned an error: %v", err)
+	}
+
+	authenticated, err := u.Authenticate(ctx, token)
+	if err != nil {
+		t.Fatalf("Authenticate returned an error: %v", err)
+	}
+	if authenticated.KeyID != key.KeyID || authenticated.Name != "importer" || !slices.Equal(authenticated.Scopes, key.Scopes) ||
+		!authenticated.ExpiresAt.Equal(expiresAt.Truncate(time.Microsecond)) || !authenticated.RevokedAt.IsZero() {
+		t.Errorf("authenticated = %+v", authenticated)
+	}
+
+	// The last use is stored
+	keys, err := u.FetchAPIKeyList(ctx)
+	if err != nil || len(keys) != 1 || keys[0].LastUsedAt.IsZero() || keys[0].Prefix != key.Prefix {
+		t.Fatalf("FetchAPIKeyList = %+v, %v", keys, err)
+	}
+
+	if _, err := u.Authenticate(ctx, token[:len(token)-1]+"x"); !errors.Is(err, usecase.ErrInvalidAPIKey) {
+		t.Errorf("Authenticate of a wrong secret = %v, want ErrInvalidAPIKey", err)
+	}
+}
+
+func testAPIKeyRevoke(t *testing.T, repo usecase.APIKeyRepository) {
+	ctx := context.Background()
+	u := usecase.NewAPIKeyUsecase(repo, time.Minute)
+
+	key := &domain.APIKey{Name: "importer", Scopes: []domain.Scope{domain.ScopeVocabRead}}
+	token, err := u.CreateAPIKey(ctx, key)
+	if err != nil {
+		t.Fatalf("CreateAPIKey returned an error: %v", err)
+	}
+
+	if _, err := u.RevokeAPIKey(ctx, key.KeyID); err != nil {
+		t.Fatalf("RevokeAPIKey returned an error: %v", err)
+	}
+	if _, err := u.Authenticate(ctx, token); !errors.Is(err, usecase.ErrInvalidAPIKey) {
+		t.Errorf("Authenticate of a revoked key = %v, want ErrInvalidAPIKey", err)
+	}
+	if _, err := u.RevokeAPIKey(ctx, key.KeyID+1); !errors.Is(err, domain.ErrAPIKeyNotFound) {
+		t.Errorf("RevokeAPIKey of an unknown key = %v, want ErrAPIKeyNotFound", err)
+	}
+
+	// Revoking again keeps the first time
+	keys, err := u.FetchAPIKeyList(ctx)
+	if err != nil || len(keys) != 1 || keys[0].RevokedAt.IsZero() {
+		t.Fatalf("FetchAPIKeyList = %+v, %v", keys, err)
+	}
+	if _, err := u.RevokeAPIKey(ctx, key.KeyID); err != nil {
+		t.Fatalf("RevokeAPIKey returned an error: %v", err)
+	}
+	again, err := u.FetchAPIKeyList(ctx)
+	if err != nil || !again[0].RevokedAt.Equal(keys[0].RevokedAt) {
+		t.Errorf("revoked_at moved from %v to %+v, %v", keys[0].RevokedAt, again, err)
+	}
+}
@@ -0,0 +1,527 @@
+// Package repositorytest provides a conformance suite that every
+// usecase.VocabularyRepository implementation must pass.
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+)
+
+// Run runs the conformance suite. newRepository must return an empty repository on every call.
+func Run(t *testing.T, newRepository func(t *testing.T) usecase.VocabularyRepository) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repo usecase.VocabularyRepository)
+	}{
+		{"InsertAndSelect", testInsertAndSelect},
+		{"InsertDuplicate", testInsertDuplicate},
+		{"InsertAll", testInsertAll},
+		{"InsertAllDuplicate", testInsertAllDuplicate},
+		{"SelectNotFound", testSelectNotFound},
+		{"SelectAllOrder", testSelectAllOrder},
+		{"SelectAllEmpty", testSelectAllEmpty},
+		{"SelectAllSort", testSelectAllSort},
+		{"SelectAllFilter", testSelectAllFilter},
+		{"SelectAllCollation", testSelectAllCollation},
+		{"SelectAllTimeRange", testSelectAllTimeRange},
+		{"Update", testUpdate},
+		{"UpdateTimestamps", testUpdateTimestamps},
+		{"UpdateNotFound", testUpdateNotFound},
+		{"UpdateDuplicate", testUpdateDuplicate},
+		{"Delete", testDelete},
+		{"DeleteNotFound", testDeleteNotFound},
+		{"ConcurrentInsert", testConcurrentInsert},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newRepository(t))
+		})
+	}
+}
+
+func newVocabulary(title string) *domain.Vocabulary {
+	return &domain.Vocabulary{
+		Title:     title,
+		Meaning:   "meaning of " + title,
+		Sentence:  "sentence of " + title,
+		CreatedBy: "tester",
+		UpdatedBy: "tester",
+	}
+}
+
+func mustInsert(t *testing.T, repo usecase.VocabularyRepository, title string) int64 {
+	t.Helper()
+
+	vocabularyNo, err := repo.Insert(context.Background(), newVocabulary(title))
+	if err != nil {
+		t.Fatalf("Insert(%q) returned an error: %v", title, err)
+	}
+
+	return vocabularyNo
+}
+
+func testInsertAndSelect(t *testing.T, repo usecase.VocabularyRepository) {
+	ctx := context.Background()
+	vocabularyNo := mustInsert(t, repo, "apple")
+
+	got, err := repo.SelectByVocabularyNo(ctx, vocabularyNo)
+	if err != nil {
+		t.Fatalf("SelectByVocabularyNo returned an error: %v", err)
+	}
+
+	// Both timestamps are set to the time of the insert
+	if got.CreatedAt.IsZero() || !got.UpdatedAt.Equal(got.CreatedAt) {
+		t.Errorf("CreatedAt = %v, UpdatedAt = %v, want the same non-zero time", got.CreatedAt, got.UpdatedAt)
+	}
+	got.CreatedAt, got.UpdatedAt = time.Time{}, time.Time{}
+
+	want := newVocabulary("apple")
+	want.VocabularyNo = vocabularyNo
+	want.Version = 1
+	want.CreatedBy = "tester"
+	want.UpdatedBy = "tester"
+	if *got != *want {
+		t.Errorf("SelectByVocabularyNo = %+v, want %+v", got, want)
+	}
+}
+
+func testInsertDuplicate(t *testing.T, repo usecase.VocabularyRepository) {
+	mustInsert(t, repo, "apple")
+
+	_, err := repo.Insert(context.Background(), newVocabulary("apple"))
+	if !errors.Is(err, domain.ErrVocabularyDuplicate) {
+		t.Errorf("Insert with a duplicate title returned %v, want %v", err, domain.ErrVocabularyDuplicate)
+	}
+}
+
+func testInsertAll(t *testing.T, repo usecase.VocabularyRepository) {
+	ctx := context.Background()
+	titles := []string{"cherry", "apple", "banana"}
+	var vocabularies []*domain.Vocabulary
+	for _, title := range titles {
+		vocabularies = append(vocabularies, newVocabulary(title))
+	}
+
+	inserted, err := repo.InsertAll(ctx, vocabularies)
+	if err != nil {
+		t.Fatalf("InsertAll returned an error: %v", err)
+	}
+	if inserted != int64(len(titles)) {
+		t.Errorf("InsertAll returned %d, want %d", inserted, len(titles))
+	}
+
+	// The rows keep the order of the slice
+	got, err := repo.SelectAll(ctx, usecase.VocabularyQuery{})
+	if err != nil {
+		t.Fatalf("SelectAll returned an error: %v", err)
+	}
+	if len(got) != len(titles) {
+		t.Fatalf("SelectAll returned %d vocabularies, want %d", len(got), len(titles))
+	}
+	for i, vocabulary := range got {
+		if vocabulary.Title != titles[i] || vocabulary.Sentence != "sentence of "+titles[i] || vocabulary.Version != 1 {
+			t.Errorf("SelectAll()[%d] = %+v, want the title %s", i, vocabulary, titles[i])
+		}
+
+		// InsertAll sets the numbers of the inserted vocabularies
+		if vocabularies[i].VocabularyNo != vocabulary.VocabularyNo {
+			t.Errorf("VocabularyNo of %s = %d, want %d", titles[i], vocabularies[i].VocabularyNo, vocabulary.VocabularyNo)
+		}
+	}
+}
+
+func testInsertAllDuplicate(t *testing.T, repo usecase.VocabularyRepository) {
+	ctx := context.Background()
+	mustInsert(t, repo, "apple")
+
+	for name, titles := range map[string][]string{
+		"registered title":   {"banana", "apple"},
+		"title in the batch": {"cherry", "cherry"},
+	} {
+		var vocabularies []*domain.Vocabulary
+		for _, title := range titles {
+			vocabularies = append(vocabularies, newVocabulary(title))
+		}
+
+		_, err := repo.InsertAll(ctx, vocabularies)
+		if !errors.Is(err, domain.ErrVocabularyDuplicate) {
+			t.Errorf("InsertAll with a duplicate %s returned %v, want %v", name, err, domain.ErrVocabularyDuplicate)
+		}
+	}
+
+	// Nothing of the failed batches is inserted
+	got, err := repo.SelectAll(ctx, usecase.VocabularyQuery{})
+	if err != nil {
+		t.Fatalf("SelectAll returned an error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("SelectAll returned %d vocabularies after failed InsertAll, want 1", len(got))
+	}
+}
+
+func testSelectNotFound(t *testing.T, repo usecase.VocabularyRepository) {
+	_, err := repo.SelectByVocabularyNo(context.Background(), 9999)
+	if !errors.Is(err, domain.ErrVocabularyNotFound) {
+		t.Errorf("SelectByVocabularyNo returned %v, want %v", err, domain.ErrVocabularyNotFound)
+	}
+}
+
+func testSelectAllOrder(t *testing.T, repo usecase.VocabularyRepository) {
+	titles := []string{"cherry", "apple", "banana"}
+	var numbers []int64
+	for _, title := range titles {
+		numbers = append(numbers, mustInsert(t, repo, title))
+	}
+
+	got, err := repo.SelectAll(context.Background(), usecase.VocabularyQuery{})
+	if err != nil {
+		t.Fatalf("SelectAll returned an error: %v", err)
+	}
+
+	if len(got) != len(titles) {
+		t.Fatalf("SelectAll returned %d vocabularies, want %d", len(got), len(titles))
+	}
+
+	// Vocabularies are ordered by vocabularyNo, which follows the insertion order
+	for i, vocabulary := range got {
+		if vocabulary.VocabularyNo != numbers[i] || vocabulary.Title != titles[i] {
+			t.Errorf("SelectAll()[%d] = {%d %s}, want {%d %s}", i, vocabulary.VocabularyNo, vocabulary.Title, numbers[i], titles[i])
+		}
+	}
+}
+
+// selectTitles returns the titles selected by the query in order
+func selectTitles(t *testing.T, repo usecase.VocabularyRepository, query usecase.VocabularyQuery) []string {
+	t.Helper()
+
+	got, err := repo.SelectAll(context.Background(), query)
+	if err != nil {
+		t.Fatalf("SelectAll(%+v) returned an error: %v", query, err)
+	}
+
+	titles := []string{}
+	for _, vocabulary := range got {
+		titles = append(titles, vocabulary.Title)
+	}
+	return titles
+}
+
+func testSelectAllSort(t *testing.T, repo usecase.VocabularyRepository) {
+	for _, title := range []string{"cherry", "apple", "banana"} {
+		mustInsert(t, repo, title)
+	}
+
+	tests := []struct {
+		sort string
+		want []string
+	}{
+		{"title", []string{"apple", "banana", "cherry"}},
+		{"-title", []string{"cherry", "banana", "apple"}},
+		{"vocabulary_no", []string{"cherry", "apple", "banana"}},
+		{"-vocabulary_no", []string{"banana", "apple", "cherry"}},
+	}
+
+	for _, tt := range tests {
+		sorts, err := usecase.ParseVocabularySort(tt.sort)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := selectTitles(t, repo, usecase.VocabularyQuery{Sort: sorts}); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("sort=%s: got %v, want %v", tt.sort, got, tt.want)
+		}
+	}
+}
+
+func testSelectAllFilter(t *testing.T, repo usecase.VocabularyRepository) {
+	ctx := context.Background()
+	for _, v := range []*domain.Vocabulary{
+		{Title: "apple", Meaning: "m", Sentence: "An apple a day."},
+		{Title: "apricot", Meaning: "m", Sentence: "Dried APRICOTS are sweet."},
+		{Title: "a_b", Meaning: "m", Sentence: "It costs 100% more."},
+		{Title: "banana", Meaning: "m", Sentence: "A ripe banana."},
+	} {
+		if _, err := repo.Insert(ctx, v); err != nil {
+			t.Fatalf("Insert(%q) returned an error: %v", v.Title, err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query usecase.VocabularyQuery
+		want  []string
+	}{
+		{"title prefix", usecase.VocabularyQuery{TitlePrefix: "ap"}, []string{"apple", "apricot"}},
+		{"title prefix ignores case", usecase.VocabularyQuery{TitlePrefix: "AP"}, []string{"apple", "apricot"}},
+		{"title prefix is not a pattern", usecase.VocabularyQuery{TitlePrefix: "a_"}, []string{"a_b"}},
+		{"sentence contains", usecase.VocabularyQuery{SentenceContains: "apricot"}, []string{"apricot"}},
+		{"sentence contains is not a pattern", usecase.VocabularyQuery{SentenceContains: "%"}, []string{"a_b"}},
+		{"both filters", usecase.VocabularyQuery{TitlePrefix: "a", SentenceContains: "day"}, []string{"apple"}},
+		{"no match", usecase.VocabularyQuery{TitlePrefix: "z"}, []string{}},
+		{
+			"filter and sort",
+			usecase.VocabularyQuery{TitlePrefix: "ap", Sort: []usecase.VocabularySort{{Field: usecase.SortByTitle, Descending: true}}},
+			[]string{"apricot", "apple"},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := selectTitles(t, repo, tt.query); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// testSelectAllCollation pins the order and the case folding of non-ASCII titles, which every backend must share
+func testSelectAllCollation(t *testing.T, repo usecase.VocabularyRepository) {
+	for _, title := range []string{"éclair", "zebra", "Äpfel", "apple", "Banana"} {
+		mustInsert(t, repo, title)
+	}
+
+	tests := []struct {
+		name  string
+		query usecase.VocabularyQuery
+		want  []string
+	}{
+		{
+			"titles are sorted by their bytes",
+			usecase.VocabularyQuery{Sort: []usecase.VocabularySort{{Field: usecase.SortByTitle}}},
+			[]string{"Banana", "apple", "zebra", "Äpfel", "éclair"},
+		},
+		{"ASCII case is ignored", usecase.VocabularyQuery{TitlePrefix: "b"}, []string{"Banana"}},
+		{"non-ASCII letters match exactly", usecase.VocabularyQuery{TitlePrefix: "Ä"}, []string{"Äpfel"}},
+		{"non-ASCII case is not ignored", usecase.VocabularyQuery{TitlePrefix: "ä"}, []string{}},
+		{"sentence ignores ASCII case", usecase.VocabularyQuery{SentenceContains: "OF é"}, []string{"éclair"}},
+		{"sentence keeps non-ASCII case", usecase.VocabularyQuery{SentenceContains: "of É"}, []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := selectTitles(t, repo, tt.query); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func testSelectAllTimeRange(t *testing.T, repo usecase.VocabularyRepository) {
+	ctx := context.Background()
+
+	// Leave a gap between the writes so that every one of them gets a distinct time
+	tick := func() time.Time {
+		time.Sleep(2 * time.Millisecond)
+		now := time.Now()
+		time.Sleep(2 * time.Millisecond)
+		return now
+	}
+
+	start := tick()
+	appleNo := mustInsert(t, repo, "apple")
+	afterApple := tick()
+	mustInsert(t, repo, "banana")
+	afterBanana := tick()
+	if _, err := repo.Update(ctx, appleNo, newVocabulary("apple")); err != nil {
+		t.Fatalf("Update returned an error: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		query usecase.VocabularyQuery
+		want  []string
+	}{
+		{"created from", usecase.VocabularyQuery{CreatedFrom: afterApple}, []string{"banana"}},
+		{"created until", usecase.VocabularyQuery{CreatedUntil: afterApple}, []string{"apple"}},
+		{"created range", usecase.VocabularyQuery{CreatedFrom: start, CreatedUntil: afterBanana}, []string{"apple", "banana"}},
+		{"updated from", usecase.VocabularyQuery{UpdatedFrom: afterBanana}, []string{"apple"}},
+		{"updated until", usecase.VocabularyQuery{UpdatedUntil: afterBanana}, []string{"banana"}},
+		{"empty range", usecase.VocabularyQuery{CreatedFrom: afterBanana}, []string{}},
+		{
+			"sort by created_at",
+			usecase.VocabularyQuery{Sort: []usecase.VocabularySort{{Field: usecase.SortByCreatedAt, Descending: true}}},
+			[]string{"banana", "apple"},
+		},
+		{
+			"sort by updated_at",
+			usecase.VocabularyQuery{Sort: []usecase.VocabularySort{{Field: usecase.SortByUpdatedAt, Descending: true}}},
+			[]string{"apple", "banana"},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := selectTitles(t, repo, tt.query); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func testSelectAllEmpty(t *testing.T, repo usecase.VocabularyRepository) {
+	got, err := repo.SelectAll(context.Background(), usecase.VocabularyQuery{})
+	if err != nil {
+		t.Fatalf("SelectAll returned an error: %v", err)
+	}
+
+	if len(got) != 0 {
+		t.Errorf("SelectAll returned %d vocabularies, want 0", len(got))
+	}
+}
+
+func testUpdate(t *testing.T, repo usecase.VocabularyRepository) {
+	ctx := context.Background()
+	vocabularyNo := mustInsert(t, repo, "apple")
+
+	updated, err := repo.Update(ctx, vocabularyNo, newVocabulary("apricot"))
+	if err != nil {
+		t.Fatalf("Update returned an error: %v", err)
+	}
+	if updated != vocabularyNo {
+		t.Errorf("Update returned %d, want %d", updated, vocabularyNo)
+	}
+
+	got, err := repo.SelectByVocabularyNo(ctx, vocabularyNo)
+	if err != nil {
+		t.Fatalf("SelectByVocabularyNo returned an error: %v", err)
+	}
+	if got.Title != "apricot" || got.Meaning != "meaning of apricot" {
+		t.Errorf("SelectByVocabularyNo after Update = %+v", got)
+	}
+	if got.Version != 2 {
+		t.Errorf("Version after Update = %d, want 2", got.Version)
+	}
+
+	// The old title can be registered again
+	mustInsert(t, repo, "apple")
+}
+
+func testUpdateTimestamps(t *testing.T, repo usecase.VocabularyRepository) {
+	ctx := context.Background()
+	vocabularyNo := mustInsert(t, repo, "apple")
+
+	inserted, err := repo.SelectByVocabularyNo(ctx, vocabularyNo)
+	if err != nil {
+		t.Fatalf("SelectByVocabularyNo returned an error: %v", err)
+	}
+
+	time.Sleep(2 * time.Millisecond)
+	vocabulary := newVocabulary("apple")
+	vocabulary.CreatedBy = "editor"
+	vocabulary.UpdatedBy = "editor"
+	if _, err := repo.Update(ctx, vocabularyNo, vocabulary); err != nil {
+		t.Fatalf("Update returned an error: %v", err)
+	}
+
+	got, err := repo.SelectByVocabularyNo(ctx, vocabularyNo)
+	if err != nil {
+		t.Fatalf("SelectByVocabularyNo returned an error: %v", err)
+	}
+
+	// The creation is kept and the update is recorded
+	if !got.CreatedAt.Equal(inserted.CreatedAt) || got.CreatedBy != "tester" {
+		t.Errorf("created = %v by %q, want %v by tester", got.CreatedAt, got.CreatedBy, inserted.CreatedAt)
+	}
+	if !got.UpdatedAt.After(inserted.UpdatedAt) || got.UpdatedBy != "editor" {
+		t.Errorf("updated = %v by %q, want after %v by editor", got.UpdatedAt, got.UpdatedBy, inserted.UpdatedAt)
+	}
+}
+
+func testUpdateNotFound(t *testing.T, repo usecase.VocabularyRepository) {
+	_, err := repo.Update(context.Background(), 9999, newVocabulary("apple"))
+	if !errors.Is(err, domain.ErrVocabularyNotFound) {
+		t.Errorf("Update returned %v, want %v", err, domain.ErrVocabularyNotFound)
+	}
+}
+
+func testUpdateDuplicate(t *testing.T, repo usecase.VocabularyRepository) {
+	ctx := context.Background()
+	mustInsert(t, repo, "apple")
+	vocabularyNo := mustInsert(t, repo, "banana")
+
+	_, err := repo.Update(ctx, vocabularyNo, newVocabulary("apple"))
+	if !errors.Is(err, domain.ErrVocabularyDuplicate) {
+		t.Errorf("Update with a duplicate title returned %v, want %v", err, domain.ErrVocabularyDuplicate)
+	}
+
+	// Keeping its own title is not a conflict
+	if _, err := repo.Update(ctx, vocabularyNo, newVocabulary("banana")); err != nil {
+		t.Errorf("Update with the same title returned an error: %v", err)
+	}
+}
+
+func testDelete(t *testing.T, repo usecase.VocabularyRepository) {
+	ctx := context.Background()
+	vocabularyNo := mustInsert(t, repo, "apple")
+
+	rowsAffected, err := repo.Delete(ctx, vocabularyNo)
+	if err != nil {
+		t.Fatalf("Delete returned an error: %v", err)
+	}
+	if rowsAffected != 1 {
+		t.Errorf("Delete returned %d, want 1", rowsAffected)
+	}
+
+	_, err = repo.SelectByVocabularyNo(ctx, vocabularyNo)
+	if !errors.Is(err, domain.ErrVocabularyNotFound) {
+		t.Errorf("SelectByVocabularyNo after Delete returned %v, want %v", err, domain.ErrVocabularyNotFound)
+	}
+}
+
+func testDeleteNotFound(t *testing.T, repo usecase.VocabularyRepository) {
+	_, err := repo.Delete(context.Background(), 9999)
+	if !errors.Is(err, domain.ErrVocabularyNotFound) {
+		t.Errorf("Delete returned %v, want %v", err, domain.ErrVocabularyNotFound)
+	}
+}
+
+func testConcurrentInsert(t *testing.T, repo usecase.VocabularyRepository) {
+	ctx := context.Background()
+	const workers = 10
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	inserted, duplicates := 0, 0
+	for i := 0; i < workers; i++ {
+		wg.Add(2)
+
+		// Distinct titles must all be inserted
+		go func() {
+			defer wg.Done()
+			if _, err := repo.Insert(ctx, newVocabulary(fmt.Sprintf("word%d", i))); err != nil {
+				t.Errorf("Insert returned an error: %v", err)
+			}
+		}()
+
+		// Only one of the writers of the same title may win
+		go func() {
+			defer wg.Done()
+			_, err := repo.Insert(ctx, newVocabulary("shared"))
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				inserted++
+			case errors.Is(err, domain.ErrVocabularyDuplicate):
+				duplicates++
+			default:
+				t.Errorf("Insert returned an unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if inserted != 1 || duplicates != workers-1 {
+		t.Errorf("concurrent Insert of the same title: %d inserted, %d duplicates, want 1 and %d", inserted, duplicates, workers-1)
+	}
+
+	got, err := repo.SelectAll(ctx, usecase.VocabularyQuery{})
+	if err != nil {
+		t.Fatalf("SelectAll returned an error: %v", err)
+	}
+	if len(got) != workers+1 {
+		t.Errorf("SelectAll returned %d vocabularies, want %d", len(got), workers+1)
+	}
+}
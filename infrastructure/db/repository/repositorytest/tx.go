@@ -0,0 +1,195 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+)
+
+var errAbort = errors.New("abort the unit of work")
+
+// RunTx runs the conformance suite of a usecase.TxManager and the repository joining its transactions.
+// newBackend must return an empty repository on every call.
+func RunTx(t *testing.T, newBackend func(t *testing.T) (usecase.VocabularyRepository, usecase.TxManager)) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repo usecase.VocabularyRepository, txManager usecase.TxManager)
+	}{
+		{"Commit", testTxCommit},
+		{"Rollback", testTxRollback},
+		{"Nested", testTxNested},
+		{"FailedStatement", testTxFailedStatement},
+		{"Isolation", testTxIsolation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, txManager := newBackend(t)
+			tt.fn(t, repo, txManager)
+		})
+	}
+}
+
+func countVocabularies(t *testing.T, repo usecase.VocabularyRepository) int {
+	t.Helper()
+
+	vocabularyList, err := repo.SelectAll(context.Background(), usecase.VocabularyQuery{})
+	if err != nil {
+		t.Fatalf("SelectAll returned an error: %v", err)
+	}
+
+	return len(vocabularyList)
+}
+
+func testTxCommit(t *testing.T, repo usecase.VocabularyRepository, txManager usecase.TxManager) {
+	err := txManager.WithinTx(context.Background(), usecase.TxOptions{}, func(ctx context.Context) error {
+		vocabularyNo, err := repo.Insert(ctx, newVocabulary("apple"))
+		if err != nil {
+			return err
+		}
+		if _, err := repo.Update(ctx, vocabularyNo, newVocabulary("apricot")); err != nil {
+			return err
+		}
+
+		// The transaction sees its own writes
+		got, err := repo.SelectByVocabularyNo(ctx, vocabularyNo)
+		if err != nil {
+			return err
+		}
+		if got.Title != "apricot" {
+			t.Errorf("SelectByVocabularyNo in the transaction = %+v, want the title apricot", got)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithinTx returned an error: %v", err)
+	}
+
+	if n := countVocabularies(t, repo); n != 1 {
+		t.Errorf("SelectAll after the commit returned %d vocabularies, want 1", n)
+	}
+}
+
+func testTxRollback(t *testing.T, repo usecase.VocabularyRepository, txManager usecase.TxManager) {
+	mustInsert(t, repo, "apple")
+
+	opts := usecase.TxOptions{Isolation: usecase.IsolationSerializable}
+	err := txManager.WithinTx(context.Background(), opts, func(ctx context.Context) error {
+		if _, err := repo.Insert(ctx, newVocabulary("banana")); err != nil {
+			return err
+		}
+		if _, err := repo.InsertAll(ctx, []*domain.Vocabulary{newVocabulary("cherry")}); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("WithinTx returned %v, want %v", err, errAbort)
+	}
+
+	if n := countVocabularies(t, repo); n != 1 {
+		t.Errorf("SelectAll after the rollback returned %d vocabularies, want 1", n)
+	}
+}
+
+func testTxNested(t *testing.T, repo usecase.VocabularyRepository, txManager usecase.TxManager) {
+	err := txManager.WithinTx(context.Background(), usecase.TxOptions{}, func(ctx context.Context) error {
+		err := txManager.WithinTx(ctx, usecase.TxOptions{}, func(ctx context.Context) error {
+			_, err := repo.Insert(ctx, newVocabulary("apple"))
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("WithinTx returned %v, want %v", err, errAbort)
+	}
+
+	// The inner unit of work is rolled back with the outer one
+	if n := countVocabularies(t, repo); n != 0 {
+		t.Errorf("SelectAll after the rollback returned %d vocabularies, want 0", n)
+	}
+}
+
+func testTxFailedStatement(t *testing.T, repo usecase.VocabularyRepository, txManager usecase.TxManager) {
+	mustInsert(t, repo, "apple")
+
+	err := txManager.WithinTx(context.Background(), usecase.TxOptions{}, func(ctx context.Context) error {
+		if _, err := repo.Insert(ctx, newVocabulary("banana")); err != nil {
+			return err
+		}
+
+		// A failed repository call can be handled without aborting the transaction
+		_, err := repo.InsertAll(ctx, []*domain.Vocabulary{newVocabulary("cherry"), newVocabulary("apple")})
+		if !errors.Is(err, domain.ErrVocabularyDuplicate) {
+			t.Errorf("InsertAll with a duplicate title returned %v, want %v", err, domain.ErrVocabularyDuplicate)
+		}
+		if _, err := repo.Insert(ctx, newVocabulary("apple")); !errors.Is(err, domain.ErrVocabularyDuplicate) {
+			t.Errorf("Insert with a duplicate title returned %v, want %v", err, domain.ErrVocabularyDuplicate)
+		}
+
+		_, err = repo.Insert(ctx, newVocabulary("durian"))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("WithinTx returned an error: %v", err)
+	}
+
+	// apple, banana and durian; cherry was rolled back with the failed InsertAll
+	if n := countVocabularies(t, repo); n != 3 {
+		t.Errorf("SelectAll after the commit returned %d vocabularies, want 3", n)
+	}
+}
+
+// Nobody sees the writes of a transaction before it ends, and its rollback keeps the writes of others
+func testTxIsolation(t *testing.T, repo usecase.VocabularyRepository, txManager usecase.TxManager) {
+	var wg sync.WaitGroup
+	err := txManager.WithinTx(context.Background(), usecase.TxOptions{}, func(ctx context.Context) error {
+		if _, err := repo.Insert(ctx, newVocabulary("apple")); err != nil {
+			return err
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if _, err := repo.Insert(context.Background(), newVocabulary("banana")); err != nil {
+				t.Errorf("Insert outside the transaction returned an error: %v", err)
+			}
+			vocabularyList, err := repo.SelectAll(context.Background(), usecase.VocabularyQuery{})
+			if err != nil {
+				t.Errorf("SelectAll outside the transaction returned an error: %v", err)
+			}
+			for _, vocabulary := range vocabularyList {
+				if vocabulary.Title == "apple" {
+					t.Error("SelectAll outside the transaction saw its uncommitted write")
+				}
+			}
+		}()
+
+		// Give the other writer time to run before the rollback
+		time.Sleep(50 * time.Millisecond)
+		return errAbort
+	})
+	wg.Wait()
+
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("WithinTx returned %v, want errAbort", err)
+	}
+
+	vocabularyList, err := repo.SelectAll(context.Background(), usecase.VocabularyQuery{})
+	if err != nil {
+		t.Fatalf("SelectAll returned an error: %v", err)
+	}
+	if len(vocabularyList) != 1 || vocabularyList[0].Title != "banana" {
+		t.Errorf("SelectAll after the rollback = %+v, want only banana", vocabularyList)
+	}
+}
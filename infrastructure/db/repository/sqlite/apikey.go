@@ -0,0 +1,160 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/domain"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db"
+)
+
+// Columns scanned by scanAPIKey
+const apiKeyColumns = "key_id, name, prefix, secret_hash, scopes, created_at, expires_at, last_used_at, revoked_at"
+
+// APIKeyRepository keeps the API keys. The scopes are stored separated by spaces.
+type APIKeyRepository struct {
+	Db *sql.DB
+}
+
+func NewAPIKeyRepository(db *sql.DB) *APIKeyRepository {
+	return &APIKeyRepository{
+		Db: db,
+	}
+}
+
+func (r *APIKeyRepository) InsertAPIKey(ctx context.Context, key *domain.APIKey) (int64, error) {
+	scopes := make([]string, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+
+	var keyID int64
+	err := r.Db.QueryRowContext(
+		ctx,
+		"INSERT INTO api_keys (name, prefix, secret_hash, scopes, created_at, expires_at) "+
+			"VALUES (?, ?, ?, ?, ?, ?) RETURNING key_id",
+		key.Name, key.Prefix, key.SecretHash, strings.Join(scopes, " "), formatTime(key.CreatedAt), formatTime(key.ExpiresAt),
+	).Scan(&keyID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to insert an api key", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	slog.InfoContext(ctx, "new api key was inserted successfully", slog.Int64("keyID", keyID), slog.String("prefix", key.Prefix))
+	return keyID, nil
+}
+
+func (r *APIKeyRepository) SelectAPIKeyByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	key, err := scanAPIKey(r.Db.QueryRowContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE prefix = ?", prefix))
+
+	if errors.Is(err, sql.ErrNoRows) {
+		slog.WarnContext(ctx, "no api key found", slog.String("prefix", prefix))
+		return nil, domain.ErrAPIKeyNotFound
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get the api key", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (r *APIKeyRepository) SelectAllAPIKeys(ctx context.Context) ([]*domain.APIKey, error) {
+	rows, err := r.Db.QueryContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys ORDER BY key_id")
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to query all api keys", slog.String("error", err.Error()))
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []*domain.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to scan an api key row", slog.String("error", err.Error()))
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		slog.ErrorContext(ctx, "failed to iterate api key rows", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (r *APIKeyRepository) RevokeAPIKey(ctx context.Context, keyID int64, revokedAt time.Time) (int64, error) {
+	result, err := r.Db.ExecContext(
+		ctx, "UPDATE api_keys SET revoked_at = CASE revoked_at WHEN '' THEN ? ELSE revoked_at END WHERE key_id = ?",
+		formatTime(revokedAt), keyID,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to revoke the api key", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get a rows affected", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	if rowsAffected == 0 {
+		slog.WarnContext(ctx, "no api key was revoked", slog.Int64("keyID", keyID))
+		return 0, domain.ErrAPIKeyNotFound
+	}
+
+	slog.InfoContext(ctx, "the api key was revoked successfully", slog.Int64("keyID", keyID))
+	return rowsAffected, nil
+}
+
+func (r *APIKeyRepository) UpdateAPIKeyLastUsed(ctx context.Context, keyID int64, lastUsedAt time.Time) error {
+	_, err := r.Db.ExecContext(ctx, "UPDATE api_keys SET last_used_at = ? WHERE key_id = ?", formatTime(lastUsedAt), keyID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to update the last use of the api key", slog.String("error", err.Error()))
+	}
+	return err
+}
+
+// scanAPIKey copies a row of apiKeyColumns into an API key
+func scanAPIKey(row interface{ Scan(dest ...any) error }) (*domain.APIKey, error) {
+	var key domain.APIKey
+	var scopes, createdAt, expiresAt, lastUsedAt, revokedAt string
+
+	err := row.Scan(
+		&key.KeyID, &key.Name, &key.Prefix, &key.SecretHash, &scopes, &createdAt,
+		&expiresAt, &lastUsedAt, &revokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, scope := range strings.Fields(scopes) {
+		key.Scopes = append(key.Scopes, domain.Scope(scope))
+	}
+	for _, t := range []struct {
+		value string
+		dest  *time.Time
+	}{
+		{createdAt, &key.CreatedAt}, {expiresAt, &key.ExpiresAt}, {lastUsedAt, &key.LastUsedAt}, {revokedAt, &key.RevokedAt},
+	} {
+		if *t.dest, err = parseTime(t.value); err != nil {
+			return nil, err
+		}
+	}
+	return &key, nil
+}
+
+// formatTime stores the zero time as an empty string
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(db.SQLiteTimeLayout)
+}
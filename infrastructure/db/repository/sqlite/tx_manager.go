@@ -0,0 +1,56 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+)
+
+type txKey struct{}
+
+// TxManager runs units of work in SQLite transactions.
+// SQLite transactions are always serializable and there is a single writer,
+// so the isolation level is ignored and nothing is retried.
+type TxManager struct {
+	Db *sql.DB
+}
+
+func NewTxManager(db *sql.DB) *TxManager {
+	return &TxManager{
+		Db: db,
+	}
+}
+
+func (m *TxManager) WithinTx(ctx context.Context, _ usecase.TxOptions, fn func(ctx context.Context) error) error {
+	// Join the transaction of an outer WithinTx
+	if _, ok := txFrom(ctx); ok {
+		return fn(ctx)
+	}
+
+	// Begin a transaction
+	tx, err := m.Db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to begin a transaction", slog.String("error", err.Error()))
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		slog.ErrorContext(ctx, "failed to commit the transaction", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func txFrom(ctx context.Context) (*sql.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(*sql.Tx)
+	return tx, ok
+}
@@ -0,0 +1,318 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db/repository/model"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db/repository/sqlquery"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db/repository/transformer"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+type VocabularyRepository struct {
+	Db *sql.DB
+}
+
+func NewVocabularyRepository(db *sql.DB) *VocabularyRepository {
+	return &VocabularyRepository{
+		Db: db,
+	}
+}
+
+func (r *VocabularyRepository) Insert(ctx context.Context, vocabulary *domain.Vocabulary) (int64, error) {
+	// Transform the received domain model into a DB model
+	vocabModel := transformer.ToModel(vocabulary)
+
+	// Execute an insert process
+	insertedAt := now()
+	var vocabularyNo int64
+	err := r.conn(ctx).QueryRowContext(
+		ctx,
+		"INSERT INTO vocabularies(title, meaning, sentence, created_at, updated_at, created_by, updated_by) "+
+			"VALUES(?, ?, ?, ?, ?, ?, ?) ON CONFLICT (title) DO NOTHING RETURNING vocabulary_no",
+		vocabModel.Title, vocabModel.Meaning, vocabModel.Sentence, insertedAt, insertedAt, vocabModel.CreatedBy, vocabModel.UpdatedBy,
+	).Scan(&vocabularyNo)
+
+	// sql.ErrNoRows is returned when an insert proccess is skipped with ON CONFLICT DO NOTHING
+	if errors.Is(err, sql.ErrNoRows) {
+		slog.WarnContext(ctx, "duplicate vocabulary detected", slog.String("title", vocabModel.Title))
+		return 0, domain.ErrVocabularyDuplicate
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to insert a vocabulary", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	slog.InfoContext(ctx, "new vocabulary was inserted successfully", slog.Int64("vocabularyNo", vocabularyNo))
+	return vocabularyNo, nil
+}
+
+func (r *VocabularyRepository) InsertAll(ctx context.Context, vocabularies []*domain.Vocabulary) (int64, error) {
+	err := r.atomically(ctx, func(conn conn) error {
+		stmt, err := conn.PrepareContext(
+			ctx,
+			"INSERT INTO vocabularies(title, meaning, sentence, created_at, updated_at, created_by, updated_by) "+
+				"VALUES(?, ?, ?, ?, ?, ?, ?)",
+		)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to prepare the insert statement", slog.String("error", err.Error()))
+			return err
+		}
+		defer stmt.Close()
+
+		// Execute an insert process for each vocabulary
+		insertedAt := now()
+		for _, vocabulary := range vocabularies {
+			vocabModel := transformer.ToModel(vocabulary)
+			result, err := stmt.ExecContext(
+				ctx, vocabModel.Title, vocabModel.Meaning, vocabModel.Sentence, insertedAt, insertedAt,
+				vocabModel.CreatedBy, vocabModel.UpdatedBy,
+			)
+
+			// The title is already registered or appears twice; nothing is inserted
+			if isUniqueViolation(err) {
+				slog.WarnContext(ctx, "duplicate vocabulary detected", slog.String("title", vocabModel.Title))
+				return domain.ErrVocabularyDuplicate
+			}
+
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to insert the vocabularies", slog.String("error", err.Error()))
+				return err
+			}
+
+			// vocabulary_no is the rowid of the table
+			if vocabulary.VocabularyNo, err = result.LastInsertId(); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	inserted := int64(len(vocabularies))
+	slog.InfoContext(ctx, "new vocabularies were inserted successfully", slog.Int64("count", inserted))
+	return inserted, nil
+}
+
+func (r *VocabularyRepository) SelectByVocabularyNo(ctx context.Context, vocabularyNo int64) (*domain.Vocabulary, error) {
+	// Execute a select process
+	vocabulary, err := scanVocabulary(r.conn(ctx).QueryRowContext(
+		ctx, "SELECT "+sqlquery.VocabularyColumns+" FROM vocabularies WHERE vocabulary_no = ?", vocabularyNo,
+	))
+
+	// Not found by specified vocabularyNo
+	if errors.Is(err, sql.ErrNoRows) {
+		slog.WarnContext(ctx, "no vocabulary found", slog.Int64("vocabularyNo", vocabularyNo))
+		return nil, domain.ErrVocabularyNotFound
+	}
+
+	if err != nil {
+		slog.ErrorContext(
+			ctx, "failed to query vocabulary", slog.Int64("vocabularyNo", vocabularyNo), slog.String("error", err.Error()),
+		)
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "vocabulary fetched successfully", slog.Int64("vocabularyNo", vocabularyNo))
+	return vocabulary, nil
+}
+
+func (r *VocabularyRepository) SelectAll(ctx context.Context, query usecase.VocabularyQuery) ([]*domain.Vocabulary, error) {
+	// Execute a select process
+	statement, args := sqlquery.SQLite.SelectVocabularies(sqlquery.VocabularyColumns, query)
+	rows, err := r.conn(ctx).QueryContext(ctx, statement, args...)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to query all vocabularies", slog.String("error", err.Error()))
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Copy the selected columns into the domain model
+	var vocabularyList []*domain.Vocabulary
+	for rows.Next() {
+		vocabulary, err := scanVocabulary(rows)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to scan vocabulary row", slog.String("error", err.Error()))
+			return nil, err
+		}
+		vocabularyList = append(vocabularyList, vocabulary)
+	}
+
+	// Check for errors from iterating over rows
+	if err := rows.Err(); err != nil {
+		slog.ErrorContext(ctx, "row iteration error", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "all vocabularies were fetched successfully", slog.Int("count", len(vocabularyList)))
+	return vocabularyList, nil
+}
+
+func (r *VocabularyRepository) Update(ctx context.Context, vocabularyNo int64, vocabulary *domain.Vocabulary) (int64, error) {
+	// Transform the received domain model into DB model
+	vocabModel := transformer.ToModel(vocabulary)
+
+	// Execute the update process
+	var updated int64
+	err := r.conn(ctx).QueryRowContext(
+		ctx,
+		"UPDATE vocabularies SET title = ?, meaning = ?, sentence = ?, version = version + 1, "+
+			"updated_at = ?, updated_by = ? WHERE vocabulary_no = ? RETURNING vocabulary_no",
+		vocabModel.Title, vocabModel.Meaning, vocabModel.Sentence,
+		now(), vocabModel.UpdatedBy, vocabularyNo,
+	).Scan(&updated)
+
+	// Not found by specified vocabularyNo
+	if errors.Is(err, sql.ErrNoRows) {
+		slog.WarnContext(ctx, "no vocabulary found", slog.Int64("vocabularyNo", vocabularyNo))
+		return 0, domain.ErrVocabularyNotFound
+	}
+
+	// The new title is already used by another vocabulary
+	if isUniqueViolation(err) {
+		slog.WarnContext(ctx, "duplicate vocabulary detected", slog.String("title", vocabModel.Title))
+		return 0, domain.ErrVocabularyDuplicate
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to update the vocabulary", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	slog.InfoContext(ctx, "the vocabulary was updated successfully", slog.Int64("vocabularyNo", updated))
+	return updated, nil
+}
+
+func (r *VocabularyRepository) Delete(ctx context.Context, vocabularyNo int64) (int64, error) {
+	// Execute the delete process
+	result, err := r.conn(ctx).ExecContext(ctx, "DELETE FROM vocabularies WHERE vocabulary_no = ?", vocabularyNo)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to delete the vocabulary", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	// Check rows affected number
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get a rows affected", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	if rowsAffected == 0 {
+		slog.WarnContext(ctx, "no vocabulary was deleted", slog.Int64("vocabularyNo", vocabularyNo))
+		return 0, domain.ErrVocabularyNotFound
+	}
+
+	slog.InfoContext(ctx, "the vocabulary was deleted successfully", slog.Int64("rowsAffected", rowsAffected))
+	return rowsAffected, nil
+}
+
+// now returns the current time in the format of the timestamp columns
+func now() string {
+	return time.Now().UTC().Format(db.SQLiteTimeLayout)
+}
+
+// scanVocabulary copies a row of sqlquery.VocabularyColumns into the domain model
+func scanVocabulary(row interface{ Scan(dest ...any) error }) (*domain.Vocabulary, error) {
+	var output model.VocabularyOutput
+	var createdAt, updatedAt string
+	if err := row.Scan(
+		&output.VocabularyNo, &output.Title, &output.Meaning, &output.Sentence, &output.Version,
+		&createdAt, &updatedAt, &output.CreatedBy, &output.UpdatedBy,
+	); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if output.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, err
+	}
+	if output.UpdatedAt, err = parseTime(updatedAt); err != nil {
+		return nil, err
+	}
+
+	return transformer.ToDomain(&output), nil
+}
+
+// parseTime parses a timestamp column. An empty value is the zero time.
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(db.SQLiteTimeLayout, value)
+}
+
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+}
+
+// conn is implemented by both *sql.DB and *sql.Tx
+type conn interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// conn returns the transaction of ctx, or the database.
+// The database has a single connection, so a transaction must not be bypassed.
+func (r *VocabularyRepository) conn(ctx context.Context) conn {
+	if tx, ok := txFrom(ctx); ok {
+		return tx
+	}
+	return r.Db
+}
+
+// atomically runs fn in a new transaction, or in a savepoint of the transaction of ctx
+func (r *VocabularyRepository) atomically(ctx context.Context, fn func(conn conn) error) error {
+	if tx, ok := txFrom(ctx); ok {
+		if _, err := tx.ExecContext(ctx, "SAVEPOINT atomically"); err != nil {
+			slog.ErrorContext(ctx, "failed to create a savepoint", slog.String("error", err.Error()))
+			return err
+		}
+
+		if err := fn(tx); err != nil {
+			// Undo the changes of fn and leave the outer transaction usable
+			if _, rollbackErr := tx.ExecContext(ctx, "ROLLBACK TO atomically; RELEASE atomically"); rollbackErr != nil {
+				slog.ErrorContext(ctx, "failed to roll back to the savepoint", slog.String("error", rollbackErr.Error()))
+			}
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, "RELEASE atomically")
+		return err
+	}
+
+	// Begin a transaction
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to begin a transaction", slog.String("error", err.Error()))
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		slog.ErrorContext(ctx, "failed to commit the transaction", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
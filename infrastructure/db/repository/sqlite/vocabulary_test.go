@@ -0,0 +1,42 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db/repository/repositorytest"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	sqlDB, err := db.OpenSQLite(context.Background(), ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open the database: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	return sqlDB
+}
+
+func TestVocabularyRepository(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) usecase.VocabularyRepository {
+		return NewVocabularyRepository(openTestDB(t))
+	})
+}
+
+func TestTxManager(t *testing.T) {
+	repositorytest.RunTx(t, func(t *testing.T) (usecase.VocabularyRepository, usecase.TxManager) {
+		sqlDB := openTestDB(t)
+		return NewVocabularyRepository(sqlDB), NewTxManager(sqlDB)
+	})
+}
+
+func TestAPIKeyRepository(t *testing.T) {
+	repositorytest.RunAPIKeys(t, func(t *testing.T) usecase.APIKeyRepository {
+		return NewAPIKeyRepository(openTestDB(t))
+	})
+}
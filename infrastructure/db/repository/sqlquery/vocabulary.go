@@ -0,0 +1,143 @@
+// Package sqlquery builds the SQL of the vocabulary queries shared by the database backends
+package sqlquery
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db"
+)
+
+// Dialect holds the differences between the databases.
+// Every backend matches the filters ignoring the case of ASCII letters only, and sorts the text columns
+// in byte order, which is the order of the code points.
+type Dialect struct {
+	// Placeholder of the n-th argument, counted from 1
+	Placeholder func(n int) string
+
+	// Column compared by LIKE with a pattern in lower case. It must lower the ASCII letters only,
+	// unless LIKE already ignores their case.
+	FoldCase func(column string) string
+
+	// Collation of the text columns in ORDER BY
+	Collate string
+
+	// Argument compared with a timestamp column
+	Time func(t time.Time) any
+}
+
+var (
+	PostgreSQL = Dialect{
+		Placeholder: func(n int) string { return "$" + strconv.Itoa(n) },
+		FoldCase: func(column string) string {
+			return "translate(" + column + ", 'ABCDEFGHIJKLMNOPQRSTUVWXYZ', 'abcdefghijklmnopqrstuvwxyz')"
+		},
+		Collate: ` COLLATE "C"`,
+		Time:    func(t time.Time) any { return t },
+	}
+
+	// LIKE of SQLite ignores the case of ASCII letters only, and its default collation is byte order
+	SQLite = Dialect{
+		Placeholder: func(int) string { return "?" },
+		FoldCase:    func(column string) string { return column },
+		Time:        func(t time.Time) any { return t.UTC().Format(db.SQLiteTimeLayout) },
+	}
+)
+
+// VocabularyColumns are selected in the order of the fields of model.VocabularyOutput
+const VocabularyColumns = "vocabulary_no, title, meaning, sentence, version, created_at, updated_at, created_by, updated_by"
+
+// Columns that can be sorted by. Only these names are written into ORDER BY.
+var sortColumns = map[usecase.VocabularySortField]string{
+	usecase.SortByVocabularyNo: "vocabulary_no",
+	usecase.SortByTitle:        "title",
+	usecase.SortByCreatedAt:    "created_at",
+	usecase.SortByUpdatedAt:    "updated_at",
+}
+
+// SelectVocabularies returns the statement and the arguments that select the vocabularies matching the query
+func (d Dialect) SelectVocabularies(columns string, query usecase.VocabularyQuery) (string, []any) {
+	var conditions []string
+	var args []any
+
+	like := func(column, pattern string) {
+		args = append(args, pattern)
+		conditions = append(conditions, d.FoldCase(column)+" LIKE "+d.Placeholder(len(args))+` ESCAPE '\'`)
+	}
+
+	compare := func(column, operator string, t time.Time) {
+		if t.IsZero() {
+			return
+		}
+		args = append(args, d.Time(t))
+		conditions = append(conditions, column+" "+operator+" "+d.Placeholder(len(args)))
+	}
+
+	if query.TitlePrefix != "" {
+		like("title", foldASCII(escapeLike(query.TitlePrefix))+"%")
+	}
+	if query.SentenceContains != "" {
+		like("sentence", "%"+foldASCII(escapeLike(query.SentenceContains))+"%")
+	}
+	compare("created_at", ">=", query.CreatedFrom)
+	compare("created_at", "<", query.CreatedUntil)
+	compare("updated_at", ">=", query.UpdatedFrom)
+	compare("updated_at", "<", query.UpdatedUntil)
+
+	var sb strings.Builder
+	sb.WriteString("SELECT " + columns + " FROM vocabularies")
+	if len(conditions) > 0 {
+		sb.WriteString(" WHERE " + strings.Join(conditions, " AND "))
+	}
+	sb.WriteString(" ORDER BY " + d.orderBy(query.Sort))
+
+	return sb.String(), args
+}
+
+func (d Dialect) orderBy(sorts []usecase.VocabularySort) string {
+	var keys []string
+	tieBroken := false
+
+	for _, sort := range sorts {
+		column, ok := sortColumns[sort.Field]
+		if !ok {
+			continue
+		}
+
+		if sort.Field == usecase.SortByTitle {
+			column += d.Collate
+		}
+
+		key := column + " ASC"
+		if sort.Descending {
+			key = column + " DESC"
+		}
+		keys = append(keys, key)
+
+		if sort.Field == usecase.SortByVocabularyNo {
+			tieBroken = true
+		}
+	}
+
+	if !tieBroken {
+		keys = append(keys, "vocabulary_no ASC")
+	}
+	return strings.Join(keys, ", ")
+}
+
+// foldASCII lowers the ASCII letters of s and keeps the other characters
+func foldASCII(s string) string {
+	return strings.Map(func(r rune) rune {
+		if 'A' <= r && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return r
+	}, s)
+}
+
+// escapeLike makes the wildcards of LIKE match literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
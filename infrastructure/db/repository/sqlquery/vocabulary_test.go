@@ -0,0 +1,93 @@
+package sqlquery
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+)
+
+func TestSelectVocabularies(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  Dialect
+		query    usecase.VocabularyQuery
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:    "default",
+			dialect: PostgreSQL,
+			wantSQL: "SELECT title FROM vocabularies ORDER BY vocabulary_no ASC",
+		},
+		{
+			name:    "filters",
+			dialect: PostgreSQL,
+			query:   usecase.VocabularyQuery{TitlePrefix: "Ap", SentenceContains: "100%_Ä"},
+			wantSQL: "SELECT title FROM vocabularies WHERE translate(title, 'ABCDEFGHIJKLMNOPQRSTUVWXYZ', 'abcdefghijklmnopqrstuvwxyz')" +
+				` LIKE $1 ESCAPE '\' AND translate(sentence, 'ABCDEFGHIJKLMNOPQRSTUVWXYZ', 'abcdefghijklmnopqrstuvwxyz')` +
+				` LIKE $2 ESCAPE '\' ORDER BY vocabulary_no ASC`,
+			wantArgs: []any{"ap%", `%100\%\_Ä%`},
+		},
+		{
+			name:     "sqlite",
+			dialect:  SQLite,
+			query:    usecase.VocabularyQuery{TitlePrefix: `A\b`},
+			wantSQL:  `SELECT title FROM vocabularies WHERE title LIKE ? ESCAPE '\' ORDER BY vocabulary_no ASC`,
+			wantArgs: []any{`a\\b%`},
+		},
+		{
+			name:    "time ranges",
+			dialect: PostgreSQL,
+			query: usecase.VocabularyQuery{
+				CreatedFrom: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), UpdatedUntil: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			},
+			wantSQL:  "SELECT title FROM vocabularies WHERE created_at >= $1 AND updated_at < $2 ORDER BY vocabulary_no ASC",
+			wantArgs: []any{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:     "sqlite time range",
+			dialect:  SQLite,
+			query:    usecase.VocabularyQuery{CreatedUntil: time.Date(2024, 1, 1, 9, 0, 0, 0, time.FixedZone("JST", 9*60*60))},
+			wantSQL:  "SELECT title FROM vocabularies WHERE created_at < ? ORDER BY vocabulary_no ASC",
+			wantArgs: []any{"2024-01-01T00:00:00.000000Z"},
+		},
+		{
+			name:    "sort",
+			dialect: PostgreSQL,
+			query:   usecase.VocabularyQuery{Sort: []usecase.VocabularySort{{Field: usecase.SortByTitle, Descending: true}}},
+			wantSQL: `SELECT title FROM vocabularies ORDER BY title COLLATE "C" DESC, vocabulary_no ASC`,
+		},
+		{
+			name:    "sqlite sort",
+			dialect: SQLite,
+			query:   usecase.VocabularyQuery{Sort: []usecase.VocabularySort{{Field: usecase.SortByTitle}}},
+			wantSQL: "SELECT title FROM vocabularies ORDER BY title ASC, vocabulary_no ASC",
+		},
+		{
+			name:    "sort by vocabulary_no",
+			dialect: PostgreSQL,
+			query:   usecase.VocabularyQuery{Sort: []usecase.VocabularySort{{Field: usecase.SortByVocabularyNo, Descending: true}}},
+			wantSQL: "SELECT title FROM vocabularies ORDER BY vocabulary_no DESC",
+		},
+		{
+			name:    "unknown sort field",
+			dialect: PostgreSQL,
+			query:   usecase.VocabularyQuery{Sort: []usecase.VocabularySort{{Field: "title; DROP TABLE vocabularies"}}},
+			wantSQL: "SELECT title FROM vocabularies ORDER BY vocabulary_no ASC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSQL, gotArgs := tt.dialect.SelectVocabularies("title", tt.query)
+			if gotSQL != tt.wantSQL {
+				t.Errorf("SQL = %s, want %s", gotSQL, tt.wantSQL)
+			}
+			if !slices.Equal(gotArgs, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -8,9 +8,11 @@ import (
 // Domain model -> DB model
 func ToModel(vocabulary *domain.Vocabulary) *model.VocabularyInput {
 	return &model.VocabularyInput{
-		Title:    vocabulary.Title,
-		Meaning:  vocabulary.Meaning,
-		Sentence: vocabulary.Sentence,
+		Title:     vocabulary.Title,
+		Meaning:   vocabulary.Meaning,
+		Sentence:  vocabulary.Sentence,
+		CreatedBy: vocabulary.CreatedBy,
+		UpdatedBy: vocabulary.UpdatedBy,
 	}
 }
 
@@ -21,5 +23,10 @@ func ToDomain(output *model.VocabularyOutput) *domain.Vocabulary {
 		Title:        output.Title,
 		Meaning:      output.Meaning,
 		Sentence:     output.Sentence,
+		Version:      output.Version,
+		CreatedAt:    output.CreatedAt,
+		UpdatedAt:    output.UpdatedAt,
+		CreatedBy:    output.CreatedBy,
+		UpdatedBy:    output.UpdatedBy,
 	}
 }
@@ -0,0 +1,41 @@
+package transformer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/domain"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db/repository/model"
+)
+
+func TestToModel(t *testing.T) {
+	vocabulary := &domain.Vocabulary{
+		VocabularyNo: 7, Title: "apple", Meaning: "fruit", Sentence: "I ate an apple.", CreatedBy: "alice", UpdatedBy: "bob",
+	}
+
+	got := ToModel(vocabulary)
+
+	want := model.VocabularyInput{Title: "apple", Meaning: "fruit", Sentence: "I ate an apple.", CreatedBy: "alice", UpdatedBy: "bob"}
+	if *got != want {
+		t.Errorf("ToModel() = %+v, want %+v", got, want)
+	}
+}
+
+func TestToDomain(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	updatedAt := createdAt.Add(time.Hour)
+	output := &model.VocabularyOutput{
+		VocabularyNo: 7, Title: "apple", Meaning: "fruit", Sentence: "I ate an apple.", Version: 3,
+		CreatedAt: createdAt, UpdatedAt: updatedAt, CreatedBy: "alice", UpdatedBy: "bob",
+	}
+
+	got := ToDomain(output)
+
+	want := domain.Vocabulary{
+		VocabularyNo: 7, Title: "apple", Meaning: "fruit", Sentence: "I ate an apple.", Version: 3,
+		CreatedAt: createdAt, UpdatedAt: updatedAt, CreatedBy: "alice", UpdatedBy: "bob",
+	}
+	if *got != want {
+		t.Errorf("ToDomain() = %+v, want %+v", got, want)
+	}
+}
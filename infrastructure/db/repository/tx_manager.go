@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db"
+)
+
+// PostgreSQL error codes of transactions that may succeed when retried
+const (
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+)
+
+// Backoff between attempts of a transaction
+const (
+	initialTxRetryInterval = 10 * time.Millisecond
+	maxTxRetryInterval     = 500 * time.Millisecond
+)
+
+var isoLevels = map[usecase.IsolationLevel]pgx.TxIsoLevel{
+	usecase.IsolationReadCommitted:  pgx.ReadCommitted,
+	usecase.IsolationRepeatableRead: pgx.RepeatableRead,
+	usecase.IsolationSerializable:   pgx.Serializable,
+}
+
+type txKey struct{}
+
+// TxManager runs units of work in transactions on the primary
+type TxManager struct {
+	DB               *db.Router
+	DefaultIsolation usecase.IsolationLevel
+	MaxRetries       int
+}
+
+func NewTxManager(router *db.Router, defaultIsolation usecase.IsolationLevel, maxRetries int) *TxManager {
+	return &TxManager{
+		DB:               router,
+		DefaultIsolation: defaultIsolation,
+		MaxRetries:       maxRetries,
+	}
+}
+
+func (m *TxManager) WithinTx(ctx context.Context, opts usecase.TxOptions, fn func(ctx context.Context) error) error {
+	// Join the transaction of an outer WithinTx
+	if _, ok := txFrom(ctx); ok {
+		return fn(ctx)
+	}
+
+	isolation := opts.Isolation
+	if isolation == usecase.IsolationDefault {
+		isolation = m.DefaultIsolation
+	}
+	txOptions := pgx.TxOptions{IsoLevel: isoLevels[isolation]}
+
+	interval := initialTxRetryInterval
+	for attempt := 1; ; attempt++ {
+		err := m.run(ctx, txOptions, fn)
+		if err == nil || !isRetryable(err) || attempt > m.MaxRetries {
+			return err
+		}
+
+		slog.WarnContext(
+			ctx, "retrying the transaction", slog.Int("attempt", attempt),
+			slog.Duration("retryIn", interval), slog.String("error", err.Error()),
+		)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(interval):
+		}
+
+		interval = min(interval*2, maxTxRetryInterval)
+	}
+}
+
+func (m *TxManager) run(ctx context.Context, txOptions pgx.TxOptions, fn func(ctx context.Context) error) error {
+	// Begin a transaction
+	tx, err := m.DB.Writer(ctx).BeginTx(ctx, txOptions)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to begin a transaction", slog.String("error", err.Error()))
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		slog.ErrorContext(ctx, "failed to commit the transaction", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// isRetryable reports whether the transaction failed only because of concurrent transactions
+func isRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && (pgErr.Code == serializationFailure || pgErr.Code == deadlockDetected)
+}
+
+func txFrom(ctx context.Context) (pgx.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(pgx.Tx)
+	return tx, ok
+}
@@ -2,22 +2,31 @@ package repository
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"log/slog"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/takumi616/golang-backend-sample/application/usecase"
 	"github.com/takumi616/golang-backend-sample/domain"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db"
 	"github.com/takumi616/golang-backend-sample/infrastructure/db/repository/model"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db/repository/sqlquery"
 	"github.com/takumi616/golang-backend-sample/infrastructure/db/repository/transformer"
 )
 
+// PostgreSQL error code raised when a UNIQUE constraint is violated
+const uniqueViolation = "23505"
+
+// VocabularyRepository runs the queries on the replica and the writes on the primary
 type VocabularyRepository struct {
-	Db *sql.DB
+	DB *db.Router
 }
 
-func NewVocabularyRepository(db *sql.DB) *VocabularyRepository {
+func NewVocabularyRepository(router *db.Router) *VocabularyRepository {
 	return &VocabularyRepository{
-		Db: db,
+		DB: router,
 	}
 }
 
@@ -26,27 +35,29 @@ func (r *VocabularyRepository) Insert(ctx context.Context, vocabulary *domain.Vo
 	vocabModel := transformer.ToModel(vocabulary)
 
 	// Begin a transaction
-	tx, err := r.Db.BeginTx(ctx, nil)
+	tx, err := r.begin(ctx)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to begin a transaction", slog.String("error", err.Error()))
 		return 0, err
 	}
-	defer tx.Rollback()
+	defer tx.Rollback(ctx)
 
 	// Execute an insert process
+	now := time.Now().UTC().Truncate(time.Microsecond)
 	var vocabularyNo int64
-	err = tx.QueryRowContext(
+	err = tx.QueryRow(
 		ctx,
-		"INSERT INTO vocabularies(title, meaning, sentence) VALUES($1, $2, $3) ON CONFLICT (title) DO NOTHING RETURNING vocabulary_no",
-		vocabModel.Title, vocabModel.Meaning, vocabModel.Sentence,
+		"INSERT INTO vocabularies(title, meaning, sentence, created_at, updated_at, created_by, updated_by) "+
+			"VALUES($1, $2, $3, $4, $4, $5, $6) RETURNING vocabulary_no",
+		vocabModel.Title, vocabModel.Meaning, vocabModel.Sentence, now, vocabModel.CreatedBy, vocabModel.UpdatedBy,
 	).Scan(&vocabularyNo)
 
-	// sql.ErrNoRows is returned when an insert proccess is skipped with ON CONFLICT DO NOTHING
-	if errors.Is(err, sql.ErrNoRows) {
+	// The title is already registered
+	if errors.Is(toDomainError(err), domain.ErrVocabularyDuplicate) {
 		slog.WarnContext(
-			ctx, "duplicate vocabulary detected", slog.Int64("vocabularyNo", vocabularyNo), slog.String("error", err.Error()),
+			ctx, "duplicate vocabulary detected", slog.String("title", vocabModel.Title), slog.String("error", err.Error()),
 		)
-		return 0, err
+		return 0, domain.ErrVocabularyDuplicate
 	}
 
 	if err != nil {
@@ -55,7 +66,7 @@ func (r *VocabularyRepository) Insert(ctx context.Context, vocabulary *domain.Vo
 	}
 
 	// Commit the transaction
-	if err := tx.Commit(); err != nil {
+	if err := tx.Commit(ctx); err != nil {
 		slog.ErrorContext(ctx, "failed to commit the transaction", slog.String("error", err.Error()))
 		return 0, err
 	}
@@ -64,21 +75,97 @@ func (r *VocabularyRepository) Insert(ctx context.Context, vocabulary *domain.Vo
 	return vocabularyNo, nil
 }
 
+func (r *VocabularyRepository) InsertAll(ctx context.Context, vocabularies []*domain.Vocabulary) (int64, error) {
+	// Transform the received domain models into COPY rows
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	rows := make([][]any, 0, len(vocabularies))
+	for _, vocabulary := range vocabularies {
+		vocabModel := transformer.ToModel(vocabulary)
+		rows = append(rows, []any{
+			vocabModel.Title, vocabModel.Meaning, vocabModel.Sentence, now, now, vocabModel.CreatedBy, vocabModel.UpdatedBy,
+		})
+	}
+
+	// Begin a transaction
+	tx, err := r.begin(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to begin a transaction", slog.String("error", err.Error()))
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Execute a bulk insert process with COPY
+	inserted, err := tx.CopyFrom(
+		ctx, pgx.Identifier{"vocabularies"},
+		[]string{"title", "meaning", "sentence", "created_at", "updated_at", "created_by", "updated_by"},
+		pgx.CopyFromRows(rows),
+	)
+
+	// One of the titles is already registered or appears twice; nothing is inserted
+	if errors.Is(toDomainError(err), domain.ErrVocabularyDuplicate) {
+		slog.WarnContext(ctx, "duplicate vocabulary detected", slog.String("error", err.Error()))
+		return 0, domain.ErrVocabularyDuplicate
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to insert the vocabularies", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	// COPY returns no rows, so look the numbers up by the unique titles
+	if err := selectVocabularyNos(ctx, tx, vocabularies); err != nil {
+		slog.ErrorContext(ctx, "failed to select the inserted vocabularies", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		slog.ErrorContext(ctx, "failed to commit the transaction", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	slog.InfoContext(ctx, "new vocabularies were inserted successfully", slog.Int64("count", inserted))
+	return inserted, nil
+}
+
+// selectVocabularyNos sets the VocabularyNo of the vocabularies from their titles
+func selectVocabularyNos(ctx context.Context, tx pgx.Tx, vocabularies []*domain.Vocabulary) error {
+	byTitle := make(map[string]*domain.Vocabulary, len(vocabularies))
+	titles := make([]string, 0, len(vocabularies))
+	for _, vocabulary := range vocabularies {
+		byTitle[vocabulary.Title] = vocabulary
+		titles = append(titles, vocabulary.Title)
+	}
+
+	rows, err := tx.Query(ctx, "SELECT title, vocabulary_no FROM vocabularies WHERE title = ANY($1)", titles)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var title string
+		var vocabularyNo int64
+		if err := rows.Scan(&title, &vocabularyNo); err != nil {
+			return err
+		}
+		byTitle[title].VocabularyNo = vocabularyNo
+	}
+	return rows.Err()
+}
+
 func (r *VocabularyRepository) SelectByVocabularyNo(ctx context.Context, vocabularyNo int64) (*domain.Vocabulary, error) {
 	// Execute a select process
-	var row model.VocabularyOutput
-	err := r.Db.QueryRowContext(
-		ctx,
-		"SELECT vocabulary_no, title, meaning, sentence FROM vocabularies WHERE vocabulary_no = $1",
-		vocabularyNo,
-	).Scan(&row.VocabularyNo, &row.Title, &row.Meaning, &row.Sentence)
+	vocabulary, err := scanVocabulary(r.reader(ctx).QueryRow(
+		ctx, "SELECT "+sqlquery.VocabularyColumns+" FROM vocabularies WHERE vocabulary_no = $1", vocabularyNo,
+	))
 
 	// Not found by specified vocabularyNo
-	if errors.Is(err, sql.ErrNoRows) {
+	if errors.Is(err, pgx.ErrNoRows) {
 		slog.WarnContext(
 			ctx, "no vocabulary found", slog.Int64("vocabularyNo", vocabularyNo), slog.String("error", err.Error()),
 		)
-		return nil, err
+		return nil, domain.ErrVocabularyNotFound
 	}
 
 	if err != nil {
@@ -88,18 +175,14 @@ func (r *VocabularyRepository) SelectByVocabularyNo(ctx context.Context, vocabul
 		return nil, err
 	}
 
-	// Transform the selected row into domain model
-	vocabulary := transformer.ToDomain(&row)
-
 	slog.InfoContext(ctx, "vocabulary fetched successfully", slog.Int64("vocabularyNo", vocabularyNo))
 	return vocabulary, nil
 }
 
-func (r *VocabularyRepository) SelectAll(ctx context.Context) ([]*domain.Vocabulary, error) {
+func (r *VocabularyRepository) SelectAll(ctx context.Context, query usecase.VocabularyQuery) ([]*domain.Vocabulary, error) {
 	// Execute a select process
-	rows, err := r.Db.QueryContext(
-		ctx, "SELECT vocabulary_no, title, meaning, sentence FROM vocabularies ORDER BY vocabulary_no ASC",
-	)
+	statement, args := sqlquery.PostgreSQL.SelectVocabularies(sqlquery.VocabularyColumns, query)
+	rows, err := r.reader(ctx).Query(ctx, statement, args...)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to query all vocabularies", slog.String("error", err.Error()))
 		return nil, err
@@ -109,12 +192,12 @@ func (r *VocabularyRepository) SelectAll(ctx context.Context) ([]*domain.Vocabul
 	// Copy the selected columns into the domain model
 	var vocabularyList []*domain.Vocabulary
 	for rows.Next() {
-		var vocabulary model.VocabularyOutput
-		if err := rows.Scan(&vocabulary.VocabularyNo, &vocabulary.Title, &vocabulary.Meaning, &vocabulary.Sentence); err != nil {
+		vocabulary, err := scanVocabulary(rows)
+		if err != nil {
 			slog.ErrorContext(ctx, "failed to scan vocabulary row", slog.String("error", err.Error()))
 			return nil, err
 		}
-		vocabularyList = append(vocabularyList, transformer.ToDomain(&vocabulary))
+		vocabularyList = append(vocabularyList, vocabulary)
 	}
 
 	// Check for errors from iterating over rows
@@ -132,27 +215,37 @@ func (r *VocabularyRepository) Update(ctx context.Context, vocabularyNo int64, v
 	vocabModel := transformer.ToModel(vocabulary)
 
 	// Begin a transaction
-	tx, err := r.Db.BeginTx(ctx, nil)
+	tx, err := r.begin(ctx)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to begin a transaction", slog.String("error", err.Error()))
 		return 0, err
 	}
-	defer tx.Rollback()
+	defer tx.Rollback(ctx)
 
 	// Execute the update process
 	var updated int64
-	err = tx.QueryRowContext(
-		ctx, "UPDATE vocabularies SET title = $1, meaning = $2, sentence = $3 WHERE vocabulary_no = $4 RETURNING vocabulary_no",
-		vocabModel.Title, vocabModel.Meaning,
-		vocabModel.Sentence, vocabularyNo,
+	err = tx.QueryRow(
+		ctx,
+		"UPDATE vocabularies SET title = $1, meaning = $2, sentence = $3, version = version + 1, "+
+			"updated_at = $4, updated_by = $5 WHERE vocabulary_no = $6 RETURNING vocabulary_no",
+		vocabModel.Title, vocabModel.Meaning, vocabModel.Sentence,
+		time.Now().UTC().Truncate(time.Microsecond), vocabModel.UpdatedBy, vocabularyNo,
 	).Scan(&updated)
 
 	// Not found by specified vocabularyNo
-	if errors.Is(err, sql.ErrNoRows) {
+	if errors.Is(err, pgx.ErrNoRows) {
 		slog.WarnContext(
 			ctx, "no vocabulary found", slog.Int64("vocabularyNo", vocabularyNo), slog.String("error", err.Error()),
 		)
-		return 0, err
+		return 0, domain.ErrVocabularyNotFound
+	}
+
+	// The new title is already used by another vocabulary
+	if errors.Is(toDomainError(err), domain.ErrVocabularyDuplicate) {
+		slog.WarnContext(
+			ctx, "duplicate vocabulary detected", slog.String("title", vocabModel.Title), slog.String("error", err.Error()),
+		)
+		return 0, domain.ErrVocabularyDuplicate
 	}
 
 	if err != nil {
@@ -161,7 +254,7 @@ func (r *VocabularyRepository) Update(ctx context.Context, vocabularyNo int64, v
 	}
 
 	// Commit the transaction
-	if err := tx.Commit(); err != nil {
+	if err := tx.Commit(ctx); err != nil {
 		slog.ErrorContext(ctx, "failed to commit the transaction", slog.String("error", err.Error()))
 		return 0, err
 	}
@@ -172,17 +265,15 @@ func (r *VocabularyRepository) Update(ctx context.Context, vocabularyNo int64, v
 
 func (r *VocabularyRepository) Delete(ctx context.Context, vocabularyNo int64) (int64, error) {
 	// Begin a transaction
-	tx, err := r.Db.BeginTx(ctx, nil)
+	tx, err := r.begin(ctx)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to begin a transaction", slog.String("error", err.Error()))
 		return 0, err
 	}
-	defer tx.Rollback()
+	defer tx.Rollback(ctx)
 
-	// Execute the update process
-	result, err := tx.ExecContext(
-		ctx, "DELETE FROM vocabularies WHERE vocabulary_no = $1", vocabularyNo,
-	)
+	// Execute the delete process
+	result, err := tx.Exec(ctx, "DELETE FROM vocabularies WHERE vocabulary_no = $1", vocabularyNo)
 
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to delete the vocabulary", slog.String("error", err.Error()))
@@ -190,19 +281,14 @@ func (r *VocabularyRepository) Delete(ctx context.Context, vocabularyNo int64) (
 	}
 
 	// Check rows affected number
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		slog.ErrorContext(ctx, "failed to get a rows affected", slog.String("error", err.Error()))
-		return 0, err
-	}
-
+	rowsAffected := result.RowsAffected()
 	if rowsAffected == 0 {
 		slog.WarnContext(ctx, "no vocabulary was deleted", slog.Int64("vocabularyNo", vocabularyNo))
-		return 0, sql.ErrNoRows
+		return 0, domain.ErrVocabularyNotFound
 	}
 
 	// Commit the transaction
-	if err := tx.Commit(); err != nil {
+	if err := tx.Commit(ctx); err != nil {
 		slog.ErrorContext(ctx, "failed to commit the transaction", slog.String("error", err.Error()))
 		return 0, err
 	}
@@ -210,3 +296,53 @@ func (r *VocabularyRepository) Delete(ctx context.Context, vocabularyNo int64) (
 	slog.InfoContext(ctx, "the vocabulary was deleted successfully", slog.Int64("rowsAffected", rowsAffected))
 	return rowsAffected, nil
 }
+
+// querier is implemented by both *pgxpool.Pool and pgx.Tx
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// reader returns the transaction of ctx, or the pool for queries
+func (r *VocabularyRepository) reader(ctx context.Context) querier {
+	if tx, ok := txFrom(ctx); ok {
+		return tx
+	}
+	return r.DB.Reader(ctx)
+}
+
+// scanVocabulary copies a row of sqlquery.VocabularyColumns into the domain model
+func scanVocabulary(row pgx.Row) (*domain.Vocabulary, error) {
+	var output model.VocabularyOutput
+	if err := row.Scan(
+		&output.VocabularyNo, &output.Title, &output.Meaning, &output.Sentence, &output.Version,
+		&output.CreatedAt, &output.UpdatedAt, &output.CreatedBy, &output.UpdatedBy,
+	); err != nil {
+		return nil, err
+	}
+	return transformer.ToDomain(&output), nil
+}
+
+// begin starts a transaction, or a savepoint in the transaction of ctx
+func (r *VocabularyRepository) begin(ctx context.Context) (pgx.Tx, error) {
+	if tx, ok := txFrom(ctx); ok {
+		return tx.Begin(ctx)
+	}
+	return r.DB.Writer(ctx).Begin(ctx)
+}
+
+// toDomainError maps the PostgreSQL error codes to domain errors.
+// Other errors are returned unchanged.
+func toDomainError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch pgErr.Code {
+	case uniqueViolation:
+		return domain.ErrVocabularyDuplicate
+	default:
+		return err
+	}
+}
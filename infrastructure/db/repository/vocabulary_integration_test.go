@@ -0,0 +1,152 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db/repository/repositorytest"
+	"github.com/takumi616/golang-backend-sample/migrations"
+)
+
+// Run with: TEST_DATABASE_DSN=postgres://... go test -tags integration ./infrastructure/db/repository/
+// Every test gets its own schema, which is dropped afterwards.
+
+var schemaSeq atomic.Int64
+
+func testConfig(t *testing.T) *pgxpool.Config {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	poolConfig, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		t.Fatalf("invalid TEST_DATABASE_DSN: %v", err)
+	}
+
+	return poolConfig
+}
+
+// openThrowawayPool opens a connection pool bound to a new, migrated schema
+func openThrowawayPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	ctx := context.Background()
+	poolConfig := testConfig(t)
+
+	admin, err := pgxpool.NewWithConfig(ctx, poolConfig.Copy())
+	if err != nil {
+		t.Fatalf("failed to open the database: %v", err)
+	}
+	t.Cleanup(admin.Close)
+
+	pingCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	if err := admin.Ping(pingCtx); err != nil {
+		t.Skipf("PostgreSQL is not available: %v", err)
+	}
+
+	schema := fmt.Sprintf("test_%d_%d", time.Now().UnixNano(), schemaSeq.Add(1))
+	if _, err := admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatalf("failed to create the schema: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec(ctx, "DROP SCHEMA "+schema+" CASCADE"); err != nil {
+			t.Errorf("failed to drop the schema: %v", err)
+		}
+	})
+
+	poolConfig.ConnConfig.RuntimeParams["search_path"] = schema
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		t.Fatalf("failed to open the database: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	sqlDB := stdlib.OpenDBFromPool(pool)
+	t.Cleanup(func() { sqlDB.Close() })
+	if _, err := db.Migrate(ctx, sqlDB, migrations.FS); err != nil {
+		t.Fatalf("failed to migrate the schema: %v", err)
+	}
+
+	return pool
+}
+
+func TestVocabularyRepository(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) usecase.VocabularyRepository {
+		return NewVocabularyRepository(db.NewRouter(context.Background(), openThrowawayPool(t), nil, 0))
+	})
+}
+
+func TestTxManager(t *testing.T) {
+	repositorytest.RunTx(t, func(t *testing.T) (usecase.VocabularyRepository, usecase.TxManager) {
+		router := db.NewRouter(context.Background(), openThrowawayPool(t), nil, 0)
+		return NewVocabularyRepository(router), NewTxManager(router, usecase.IsolationReadCommitted, 3)
+	})
+}
+
+func TestMigrateIsIdempotent(t *testing.T) {
+	sqlDB := stdlib.OpenDBFromPool(openThrowawayPool(t))
+	defer sqlDB.Close()
+
+	applied, err := db.Migrate(context.Background(), sqlDB, migrations.FS)
+	if err != nil {
+		t.Fatalf("Migrate returned an error: %v", err)
+	}
+	if applied != 0 {
+		t.Errorf("Migrate applied %d migrations on a migrated schema, want 0", applied)
+	}
+}
+
+func TestIdempotencyStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewIdempotencyStore(db.NewRouter(ctx, openThrowawayPool(t), nil, 0))
+
+	_, reserved, err := store.Reserve(ctx, "key", "hash", time.Now().Add(time.Hour))
+	if err != nil || !reserved {
+		t.Fatalf("Reserve = %v, %v, want true, nil", reserved, err)
+	}
+
+	record, reserved, err := store.Reserve(ctx, "key", "hash", time.Now().Add(time.Hour))
+	if err != nil || reserved || record.StatusCode != 0 {
+		t.Fatalf("Reserve of an in-progress key = %+v, %v, %v", record, reserved, err)
+	}
+
+	if err := store.Complete(ctx, "key", usecase.IdempotencyRecord{
+		RequestHash: "hash", StatusCode: 201, ContentType: "application/json", Body: []byte(`{}`),
+	}); err != nil {
+		t.Fatalf("Complete returned an error: %v", err)
+	}
+
+	record, reserved, err = store.Reserve(ctx, "key", "hash", time.Now().Add(time.Hour))
+	if err != nil || reserved || record.StatusCode != 201 || string(record.Body) != `{}` {
+		t.Fatalf("Reserve of a completed key = %+v, %v, %v", record, reserved, err)
+	}
+
+	// Release keeps completed keys
+	if err := store.Release(ctx, "key"); err != nil {
+		t.Fatalf("Release returned an error: %v", err)
+	}
+	if _, reserved, _ := store.Reserve(ctx, "key", "hash", time.Now().Add(time.Hour)); reserved {
+		t.Error("Release removed a completed key")
+	}
+
+	// An expired key can be reserved again
+	if _, reserved, err := store.Reserve(ctx, "expired", "hash", time.Now().Add(-time.Second)); err != nil || !reserved {
+		t.Fatalf("Reserve = %v, %v, want true, nil", reserved, err)
+	}
+	if _, reserved, err := store.Reserve(ctx, "expired", "other", time.Now().Add(time.Hour)); err != nil || !reserved {
+		t.Errorf("Reserve of an expired key = %v, %v, want true, nil", reserved, err)
+	}
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db"
+)
+
+// WebhookRepository keeps the webhooks. Their deliveries are jobs of usecase.DeliverWebhookKind.
+type WebhookRepository struct {
+	DB *db.Router
+}
+
+func NewWebhookRepository(router *db.Router) *WebhookRepository {
+	return &WebhookRepository{
+		DB: router,
+	}
+}
+
+func (r *WebhookRepository) InsertWebhook(ctx context.Context, webhook *domain.Webhook) (int64, error) {
+	var webhookID int64
+	err := r.writer(ctx).QueryRow(
+		ctx, "INSERT INTO webhooks (url, secret, event_types, created_at) VALUES ($1, $2, $3, $4) RETURNING webhook_id",
+		webhook.URL, webhook.Secret, webhook.EventTypes, time.Now().UTC().Truncate(time.Microsecond),
+	).Scan(&webhookID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to insert a webhook", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	slog.InfoContext(ctx, "new webhook was inserted successfully", slog.Int64("webhookID", webhookID))
+	return webhookID, nil
+}
+
+func (r *WebhookRepository) SelectWebhookByID(ctx context.Context, webhookID int64) (*domain.Webhook, error) {
+	var webhook domain.Webhook
+	err := r.reader(ctx).QueryRow(
+		ctx, "SELECT webhook_id, url, secret, event_types, created_at FROM webhooks WHERE webhook_id = $1", webhookID,
+	).Scan(&webhook.WebhookID, &webhook.URL, &webhook.Secret, &webhook.EventTypes, &webhook.CreatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		slog.WarnContext(ctx, "no webhook found", slog.Int64("webhookID", webhookID))
+		return nil, domain.ErrWebhookNotFound
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get the webhook", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+func (r *WebhookRepository) SelectAllWebhooks(ctx context.Context) ([]*domain.Webhook, error) {
+	rows, err := r.reader(ctx).Query(
+		ctx, "SELECT webhook_id, url, secret, event_types, created_at FROM webhooks ORDER BY webhook_id",
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to query all webhooks", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	webhooks, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*domain.Webhook, error) {
+		var webhook domain.Webhook
+		err := row.Scan(&webhook.WebhookID, &webhook.URL, &webhook.Secret, &webhook.EventTypes, &webhook.CreatedAt)
+		return &webhook, err
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to scan webhook rows", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func (r *WebhookRepository) DeleteWebhook(ctx context.Context, webhookID int64) (int64, error) {
+	// The deliveries go with the webhook
+	var rowsAffected int64
+	err := r.writer(ctx).QueryRow(
+		ctx,
+		`WITH deleted AS (
+			DELETE FROM webhooks WHERE webhook_id = $1 RETURNING webhook_id
+		), deliveries AS (
+			DELETE FROM jobs WHERE kind = $2 AND (payload->>'webhook_id')::bigint IN (SELECT webhook_id FROM deleted)
+		)
+		SELECT count(*) FROM deleted`,
+		webhookID, usecase.DeliverWebhookKind,
+	).Scan(&rowsAffected)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to delete the webhook", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	if rowsAffected == 0 {
+		slog.WarnContext(ctx, "no webhook was deleted", slog.Int64("webhookID", webhookID))
+		return 0, domain.ErrWebhookNotFound
+	}
+
+	slog.InfoContext(ctx, "the webhook was deleted successfully", slog.Int64("webhookID", webhookID))
+	return rowsAffected, nil
+}
+
+// SelectDeliveries reads the delivery jobs of the webhook
+func (r *WebhookRepository) SelectDeliveries(
+	ctx context.Context, webhookID int64, status usecase.WebhookDeliveryStatus, limit int,
+) ([]*usecase.WebhookDelivery, error) {
+	rows, err := r.reader(ctx).Query(
+		ctx,
+		"SELECT "+jobColumns+" FROM jobs "+
+			"WHERE kind = $1 AND (payload->>'webhook_id')::bigint = $2 AND ($3 = '' OR status = $3) "+
+			"ORDER BY job_id DESC LIMIT $4",
+		usecase.DeliverWebhookKind, webhookID, string(toJobStatus(status)), limit,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to query the webhook deliveries", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	deliveries, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*usecase.WebhookDelivery, error) {
+		job, err := scanJob(row)
+		if err != nil {
+			return nil, err
+		}
+		return toDelivery(job)
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to scan webhook delivery rows", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// execQuerier is implemented by both *pgxpool.Pool and pgx.Tx
+type execQuerier interface {
+	querier
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// writer returns the transaction of ctx, or the primary
+func (r *WebhookRepository) writer(ctx context.Context) execQuerier {
+	if tx, ok := txFrom(ctx); ok {
+		return tx
+	}
+	return r.DB.Writer(ctx)
+}
+
+// reader returns the transaction of ctx, or the pool for queries
+func (r *WebhookRepository) reader(ctx context.Context) querier {
+	if tx, ok := txFrom(ctx); ok {
+		return tx
+	}
+	return r.DB.Reader(ctx)
+}
+
+// toJobStatus returns the status of the jobs of the deliveries in the status
+func toJobStatus(status usecase.WebhookDeliveryStatus) usecase.JobStatus {
+	if status == usecase.DeliveryDelivered {
+		return usecase.JobSucceeded
+	}
+	return usecase.JobStatus(status)
+}
+
+// toDelivery reads a delivery job
+func toDelivery(job *usecase.Job) (*usecase.WebhookDelivery, error) {
+	var args usecase.DeliverWebhookArgs
+	if err := json.Unmarshal(job.Payload, &args); err != nil {
+		return nil, err
+	}
+
+	delivery := &usecase.WebhookDelivery{
+		DeliveryID:    job.JobID,
+		WebhookID:     args.WebhookID,
+		Event:         args.VocabularyEvent(),
+		Status:        usecase.WebhookDeliveryStatus(job.Status),
+		Attempts:      job.Attempts,
+		NextAttemptAt: job.RunAt,
+		LastError:     job.LastError,
+		CreatedAt:     job.CreatedAt,
+	}
+	if job.Status == usecase.JobSucceeded {
+		delivery.Status = usecase.DeliveryDelivered
+		delivery.DeliveredAt = job.FinishedAt
+	}
+	return delivery, nil
+}
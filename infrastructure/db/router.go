@@ -0,0 +1,196 @@
+package db
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/takumi616/golang-backend-sample/config"
+)
+
+// Time limit of a health check of the replica
+const replicaPingTimeout = 2 * time.Second
+
+// Router sends the queries to the replica and everything else to the primary.
+// Reads fall back to the primary while the replica is unhealthy,
+// and after a write in a context prepared with WithReadYourWrites or before the time it was given.
+type Router struct {
+	Primary *pgxpool.Pool
+	Replica *pgxpool.Pool
+
+	replicaHealthy atomic.Bool
+	stopWatch      context.CancelFunc
+	watchDone      chan struct{}
+}
+
+// OpenRouter opens the primary and, when DATABASE_REPLICA_URL is set, the replica.
+// An unreachable replica is not an error; reads go to the primary until it recovers.
+func OpenRouter(ctx context.Context, cfg *config.Config) (*Router, error) {
+	primary, err := Open(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.DatabaseReplicaURL == "" {
+		return NewRouter(ctx, primary, nil, 0), nil
+	}
+
+	// The replica shares the pool settings of the primary
+	replicaCfg := *cfg
+	replicaCfg.DatabaseURL = cfg.DatabaseReplicaURL
+	poolConfig, err := PoolConfig(&replicaCfg)
+	if err != nil {
+		slog.ErrorContext(ctx, "invalid database replica settings", "error", err)
+		primary.Close()
+		return nil, err
+	}
+
+	replica, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to open the database replica", "error", err)
+		primary.Close()
+		return nil, err
+	}
+
+	return NewRouter(ctx, primary, replica, cfg.DBReplicaHealthInterval), nil
+}
+
+// NewRouter checks the replica, which may be nil, and keeps checking it every healthInterval until Close.
+// A zero healthInterval checks it only once.
+func NewRouter(ctx context.Context, primary, replica *pgxpool.Pool, healthInterval time.Duration) *Router {
+	r := &Router{
+		Primary: primary,
+		Replica: replica,
+	}
+	if replica == nil {
+		return r
+	}
+
+	// Assume healthy so that an unreachable replica is reported by the first check
+	r.replicaHealthy.Store(true)
+	r.checkReplica(ctx)
+	if healthInterval > 0 {
+		watchCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		r.stopWatch = cancel
+		r.watchDone = make(chan struct{})
+		go r.watchReplica(watchCtx, healthInterval)
+	}
+
+	return r
+}
+
+// Reader returns the pool for queries
+func (r *Router) Reader(ctx context.Context) *pgxpool.Pool {
+	if r.Replica == nil || !r.replicaHealthy.Load() || Pinned(ctx) {
+		return r.Primary
+	}
+	return r.Replica
+}
+
+// Writer returns the primary and pins the reads of ctx to it
+func (r *Router) Writer(ctx context.Context) *pgxpool.Pool {
+	pin(ctx)
+	return r.Primary
+}
+
+// Close stops the health check and closes both pools
+func (r *Router) Close() {
+	if r.stopWatch != nil {
+		r.stopWatch()
+		<-r.watchDone
+	}
+	if r.Replica != nil {
+		r.Replica.Close()
+	}
+	r.Primary.Close()
+}
+
+func (r *Router) watchReplica(ctx context.Context, interval time.Duration) {
+	defer close(r.watchDone)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.checkReplica(ctx)
+		}
+	}
+}
+
+// checkReplica pings the replica and logs when its health changes
+func (r *Router) checkReplica(ctx context.Context) {
+	pingCtx, cancel := context.WithTimeout(ctx, replicaPingTimeout)
+	defer cancel()
+
+	err := r.Replica.Ping(pingCtx)
+	if ctx.Err() != nil {
+		return
+	}
+
+	healthy := err == nil
+	if r.replicaHealthy.Swap(healthy) == healthy {
+		return
+	}
+
+	if healthy {
+		slog.InfoContext(ctx, "database replica is healthy, reads go to the replica")
+	} else {
+		slog.WarnContext(
+			ctx, "database replica is unhealthy, reads fall back to the primary", slog.String("error", err.Error()),
+		)
+	}
+}
+
+type readYourWritesKey struct{}
+
+type readYourWrites struct {
+	written atomic.Bool
+
+	// Reads go to the primary before it, for the writes of earlier requests
+	until time.Time
+}
+
+// WithReadYourWrites returns a context whose reads go to the primary once it has written
+func WithReadYourWrites(ctx context.Context) context.Context {
+	return WithReadYourWritesUntil(ctx, time.Time{})
+}
+
+// WithReadYourWritesUntil is WithReadYourWrites with the reads also going to the primary until the time,
+// so that a client sees the writes of its earlier requests
+func WithReadYourWritesUntil(ctx context.Context, until time.Time) context.Context {
+	return context.WithValue(ctx, readYourWritesKey{}, &readYourWrites{until: until})
+}
+
+// Written reports whether a context prepared with WithReadYourWrites has written
+func Written(ctx context.Context) bool {
+	rw, ok := ctx.Value(readYourWritesKey{}).(*readYourWrites)
+	return ok && rw.written.Load()
+}
+
+type primaryKey struct{}
+
+// WithPrimary returns a context whose reads go to the primary, for a caller that keeps what it reads
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// Pinned reports whether the reads of ctx go to the primary whatever the health of the replica
+func Pinned(ctx context.Context) bool {
+	if primary, _ := ctx.Value(primaryKey{}).(bool); primary {
+		return true
+	}
+	rw, ok := ctx.Value(readYourWritesKey{}).(*readYourWrites)
+	return ok && (rw.written.Load() || time.Now().Before(rw.until))
+}
+
+func pin(ctx context.Context) {
+	if rw, ok := ctx.Value(readYourWritesKey{}).(*readYourWrites); ok {
+		rw.written.Store(true)
+	}
+}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// newUnreachablePool returns a pool that never connects, which is enough to compare identities
+func newUnreachablePool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	pool, err := pgxpool.New(context.Background(), "host=127.0.0.1 port=1 user=app dbname=app connect_timeout=1")
+	if err != nil {
+		t.Fatalf("failed to create the pool: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	return pool
+}
+
+func TestRouterReader(t *testing.T) {
+	ctx := context.Background()
+	primary, replica := newUnreachablePool(t), newUnreachablePool(t)
+
+	// The first check fails since nothing listens on the port
+	router := NewRouter(ctx, primary, replica, 0)
+	if got := router.Reader(ctx); got != primary {
+		t.Error("Reader returned the unhealthy replica, want the primary")
+	}
+
+	router.replicaHealthy.Store(true)
+	if got := router.Reader(ctx); got != replica {
+		t.Error("Reader returned the primary, want the healthy replica")
+	}
+
+	// A write pins the later reads of the same context only
+	requestCtx := WithReadYourWrites(ctx)
+	if got := router.Reader(requestCtx); got != replica {
+		t.Error("Reader returned the primary before any write, want the replica")
+	}
+	if got := router.Writer(requestCtx); got != primary {
+		t.Error("Writer returned the replica, want the primary")
+	}
+	if got := router.Reader(requestCtx); got != primary {
+		t.Error("Reader returned the replica after a write, want the primary")
+	}
+	if got := router.Reader(WithReadYourWrites(ctx)); got != replica {
+		t.Error("Reader of another request returned the primary, want the replica")
+	}
+
+	// The writes of an earlier request pin the reads until the given time
+	if got := router.Reader(WithReadYourWritesUntil(ctx, time.Now().Add(time.Minute))); got != primary {
+		t.Error("Reader before the pin expired returned the replica, want the primary")
+	}
+	if got := router.Reader(WithReadYourWritesUntil(ctx, time.Now().Add(-time.Second))); got != replica {
+		t.Error("Reader after the pin expired returned the primary, want the replica")
+	}
+
+	if got := router.Reader(WithPrimary(ctx)); got != primary {
+		t.Error("Reader of a context prepared with WithPrimary returned the replica, want the primary")
+	}
+}
+
+func TestRouterWithoutReplica(t *testing.T) {
+	ctx := context.Background()
+	primary := newUnreachablePool(t)
+
+	router := NewRouter(ctx, primary, nil, 0)
+	if got := router.Reader(ctx); got != primary {
+		t.Error("Reader without a replica did not return the primary")
+	}
+}
@@ -0,0 +1,131 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLite version of the migrations
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS vocabularies (
+    vocabulary_no INTEGER PRIMARY KEY AUTOINCREMENT,
+    title VARCHAR(20) NOT NULL UNIQUE,
+    meaning TEXT NOT NULL,
+    sentence TEXT NOT NULL,
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TEXT NOT NULL DEFAULT '',
+    updated_at TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL DEFAULT '',
+    updated_by TEXT NOT NULL DEFAULT ''
+);
+CREATE TABLE IF NOT EXISTS api_keys (
+    key_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    prefix TEXT NOT NULL UNIQUE,
+    secret_hash TEXT NOT NULL,
+    scopes TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    expires_at TEXT NOT NULL DEFAULT '',
+    last_used_at TEXT NOT NULL DEFAULT '',
+    revoked_at TEXT NOT NULL DEFAULT ''
+);`
+
+// SQLiteTimeLayout stores UTC times as fixed-width text, so that they sort and compare as strings
+const SQLiteTimeLayout = "2006-01-02T15:04:05.000000Z"
+
+type sqliteColumn struct {
+	Table      string
+	Column     string
+	Definition string
+
+	// Value set on the existing rows when the column is added. nil keeps the default.
+	Backfill func() any
+}
+
+// Columns added after the first release, for database files created before them
+var sqliteColumns = []sqliteColumn{
+	{Table: "vocabularies", Column: "version", Definition: "INTEGER NOT NULL DEFAULT 1"},
+	{Table: "vocabularies", Column: "created_at", Definition: "TEXT NOT NULL DEFAULT ''", Backfill: sqliteNow},
+	{Table: "vocabularies", Column: "updated_at", Definition: "TEXT NOT NULL DEFAULT ''", Backfill: sqliteNow},
+	{Table: "vocabularies", Column: "created_by", Definition: "TEXT NOT NULL DEFAULT ''"},
+	{Table: "vocabularies", Column: "updated_by", Definition: "TEXT NOT NULL DEFAULT ''"},
+}
+
+func sqliteNow() any {
+	return time.Now().UTC().Format(SQLiteTimeLayout)
+}
+
+// OpenSQLite opens the SQLite database file at path and creates or upgrades the schema if needed.
+// Use ":memory:" for a database that lives only as long as the process.
+func OpenSQLite(ctx context.Context, path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to open the database", "error", err)
+		return nil, err
+	}
+
+	// SQLite allows a single writer, and each connection to ":memory:" is a separate database
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.ExecContext(ctx, sqliteSchema); err != nil {
+		slog.ErrorContext(ctx, "failed to create the schema", "error", err)
+		db.Close()
+		return nil, err
+	}
+
+	if err := upgradeSQLite(ctx, db); err != nil {
+		slog.ErrorContext(ctx, "failed to upgrade the schema", "error", err)
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// upgradeSQLite adds the columns missing in an old database file
+func upgradeSQLite(ctx context.Context, db *sql.DB) error {
+	for _, c := range sqliteColumns {
+		var count int
+		err := db.QueryRowContext(
+			ctx, "SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", c.Table, c.Column,
+		).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+
+		if err := addSQLiteColumn(ctx, db, c); err != nil {
+			return err
+		}
+		slog.InfoContext(ctx, "column was added to the sqlite schema", slog.String("table", c.Table), slog.String("column", c.Column))
+	}
+
+	return nil
+}
+
+// addSQLiteColumn adds the column and fills it in one transaction
+func addSQLiteColumn(ctx context.Context, db *sql.DB, c sqliteColumn) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "ALTER TABLE "+c.Table+" ADD COLUMN "+c.Column+" "+c.Definition); err != nil {
+		return err
+	}
+
+	if c.Backfill != nil {
+		if _, err := tx.ExecContext(ctx, "UPDATE "+c.Table+" SET "+c.Column+" = ?", c.Backfill()); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
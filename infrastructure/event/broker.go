@@ -0,0 +1,150 @@
+// Package event delivers the vocabulary events to the subscribers of this process.
+package event
+
+import (
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+)
+
+// Broker is an in-process usecase.VocabularyEventBroker.
+// The events are not shared between processes, and the replay buffer is lost on restart.
+type Broker struct {
+	// Number of the latest events kept for resuming subscriptions
+	ReplaySize int
+
+	// Number of events queued for a subscriber. A subscriber that falls this far behind is dropped
+	// so that Publish never blocks, and can resume from the replay buffer.
+	SubscriberBuffer int
+
+	mu sync.Mutex
+
+	// Distinguishes the event IDs of this process from those of a previous one
+	epoch string
+
+	// Sequence number of the latest event
+	seq uint64
+
+	// The latest events, oldest first. The last one has the sequence number seq.
+	replay []usecase.VocabularyEvent
+
+	subscribers map[chan usecase.VocabularyEvent]struct{}
+	closed      bool
+}
+
+func NewBroker(replaySize, subscriberBuffer int) *Broker {
+	return &Broker{
+		ReplaySize:       replaySize,
+		SubscriberBuffer: subscriberBuffer,
+		epoch:            strconv.FormatInt(time.Now().UnixNano(), 36),
+		subscribers:      make(map[chan usecase.VocabularyEvent]struct{}),
+	}
+}
+
+// Publish assigns an ID to the event and queues it for every subscriber without waiting for them
+func (b *Broker) Publish(event usecase.VocabularyEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	b.seq++
+	event.ID = b.eventID(b.seq)
+
+	b.replay = append(b.replay, event)
+	if len(b.replay) > b.ReplaySize {
+		b.replay = b.replay[len(b.replay)-b.ReplaySize:]
+	}
+
+	for events := range b.subscribers {
+		select {
+		case events <- event:
+		default:
+			// Drop the subscriber rather than block the writer
+			slog.Warn("dropped a subscriber that fell behind", slog.String("eventID", event.ID))
+			delete(b.subscribers, events)
+			close(events)
+		}
+	}
+}
+
+// Subscribe replays the kept events after lastEventID and delivers the new ones
+func (b *Broker) Subscribe(lastEventID string) *usecase.VocabularySubscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	events := make(chan usecase.VocabularyEvent, b.SubscriberBuffer)
+	if b.closed {
+		close(events)
+	} else {
+		b.subscribers[events] = struct{}{}
+	}
+
+	subscription := &usecase.VocabularySubscription{
+		Complete:    true,
+		Events:      events,
+		Unsubscribe: func() { b.unsubscribe(events) },
+	}
+	if b.seq > 0 {
+		subscription.LastEventID = b.eventID(b.seq)
+	}
+
+	if lastEventID == "" {
+		return subscription
+	}
+
+	seq, ok := b.parseEventID(lastEventID)
+	oldest := b.seq - uint64(len(b.replay)) + 1
+	if !ok || seq > b.seq || seq+1 < oldest {
+		// The ID was issued by another process, or the events after it are no longer kept
+		subscription.Complete = false
+		return subscription
+	}
+
+	subscription.Replay = append([]usecase.VocabularyEvent(nil), b.replay[len(b.replay)-int(b.seq-seq):]...)
+	return subscription
+}
+
+// Close ends every subscription. Events published after it are discarded.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for events := range b.subscribers {
+		delete(b.subscribers, events)
+		close(events)
+	}
+}
+
+func (b *Broker) unsubscribe(events chan usecase.VocabularyEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// The channel is already closed when the subscriber was dropped
+	if _, ok := b.subscribers[events]; ok {
+		delete(b.subscribers, events)
+		close(events)
+	}
+}
+
+func (b *Broker) eventID(seq uint64) string {
+	return b.epoch + "-" + strconv.FormatUint(seq, 10)
+}
+
+// parseEventID returns the sequence number of an event ID issued by this broker
+func (b *Broker) parseEventID(id string) (uint64, bool) {
+	epoch, seq, ok := strings.Cut(id, "-")
+	if !ok || epoch != b.epoch {
+		return 0, false
+	}
+
+	n, err := strconv.ParseUint(seq, 10, 64)
+	return n, err == nil
+}
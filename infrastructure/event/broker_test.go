@@ -0,0 +1,115 @@
+package event
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+)
+
+func publish(b *Broker, vocabularyNos ...int64) {
+	for _, vocabularyNo := range vocabularyNos {
+		b.Publish(usecase.VocabularyEvent{Type: usecase.VocabularyCreated, VocabularyNo: vocabularyNo})
+	}
+}
+
+func vocabularyNos(events []usecase.VocabularyEvent) []int64 {
+	nos := []int64{}
+	for _, event := range events {
+		nos = append(nos, event.VocabularyNo)
+	}
+	return nos
+}
+
+func TestBrokerReplay(t *testing.T) {
+	b := NewBroker(3, 10)
+	publish(b, 1)
+	first := b.Subscribe("").LastEventID
+	publish(b, 2, 3)
+	third := b.Subscribe("").LastEventID
+	publish(b, 4)
+
+	tests := []struct {
+		name         string
+		lastEventID  string
+		wantComplete bool
+		want         []int64
+	}{
+		{"no event ID", "", true, []int64{}},
+		{"kept events", first, true, []int64{2, 3, 4}},
+		{"latest events", third, true, []int64{4}},
+		{"evicted events", b.eventID(0), false, []int64{}},
+		{"future event", b.eventID(9), false, []int64{}},
+		{"another process", "other-1", false, []int64{}},
+		{"malformed", "1", false, []int64{}},
+	}
+
+	for _, tt := range tests {
+		subscription := b.Subscribe(tt.lastEventID)
+		if subscription.Complete != tt.wantComplete {
+			t.Errorf("%s: Complete = %v, want %v", tt.name, subscription.Complete, tt.wantComplete)
+		}
+		if got := vocabularyNos(subscription.Replay); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s: replay = %v, want %v", tt.name, got, tt.want)
+		}
+		if subscription.LastEventID != b.eventID(4) {
+			t.Errorf("%s: LastEventID = %q, want %q", tt.name, subscription.LastEventID, b.eventID(4))
+		}
+		subscription.Unsubscribe()
+	}
+}
+
+func TestBrokerDropsSlowSubscriber(t *testing.T) {
+	b := NewBroker(10, 2)
+	slow := b.Subscribe("")
+	fast := b.Subscribe("")
+
+	publish(b, 1, 2)
+	for range 2 {
+		<-fast.Events
+	}
+
+	// The third event does not fit in the queue of the slow subscriber
+	publish(b, 3)
+
+	var received []usecase.VocabularyEvent
+	for event := range slow.Events {
+		received = append(received, event)
+	}
+	if got := vocabularyNos(received); len(got) != 2 {
+		t.Fatalf("slow subscriber received %v, want [1 2] and then a closed channel", got)
+	}
+
+	// The fast subscriber keeps receiving
+	if event := <-fast.Events; event.VocabularyNo != 3 {
+		t.Errorf("fast subscriber received %d, want 3", event.VocabularyNo)
+	}
+
+	// The slow subscriber resumes from the replay buffer
+	resumed := b.Subscribe(received[1].ID)
+	if got := vocabularyNos(resumed.Replay); !resumed.Complete || len(got) != 1 || got[0] != 3 {
+		t.Errorf("resumed: Complete = %v, replay = %v, want [3]", resumed.Complete, got)
+	}
+
+	// Unsubscribing a dropped subscriber is harmless
+	slow.Unsubscribe()
+	fast.Unsubscribe()
+	resumed.Unsubscribe()
+}
+
+func TestBrokerClose(t *testing.T) {
+	b := NewBroker(10, 10)
+	subscription := b.Subscribe("")
+
+	b.Close()
+	if _, ok := <-subscription.Events; ok {
+		t.Error("the subscription is still open after Close")
+	}
+
+	// Later subscriptions end at once and the events are discarded
+	publish(b, 1)
+	if _, ok := <-b.Subscribe("").Events; ok {
+		t.Error("a subscription after Close is open")
+	}
+	subscription.Unsubscribe()
+}
@@ -0,0 +1,69 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/infrastructure/web"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// authorize checks the API key of a call against the scope of its method, as the http routes do.
+// The health and reflection services are public, and any other method without a scope is denied.
+func (s *Server) authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	scope, ok := s.Scopes[fullMethod]
+	if !ok {
+		if isPublic(fullMethod) {
+			return ctx, nil
+		}
+		slog.ErrorContext(ctx, "grpc method has no scope", slog.String("method", fullMethod))
+		return nil, status.Error(codes.PermissionDenied, "The method is not available to API keys.")
+	}
+
+	token := web.APIKeyToken(firstValue(ctx, "x-api-key"), firstValue(ctx, "authorization"))
+	if token == "" {
+		if slices.Contains(s.AnonymousScopes, scope) {
+			return ctx, nil
+		}
+		return nil, status.Error(codes.Unauthenticated, "An API key is required. Send it as x-api-key or a bearer token.")
+	}
+
+	if s.APIKeys == nil {
+		return nil, status.Error(codes.Unauthenticated, "The API key is invalid, expired or revoked.")
+	}
+	key, err := s.APIKeys.Authenticate(ctx, token)
+	switch {
+	case errors.Is(err, usecase.ErrInvalidAPIKey):
+		return nil, status.Error(codes.Unauthenticated, "The API key is invalid, expired or revoked.")
+	case err != nil:
+		slog.ErrorContext(ctx, "failed to authenticate an api key", slog.String("error", err.Error()))
+		return nil, status.Error(codes.Internal, "Failed to check the API key due to a server error.")
+	case !key.HasScope(scope):
+		slog.WarnContext(ctx, "api key lacks the scope", slog.String("prefix", key.Prefix), slog.String("scope", string(scope)))
+		return nil, status.Error(codes.PermissionDenied, fmt.Sprintf("The API key lacks the %s scope.", scope))
+	}
+
+	// Attribute the writes to the verified key, as listed by its prefix
+	return usecase.WithActor(usecase.WithAPIKey(ctx, key), "apikey:"+key.Prefix), nil
+}
+
+// isPublic reports whether the method belongs to the health or reflection services
+func isPublic(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+healthpb.Health_ServiceDesc.ServiceName+"/") ||
+		strings.HasPrefix(fullMethod, "/grpc.reflection.")
+}
+
+func firstValue(ctx context.Context, key string) string {
+	if values := metadata.ValueFromIncomingContext(ctx, key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
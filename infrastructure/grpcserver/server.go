@@ -0,0 +1,159 @@
+// Package grpcserver runs the gRPC server next to the http server.
+package grpcserver
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/domain"
+	"github.com/takumi616/golang-backend-sample/infrastructure/web"
+	"github.com/takumi616/golang-backend-sample/interface/rpc/vocabularyv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// ContextFunc derives the context of a call, as the http middlewares do for a request
+type ContextFunc func(ctx context.Context) context.Context
+
+type Server struct {
+	Port    string
+	Service vocabularyv1.VocabularyServiceServer
+
+	// Serves over TLS when set, with the certificate of the http server
+	TLS *web.TLSReloader
+
+	// Grace period for in-flight calls on shutdown. The remaining calls are cancelled after it.
+	ShutdownTimeout time.Duration
+
+	// Checks the API keys of the calls against Scopes, keyed by the full method names
+	APIKeys web.APIKeyAuthenticator
+	Scopes  map[string]domain.Scope
+
+	// Scopes of a call without a key. Empty unless anonymous reads are enabled.
+	AnonymousScopes []domain.Scope
+
+	// Applied to the context of every call in order, after its API key is checked
+	Contexts []ContextFunc
+}
+
+func NewServer(port string, service vocabularyv1.VocabularyServiceServer, shutdownTimeout time.Duration, tlsReloader *web.TLSReloader) *Server {
+	return &Server{
+		Port:            port,
+		Service:         service,
+		ShutdownTimeout: shutdownTimeout,
+		TLS:             tlsReloader,
+	}
+}
+
+// Run serves until ctx is cancelled, then stops gracefully
+func (s *Server) Run(ctx context.Context) error {
+	// Create a grpc listener
+	listener, err := net.Listen("tcp", ":"+s.Port)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create a grpc listener", "error", err)
+		return err
+	}
+
+	return s.Serve(ctx, listener)
+}
+
+// Serve serves on listener until ctx is cancelled, then stops gracefully
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	server := grpc.NewServer(s.options()...)
+
+	// Register the services
+	vocabularyv1.RegisterVocabularyServiceServer(server, s.Service)
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(vocabularyv1.VocabularyService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	reflection.Register(server)
+
+	// Start the grpc server
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(listener)
+	}()
+	slog.InfoContext(ctx, "grpc server started", slog.String("address", listener.Addr().String()))
+
+	select {
+	case err := <-served:
+		slog.ErrorContext(ctx, "failed to serve grpc", "error", err)
+		return err
+	case <-ctx.Done():
+	}
+
+	// Report NOT_SERVING to the health checks while the calls drain
+	healthServer.Shutdown()
+
+	// Shutdown the grpc server
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	timer := time.NewTimer(s.ShutdownTimeout)
+	defer timer.Stop()
+	select {
+	case <-stopped:
+	case <-timer.C:
+		slog.ErrorContext(ctx, "failed to shut down the grpc server in time, cancelling the remaining calls")
+		server.Stop()
+		<-stopped
+	}
+
+	slog.InfoContext(ctx, "grpc server stopped")
+	return nil
+}
+
+func (s *Server) options() []grpc.ServerOption {
+	options := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(s.unaryInterceptor),
+		grpc.ChainStreamInterceptor(s.streamInterceptor),
+	}
+	if s.TLS != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(s.TLS.TLSConfig())))
+	}
+	return options
+}
+
+func (s *Server) callContext(ctx context.Context) context.Context {
+	for _, f := range s.Contexts {
+		ctx = f(ctx)
+	}
+	return ctx
+}
+
+func (s *Server) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := s.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(s.callContext(ctx), req)
+}
+
+func (s *Server) streamInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authorize(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &contextStream{ServerStream: stream, ctx: s.callContext(ctx)})
+}
+
+// contextStream replaces the context of a stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,140 @@
+package grpcserver
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+	"github.com/takumi616/golang-backend-sample/interface/rpc/vocabularyv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// actorService answers Create and List with the actor of the call
+type actorService struct {
+	vocabularyv1.UnimplementedVocabularyServiceServer
+	actor chan string
+}
+
+func (s *actorService) Create(ctx context.Context, _ *vocabularyv1.CreateRequest) (*vocabularyv1.CreateResponse, error) {
+	s.actor <- usecase.ActorFrom(ctx)
+	return &vocabularyv1.CreateResponse{}, nil
+}
+
+func (s *actorService) List(_ *vocabularyv1.ListRequest, stream grpc.ServerStreamingServer[vocabularyv1.Vocabulary]) error {
+	s.actor <- usecase.ActorFrom(stream.Context())
+	return nil
+}
+
+// fakeAuthenticator knows the keys by their token
+type fakeAuthenticator map[string]*domain.APIKey
+
+func (f fakeAuthenticator) Authenticate(_ context.Context, token string) (*domain.APIKey, error) {
+	key, ok := f[token]
+	if !ok {
+		return nil, usecase.ErrInvalidAPIKey
+	}
+	return key, nil
+}
+
+func TestServer(t *testing.T) {
+	service := &actorService{actor: make(chan string, 1)}
+	server := NewServer("0", service, time.Second, nil)
+	server.APIKeys = fakeAuthenticator{
+		"reader": {Prefix: "vk_reader", Scopes: []domain.Scope{domain.ScopeVocabRead}},
+		"writer": {Prefix: "vk_writer", Scopes: []domain.Scope{domain.ScopeVocabWrite}},
+	}
+	server.Scopes = map[string]domain.Scope{
+		vocabularyv1.VocabularyService_Create_FullMethodName: domain.ScopeVocabWrite,
+		vocabularyv1.VocabularyService_List_FullMethodName:   domain.ScopeVocabRead,
+	}
+	server.AnonymousScopes = []domain.Scope{domain.ScopeVocabRead}
+
+	listener := bufconn.Listen(1 << 20)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Serve(ctx, listener)
+	}()
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// The service is reported as serving without a key
+	health := healthpb.NewHealthClient(conn)
+	res, err := health.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "vocabulary.v1.VocabularyService"})
+	if err != nil || res.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Check = %v, %v, want SERVING", res, err)
+	}
+
+	client := vocabularyv1.NewVocabularyServiceClient(conn)
+	tests := []struct {
+		name      string
+		list      bool
+		metadata  []string
+		wantCode  codes.Code
+		wantActor string
+	}{
+		{name: "anonymous write", wantCode: codes.Unauthenticated},
+		{name: "anonymous read", list: true, wantCode: codes.OK},
+		{name: "invalid key", metadata: []string{"x-api-key", "revoked"}, wantCode: codes.Unauthenticated},
+		{name: "invalid key on a read", list: true, metadata: []string{"x-api-key", "revoked"}, wantCode: codes.Unauthenticated},
+		{name: "missing scope", metadata: []string{"x-api-key", "reader"}, wantCode: codes.PermissionDenied},
+		{name: "granted scope", metadata: []string{"x-api-key", "writer"}, wantCode: codes.OK, wantActor: "apikey:vk_writer"},
+		{name: "bearer token", list: true, metadata: []string{"authorization", "Bearer reader"}, wantCode: codes.OK, wantActor: "apikey:vk_reader"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callCtx := metadata.AppendToOutgoingContext(context.Background(), tt.metadata...)
+			if tt.list {
+				var stream grpc.ServerStreamingClient[vocabularyv1.Vocabulary]
+				stream, err = client.List(callCtx, &vocabularyv1.ListRequest{})
+				if err == nil {
+					_, err = stream.Recv()
+				}
+				if err == io.EOF {
+					err = nil
+				}
+			} else {
+				_, err = client.Create(callCtx, &vocabularyv1.CreateRequest{})
+			}
+
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %s, want %s (%v)", code, tt.wantCode, err)
+			}
+			if tt.wantCode == codes.OK {
+				if actor := <-service.actor; actor != tt.wantActor {
+					t.Errorf("actor = %q, want %q", actor, tt.wantActor)
+				}
+			}
+		})
+	}
+
+	// Cancelling the context stops the server
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Serve returned an error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve did not return after the cancellation")
+	}
+}
@@ -0,0 +1,64 @@
+package job
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleNext(t *testing.T) {
+	// A Monday
+	from := time.Date(2024, 1, 1, 10, 30, 15, 0, time.UTC)
+
+	tests := []struct {
+		expression string
+		want       time.Time
+	}{
+		{expression: "* * * * *", want: time.Date(2024, 1, 1, 10, 31, 0, 0, time.UTC)},
+		{expression: "0 3 * * *", want: time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)},
+		{expression: "*/15 * * * *", want: time.Date(2024, 1, 1, 10, 45, 0, 0, time.UTC)},
+		{expression: "30 10 * * *", want: time.Date(2024, 1, 2, 10, 30, 0, 0, time.UTC)},
+		{expression: "0 9-17/4 * * *", want: time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC)},
+		{expression: "0 0 1 * *", want: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{expression: "0 0 * * 0", want: time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC)},
+		{expression: "0 0 29 2 *", want: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{expression: "5,10 12 * 3 *", want: time.Date(2024, 3, 1, 12, 5, 0, 0, time.UTC)},
+
+		// Either day matches when both are restricted
+		{expression: "0 0 15 * 5", want: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)},
+
+		// February 30 never comes
+		{expression: "0 0 30 2 *", want: time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.expression)
+			if err != nil {
+				t.Fatalf("ParseSchedule returned an error: %v", err)
+			}
+			if got := schedule.Next(from); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseScheduleInvalid(t *testing.T) {
+	for _, expression := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 7",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+	} {
+		if _, err := ParseSchedule(expression); err == nil {
+			t.Errorf("ParseSchedule(%q) succeeded, want an error", expression)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+)
+
+// Enqueuer adds the jobs to the queue
+type Enqueuer interface {
+	EnqueueJob(ctx context.Context, job *usecase.Job) (int64, error)
+}
+
+// CronEntry enqueues a copy of Job at every time of Schedule
+type CronEntry struct {
+	Schedule *Schedule
+	Job      usecase.Job
+}
+
+// Scheduler enqueues the cron-style jobs. Every server instance runs one, and the unique key of each
+// scheduled time makes only the first of them enqueue the job.
+type Scheduler struct {
+	Queue   Enqueuer
+	Entries []CronEntry
+}
+
+func NewScheduler(queue Enqueuer) *Scheduler {
+	return &Scheduler{
+		Queue: queue,
+	}
+}
+
+// Add schedules the job with a cron expression evaluated in UTC
+func (s *Scheduler) Add(expression string, job *usecase.Job) error {
+	schedule, err := ParseSchedule(expression)
+	if err != nil {
+		return err
+	}
+
+	s.Entries = append(s.Entries, CronEntry{Schedule: schedule, Job: *job})
+	return nil
+}
+
+// Run enqueues the jobs on their schedules until ctx is cancelled. The times missed while no instance
+// was running are skipped, as in cron.
+func (s *Scheduler) Run(ctx context.Context) error {
+	if len(s.Entries) == 0 {
+		return nil
+	}
+	slog.InfoContext(ctx, "job scheduler started", slog.Int("entries", len(s.Entries)))
+
+	next := make([]time.Time, len(s.Entries))
+	for i, entry := range s.Entries {
+		next[i] = entry.Schedule.Next(time.Now().UTC())
+	}
+
+	for {
+		// Wait for the earliest entry
+		var earliest time.Time
+		for _, t := range next {
+			if !t.IsZero() && (earliest.IsZero() || t.Before(earliest)) {
+				earliest = t
+			}
+		}
+		if earliest.IsZero() {
+			return nil
+		}
+
+		timer := time.NewTimer(time.Until(earliest))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			slog.InfoContext(ctx, "job scheduler stopped")
+			return nil
+		case <-timer.C:
+		}
+
+		for i, entry := range s.Entries {
+			if !next[i].Equal(earliest) {
+				continue
+			}
+			s.enqueue(ctx, entry, earliest)
+			next[i] = entry.Schedule.Next(earliest)
+		}
+	}
+}
+
+// enqueue adds the job of the entry for the scheduled time
+func (s *Scheduler) enqueue(ctx context.Context, entry CronEntry, scheduledAt time.Time) {
+	job := entry.Job
+	job.RunAt = scheduledAt
+	job.UniqueKey = fmt.Sprintf("cron:%s:%s:%d", job.Kind, entry.Schedule, scheduledAt.Unix())
+
+	jobID, err := s.Queue.EnqueueJob(ctx, &job)
+	if err != nil {
+		slog.ErrorContext(
+			ctx, "failed to enqueue a scheduled job",
+			slog.String("kind", job.Kind), slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	// Another instance enqueued it first
+	if jobID == 0 {
+		return
+	}
+	slog.InfoContext(ctx, "scheduled job was enqueued", slog.String("kind", job.Kind), slog.Int64("jobID", jobID))
+}
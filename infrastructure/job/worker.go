@@ -0,0 +1,80 @@
+package job
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Runner runs the due jobs and returns how many were attempted
+type Runner interface {
+	RunPending(ctx context.Context, limit int) (int, error)
+}
+
+// Worker polls the job queue and runs the due jobs
+type Worker struct {
+	Runner Runner
+
+	// Time between the polls while the queue has nothing due
+	PollInterval time.Duration
+
+	// Maximum number of jobs run at the same time
+	Concurrency int
+
+	// Time the running jobs are given to finish on shutdown before their context is cancelled
+	DrainTimeout time.Duration
+}
+
+func NewWorker(runner Runner, pollInterval time.Duration, concurrency int, drainTimeout time.Duration) *Worker {
+	return &Worker{
+		Runner:       runner,
+		PollInterval: pollInterval,
+		Concurrency:  concurrency,
+		DrainTimeout: drainTimeout,
+	}
+}
+
+// Run runs the jobs until ctx is cancelled. No job is claimed after that, and the running ones are drained
+// before it returns. The errors are logged so that a database outage does not stop the worker.
+func (w *Worker) Run(ctx context.Context) error {
+	slog.InfoContext(
+		ctx, "job worker started",
+		slog.Duration("pollInterval", w.PollInterval), slog.Int("concurrency", w.Concurrency),
+	)
+
+	// The jobs outlive ctx by DrainTimeout
+	jobCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+	stop := context.AfterFunc(ctx, func() {
+		time.AfterFunc(w.DrainTimeout, cancel)
+	})
+	defer stop()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+		case <-timer.C:
+		}
+
+		// The timer may fire together with the cancellation
+		if ctx.Err() != nil {
+			slog.InfoContext(ctx, "job worker stopped")
+			return nil
+		}
+
+		attempted, err := w.Runner.RunPending(jobCtx, w.Concurrency)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to run the jobs", slog.String("error", err.Error()))
+		}
+
+		// Poll again at once while full batches are due
+		if err == nil && attempted == w.Concurrency {
+			timer.Reset(0)
+		} else {
+			timer.Reset(w.PollInterval)
+		}
+	}
+}
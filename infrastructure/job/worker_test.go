@@ -0,0 +1,75 @@
+package job
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// blockingRunner runs one job that waits for its context, or for the given duration
+type blockingRunner struct {
+	duration time.Duration
+	started  chan struct{}
+	calls    atomic.Int32
+	err      atomic.Value
+}
+
+func (r *blockingRunner) RunPending(ctx context.Context, limit int) (int, error) {
+	if r.calls.Add(1) > 1 {
+		return 0, nil
+	}
+	close(r.started)
+
+	select {
+	case <-ctx.Done():
+		r.err.Store(ctx.Err())
+	case <-time.After(r.duration):
+	}
+	return 1, nil
+}
+
+func TestWorkerDrain(t *testing.T) {
+	tests := []struct {
+		name         string
+		duration     time.Duration
+		drainTimeout time.Duration
+		wantCanceled bool
+	}{
+		{name: "finished within the drain timeout", duration: 50 * time.Millisecond, drainTimeout: time.Minute},
+		{name: "canceled after the drain timeout", duration: time.Minute, drainTimeout: 50 * time.Millisecond, wantCanceled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &blockingRunner{duration: tt.duration, started: make(chan struct{})}
+			worker := NewWorker(runner, time.Hour, 1, tt.drainTimeout)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error)
+			go func() {
+				done <- worker.Run(ctx)
+			}()
+
+			// Stop the worker while the job is running
+			<-runner.started
+			cancel()
+
+			select {
+			case err := <-done:
+				if err != nil {
+					t.Errorf("Run returned an error: %v", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("Run did not return")
+			}
+
+			if canceled := runner.err.Load() != nil; canceled != tt.wantCanceled {
+				t.Errorf("job canceled = %v, want %v", canceled, tt.wantCanceled)
+			}
+			if calls := runner.calls.Load(); calls != 1 {
+				t.Errorf("RunPending was called %d times, want 1", calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+	"github.com/takumi616/golang-backend-sample/interface/controller/helper"
+	"github.com/takumi616/golang-backend-sample/interface/controller/response"
+)
+
+// APIKeyAuthenticator returns the active API key of a token, or usecase.ErrInvalidAPIKey
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, token string) (*domain.APIKey, error)
+}
+
+// Authenticator checks the API key of each request before the rate limit and the handlers see it
+type Authenticator struct {
+	Keys APIKeyAuthenticator
+
+	// Scopes of a request without a key. Empty unless anonymous reads are enabled.
+	AnonymousScopes []domain.Scope
+}
+
+func NewAuthenticator(keys APIKeyAuthenticator, anonymousScopes []domain.Scope) *Authenticator {
+	return &Authenticator{
+		Keys:            keys,
+		AnonymousScopes: anonymousScopes,
+	}
+}
+
+type authenticationKey struct{}
+
+// authentication is the outcome of checking the API key of a request
+type authentication struct {
+	// nil for an anonymous request
+	key *domain.APIKey
+
+	// Scopes granted without a key
+	anonymousScopes []domain.Scope
+
+	// usecase.ErrInvalidAPIKey, or the failure to check the key
+	err error
+}
+
+// Middleware resolves the API key sent as X-API-Key or a bearer token.
+// The routes reject a missing or invalid key themselves, so that public routes stay reachable.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		auth := &authentication{anonymousScopes: a.AnonymousScopes}
+		if token := APIKeyToken(r.Header.Get("X-API-Key"), r.Header.Get("Authorization")); token != "" {
+			key, err := a.Keys.Authenticate(ctx, token)
+			switch {
+			case errors.Is(err, usecase.ErrInvalidAPIKey):
+				auth = &authentication{err: err}
+			case err != nil:
+				slog.ErrorContext(ctx, "failed to authenticate an api key", slog.String("error", err.Error()))
+				auth = &authentication{err: err}
+			default:
+				auth = &authentication{key: key}
+				ctx = usecase.WithAPIKey(ctx, key)
+			}
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, authenticationKey{}, auth)))
+	})
+}
+
+// withScope lets the request through when its API key, or the anonymous scopes without a key, grant scope.
+// A request that did not pass the Authenticator is anonymous with no scopes.
+func withScope(scope domain.Scope, next http.HandlerFunc) http.HandlerFunc {
+	if scope == "" {
+		return next
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		auth, ok := ctx.Value(authenticationKey{}).(*authentication)
+		if !ok {
+			auth = &authentication{}
+		}
+
+		switch {
+		case errors.Is(auth.err, usecase.ErrInvalidAPIKey):
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			helper.WriteResponse(
+				ctx, w, http.StatusUnauthorized,
+				response.ErrorRes{Message: "The API key is invalid, expired or revoked."},
+			)
+			return
+		case auth.err != nil:
+			helper.WriteResponse(
+				ctx, w, http.StatusInternalServerError,
+				response.ErrorRes{Message: "Failed to check the API key due to a server error."},
+			)
+			return
+		case auth.key == nil && slices.Contains(auth.anonymousScopes, scope):
+			next(w, r)
+			return
+		case auth.key == nil:
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			helper.WriteResponse(
+				ctx, w, http.StatusUnauthorized,
+				response.ErrorRes{Message: "An API key is required. Send it as X-API-Key or a bearer token."},
+			)
+			return
+		case !auth.key.HasScope(scope):
+			slog.WarnContext(ctx, "api key lacks the scope", slog.String("prefix", auth.key.Prefix), slog.String("scope", string(scope)))
+			helper.WriteResponse(
+				ctx, w, http.StatusForbidden,
+				response.ErrorRes{Message: fmt.Sprintf("The API key lacks the %s scope.", scope)},
+			)
+			return
+		}
+
+		// Attribute the writes to the key, as listed by its prefix
+		next(w, r.WithContext(usecase.WithActor(ctx, "apikey:"+auth.key.Prefix)))
+	}
+}
+
+// APIKeyToken returns the API key sent as X-API-Key or a bearer token, or an empty string.
+// The gRPC server reads the same values from the metadata.
+func APIKeyToken(apiKey, authorization string) string {
+	if apiKey != "" {
+		return apiKey
+	}
+	if token, ok := strings.CutPrefix(authorization, "Bearer "); ok {
+		return token
+	}
+	return ""
+}
@@ -0,0 +1,162 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+)
+
+// fakeAuthenticator knows the keys by their token
+type fakeAuthenticator map[string]*domain.APIKey
+
+func (f fakeAuthenticator) Authenticate(_ context.Context, token string) (*domain.APIKey, error) {
+	if token == "broken" {
+		return nil, errors.New("connection refused")
+	}
+	key, ok := f[token]
+	if !ok {
+		return nil, usecase.ErrInvalidAPIKey
+	}
+	return key, nil
+}
+
+func TestRoutesHaveScopes(t *testing.T) {
+	for _, route := range NewServeMux(nil, nil, nil, time.Second).routes() {
+		public := route.Pattern == "GET /openapi.json" || route.Pattern == "GET /docs"
+		if route.Scope == "" && !public {
+			t.Errorf("route %q has no scope", route.Pattern)
+		}
+		if strings.HasPrefix(route.Pattern, "GET ") && strings.HasSuffix(string(route.Scope), ":write") {
+			t.Errorf("route %q takes the write scope %s", route.Pattern, route.Scope)
+		}
+	}
+}
+
+func TestWithScope(t *testing.T) {
+	keys := fakeAuthenticator{
+		"reader": {Prefix: "vk_reader", Scopes: []domain.Scope{domain.ScopeVocabRead}},
+		"admin":  {Prefix: "vk_admin", Scopes: []domain.Scope{domain.ScopeAPIKeyAdmin}},
+	}
+
+	// Echoes the actor the request is attributed to
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(usecase.ActorFrom(r.Context())))
+	}
+
+	tests := []struct {
+		name          string
+		anonymousRead bool
+		scope         domain.Scope
+		header        string
+		token         string
+		wantStatus    int
+		wantBody      string
+	}{
+		{
+			name: "anonymous", scope: domain.ScopeVocabRead,
+			wantStatus: http.StatusUnauthorized, wantBody: "An API key is required",
+		},
+		{name: "anonymous read", anonymousRead: true, scope: domain.ScopeVocabRead, wantStatus: http.StatusOK},
+		{
+			name: "anonymous write", anonymousRead: true, scope: domain.ScopeVocabWrite,
+			wantStatus: http.StatusUnauthorized, wantBody: "An API key is required",
+		},
+		{
+			name: "key management is never anonymous", anonymousRead: true, scope: domain.ScopeAPIKeyAdmin,
+			wantStatus: http.StatusUnauthorized, wantBody: "An API key is required",
+		},
+		{
+			name: "granted scope", scope: domain.ScopeVocabRead, header: "X-API-Key", token: "reader",
+			wantStatus: http.StatusOK, wantBody: "apikey:vk_reader",
+		},
+		{
+			name: "bearer token", scope: domain.ScopeAPIKeyAdmin, header: "Authorization", token: "Bearer admin",
+			wantStatus: http.StatusOK, wantBody: "apikey:vk_admin",
+		},
+		{
+			name: "missing scope", scope: domain.ScopeVocabWrite, header: "X-API-Key", token: "reader",
+			wantStatus: http.StatusForbidden, wantBody: "The API key lacks the vocab:write scope.",
+		},
+		{
+			// A key that is sent is checked even when anonymous reads are allowed
+			name: "invalid key", anonymousRead: true, scope: domain.ScopeVocabRead, header: "X-API-Key", token: "revoked",
+			wantStatus: http.StatusUnauthorized, wantBody: "The API key is invalid, expired or revoked.",
+		},
+		{
+			name: "server error", scope: domain.ScopeVocabRead, header: "X-API-Key", token: "broken",
+			wantStatus: http.StatusInternalServerError, wantBody: "due to a server error",
+		},
+		{name: "public route", wantStatus: http.StatusOK},
+		{name: "public route with an invalid key", header: "X-API-Key", token: "revoked", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var anonymousScopes []domain.Scope
+			if tt.anonymousRead {
+				anonymousScopes = []domain.Scope{domain.ScopeVocabRead}
+			}
+			authenticator := NewAuthenticator(keys, anonymousScopes)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.token)
+			}
+			rec := httptest.NewRecorder()
+			authenticator.Middleware(withScope(tt.scope, handler)).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body = %q, want %q in it", rec.Body.String(), tt.wantBody)
+			}
+			if rec.Code == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("WWW-Authenticate is not set")
+			}
+		})
+	}
+}
+
+func TestScopesAreEnforcedByRoute(t *testing.T) {
+	authenticator := NewAuthenticator(fakeAuthenticator{"reader": {Prefix: "vk_reader", Scopes: []domain.Scope{domain.ScopeVocabRead}}}, nil)
+	handler := authenticator.Middleware(NewServeMux(nil, nil, nil, time.Second).RegisterHandler())
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		token      string
+		wantStatus int
+	}{
+		// The write route rejects the key before the controller runs
+		{name: "missing scope", method: http.MethodDelete, target: "/api/vocabularies/1", token: "reader", wantStatus: http.StatusForbidden},
+		{name: "without a key", method: http.MethodGet, target: "/api/vocabularies", wantStatus: http.StatusUnauthorized},
+		{name: "webhook route without a key", method: http.MethodGet, target: "/api/webhooks", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			if tt.token != "" {
+				req.Header.Set("X-API-Key", tt.token)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if rec.Header().Get("Cache-Control") == "" {
+				t.Error("the rejection has no Cache-Control")
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	cors := NewCORS(
+		[]string{"https://app.example.com"},
+		[]string{"GET", "POST", "PUT", "DELETE"},
+		[]string{"Content-Type"},
+		true, 10*time.Minute,
+	)
+	handler := cors.Middleware(NewServeMux(nil, nil, nil, time.Second).RegisterHandler())
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		origin         string
+		requestMethod  string
+		requestHeaders string
+		wantStatus     int
+		wantHeaders    map[string]string
+	}{
+		{
+			name:           "preflight",
+			method:         http.MethodOptions,
+			path:           "/api/vocabularies/1",
+			origin:         "https://app.example.com",
+			requestMethod:  "PUT",
+			requestHeaders: "content-type",
+			wantStatus:     http.StatusNoContent,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://app.example.com",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Allow-Methods":     "GET, POST, PUT, DELETE",
+				"Access-Control-Allow-Headers":     "Content-Type",
+				"Access-Control-Max-Age":           "600",
+				"Allow":                            "OPTIONS, GET, PUT, DELETE",
+			},
+		},
+		{
+			name:          "preflight from a disallowed origin",
+			method:        http.MethodOptions,
+			path:          "/api/vocabularies",
+			origin:        "https://evil.example.com",
+			requestMethod: "POST",
+			wantStatus:    http.StatusNoContent,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin":  "",
+				"Access-Control-Allow-Methods": "",
+			},
+		},
+		{
+			name:           "preflight with a disallowed header",
+			method:         http.MethodOptions,
+			path:           "/api/vocabularies",
+			origin:         "https://app.example.com",
+			requestMethod:  "POST",
+			requestHeaders: "X-Custom",
+			wantStatus:     http.StatusNoContent,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Methods": "",
+			},
+		},
+		{
+			name:          "preflight for an unregistered path",
+			method:        http.MethodOptions,
+			path:          "/api/unknown",
+			origin:        "https://app.example.com",
+			requestMethod: "GET",
+			wantStatus:    http.StatusNotFound,
+		},
+		{
+			name:       "actual request",
+			method:     http.MethodGet,
+			path:       "/openapi.json",
+			origin:     "https://app.example.com",
+			wantStatus: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin":   "https://app.example.com",
+				"Access-Control-Expose-Headers": "RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, Retry-After",
+			},
+		},
+		{
+			name:       "actual request from a disallowed origin",
+			method:     http.MethodGet,
+			path:       "/openapi.json",
+			origin:     "https://evil.example.com",
+			wantStatus: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin":   "",
+				"Access-Control-Expose-Headers": "",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Origin", tt.origin)
+			if tt.requestMethod != "" {
+				req.Header.Set("Access-Control-Request-Method", tt.requestMethod)
+			}
+			if tt.requestHeaders != "" {
+				req.Header.Set("Access-Control-Request-Headers", tt.requestHeaders)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			for header, want := range tt.wantHeaders {
+				if got := rec.Header().Get(header); got != want {
+					t.Errorf("%s = %q, want %q", header, got, want)
+				}
+			}
+		})
+	}
+}
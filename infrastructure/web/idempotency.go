@@ -0,0 +1,188 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/interface/controller/helper"
+	"github.com/takumi616/golang-backend-sample/interface/controller/response"
+)
+
+const maxIdempotencyKeyLength = 255
+
+type Idempotency struct {
+	Store usecase.IdempotencyStore
+
+	// How long a key is remembered
+	TTL time.Duration
+
+	// Maximum size of a request body in bytes
+	MaxBodyBytes int64
+}
+
+func NewIdempotency(store usecase.IdempotencyStore, ttl time.Duration, maxBodyBytes int64) *Idempotency {
+	return &Idempotency{
+		Store:        store,
+		TTL:          ttl,
+		MaxBodyBytes: maxBodyBytes,
+	}
+}
+
+// Middleware replays the recorded response when a POST request is retried with the same Idempotency-Key.
+// Keys are scoped to the client, identified as in the rate limiter.
+func (i *Idempotency) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Idempotency-Key")
+		if r.Method != http.MethodPost || key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		if len(key) > maxIdempotencyKeyLength {
+			helper.WriteResponse(ctx, w, http.StatusBadRequest, response.ErrorRes{
+				Message: fmt.Sprintf("The Idempotency-Key header must be %d characters or fewer.", maxIdempotencyKeyLength),
+			})
+			return
+		}
+
+		// Read the body to hash it, and hand a copy to the handler
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, i.MaxBodyBytes))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				helper.WriteResponse(ctx, w, http.StatusRequestEntityTooLarge, response.ErrorRes{
+					Message: fmt.Sprintf("Request body is too large. It must be %d bytes or fewer.", i.MaxBodyBytes),
+				})
+				return
+			}
+
+			slog.ErrorContext(ctx, "failed to read a request body", slog.String("error", err.Error()))
+			helper.WriteResponse(ctx, w, http.StatusBadRequest, response.ErrorRes{Message: "Failed to read the request body."})
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		storeKey := clientKey(r) + ":" + key
+		requestHash := hashRequest(r, body)
+
+		record, reserved, err := i.Store.Reserve(ctx, storeKey, requestHash, time.Now().Add(i.TTL))
+		if err != nil {
+			// Fail closed since handling the request could repeat its side effects
+			slog.ErrorContext(ctx, "failed to reserve the idempotency key", slog.String("error", err.Error()))
+			helper.WriteResponse(
+				ctx, w, http.StatusInternalServerError,
+				response.ErrorRes{Message: "Failed to process the request due to a server error."},
+			)
+			return
+		}
+
+		if !reserved {
+			i.writeRecord(w, r, record, requestHash)
+			return
+		}
+
+		// Record the response; the store is updated even if the client has gone away
+		storeCtx := context.WithoutCancel(ctx)
+		recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		completed := false
+		defer func() {
+			if !completed {
+				i.release(storeCtx, storeKey)
+			}
+		}()
+
+		next.ServeHTTP(recorder, r)
+
+		// Let the client retry server errors
+		if recorder.statusCode >= http.StatusInternalServerError {
+			return
+		}
+
+		if err := i.Store.Complete(storeCtx, storeKey, usecase.IdempotencyRecord{
+			RequestHash: requestHash,
+			StatusCode:  recorder.statusCode,
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		}); err != nil {
+			slog.ErrorContext(ctx, "failed to record the response of the idempotency key", slog.String("error", err.Error()))
+			return
+		}
+		completed = true
+	})
+}
+
+// writeRecord answers a request whose key was already used
+func (i *Idempotency) writeRecord(w http.ResponseWriter, r *http.Request, record *usecase.IdempotencyRecord, requestHash string) {
+	ctx := r.Context()
+
+	switch {
+	case record.RequestHash != requestHash:
+		slog.WarnContext(ctx, "idempotency key reused for a different request", slog.String("path", r.URL.Path))
+		helper.WriteResponse(
+			ctx, w, http.StatusUnprocessableEntity,
+			response.ErrorRes{Message: "The Idempotency-Key was already used for a different request."},
+		)
+	case record.StatusCode == 0:
+		helper.WriteResponse(
+			ctx, w, http.StatusConflict,
+			response.ErrorRes{Message: "A request with the same Idempotency-Key is still being processed. Please retry later."},
+		)
+	default:
+		slog.InfoContext(ctx, "replaying the response of the idempotency key", slog.String("path", r.URL.Path))
+		if record.ContentType != "" {
+			w.Header().Set("Content-Type", record.ContentType)
+		}
+		w.Header().Set("Idempotent-Replayed", "true")
+		w.WriteHeader(record.StatusCode)
+		w.Write(record.Body)
+	}
+}
+
+func (i *Idempotency) release(ctx context.Context, key string) {
+	if err := i.Store.Release(ctx, key); err != nil {
+		slog.ErrorContext(ctx, "failed to release the idempotency key", slog.String("error", err.Error()))
+	}
+}
+
+func hashRequest(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %s\n", r.Method, r.URL.Path)
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder copies the response while writing it to the client
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(statusCode int) {
+	if !rec.wroteHeader {
+		rec.statusCode = statusCode
+		rec.wroteHeader = true
+	}
+	rec.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	rec.wroteHeader = true
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+func (rec *responseRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
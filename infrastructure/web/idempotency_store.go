@@ -0,0 +1,82 @@
+package web
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+)
+
+type idempotencyEntry struct {
+	record    usecase.IdempotencyRecord
+	expiresAt time.Time
+}
+
+// MemoryIdempotencyStore keeps the idempotency keys in process memory
+type MemoryIdempotencyStore struct {
+	mu        sync.Mutex
+	entries   map[string]*idempotencyEntry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		entries: make(map[string]*idempotencyEntry),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryIdempotencyStore) Reserve(
+	ctx context.Context, key, requestHash string, expiresAt time.Time,
+) (*usecase.IdempotencyRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	if e, ok := s.entries[key]; ok && now.Before(e.expiresAt) {
+		record := e.record
+		return &record, false, nil
+	}
+
+	s.entries[key] = &idempotencyEntry{record: usecase.IdempotencyRecord{RequestHash: requestHash}, expiresAt: expiresAt}
+	return nil, true, nil
+}
+
+func (s *MemoryIdempotencyStore) Complete(ctx context.Context, key string, record usecase.IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.entries[key]; ok {
+		e.record = record
+	}
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// A completed key stays until it expires
+	if e, ok := s.entries[key]; ok && e.record.StatusCode == 0 {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// sweep drops expired keys, at most once a minute
+func (s *MemoryIdempotencyStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+
+	for key, e := range s.entries {
+		if !now.Before(e.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
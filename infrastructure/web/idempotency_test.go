@@ -0,0 +1,135 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	store := NewMemoryIdempotencyStore()
+	idempotency := NewIdempotency(store, time.Hour, 1024)
+
+	calls := 0
+	status := http.StatusCreated
+	handler := idempotency.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(`{"vocabularyNo":1}`))
+	}))
+
+	send := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/vocabularies", strings.NewReader(body))
+		req.RemoteAddr = "192.0.2.1:1234"
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// The retry replays the first response
+	first := send("key-1", `{"title":"a"}`)
+	if first.Code != http.StatusCreated || first.Header().Get("Idempotent-Replayed") != "" {
+		t.Fatalf("first: status = %d, Idempotent-Replayed = %q", first.Code, first.Header().Get("Idempotent-Replayed"))
+	}
+
+	retry := send("key-1", `{"title":"a"}`)
+	if retry.Code != http.StatusCreated {
+		t.Fatalf("retry: status = %d, want %d", retry.Code, http.StatusCreated)
+	}
+	if got := retry.Header().Get("Idempotent-Replayed"); got != "true" {
+		t.Errorf("retry: Idempotent-Replayed = %q, want true", got)
+	}
+	if got := retry.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("retry: Content-Type = %q, want application/json", got)
+	}
+	if got := retry.Body.String(); got != `{"vocabularyNo":1}` {
+		t.Errorf("retry: body = %s", got)
+	}
+	if calls != 1 {
+		t.Errorf("handler calls = %d, want 1", calls)
+	}
+
+	// The same key with a different body is rejected
+	if rec := send("key-1", `{"title":"b"}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("different body: status = %d, want %d", rec.Code, http.StatusUnprocessableEntity)
+	}
+
+	// Requests without a key are not recorded
+	send("", `{"title":"a"}`)
+	send("", `{"title":"a"}`)
+	if calls != 3 {
+		t.Errorf("handler calls = %d, want 3", calls)
+	}
+
+	// A server error releases the key so that the client can retry
+	status = http.StatusInternalServerError
+	if rec := send("key-2", `{"title":"c"}`); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("server error: status = %d", rec.Code)
+	}
+	status = http.StatusCreated
+	if rec := send("key-2", `{"title":"c"}`); rec.Code != http.StatusCreated || rec.Header().Get("Idempotent-Replayed") != "" {
+		t.Errorf("retry after server error: status = %d, Idempotent-Replayed = %q", rec.Code, rec.Header().Get("Idempotent-Replayed"))
+	}
+	if calls != 5 {
+		t.Errorf("handler calls = %d, want 5", calls)
+	}
+
+	// An expired key is handled as a new request
+	store.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if rec := send("key-1", `{"title":"b"}`); rec.Code != http.StatusCreated || rec.Header().Get("Idempotent-Replayed") != "" {
+		t.Errorf("expired key: status = %d, Idempotent-Replayed = %q", rec.Code, rec.Header().Get("Idempotent-Replayed"))
+	}
+}
+
+func TestIdempotencyMiddlewareInProgress(t *testing.T) {
+	store := NewMemoryIdempotencyStore()
+	idempotency := NewIdempotency(store, time.Hour, 1024)
+
+	var retry *httptest.ResponseRecorder
+	var handler http.Handler
+	handler = idempotency.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The retry arrives while the first request is still being handled
+		if retry == nil {
+			retry = httptest.NewRecorder()
+			handler.ServeHTTP(retry, newIdempotentRequest("key", "{}"))
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest("key", "{}"))
+	if retry.Code != http.StatusConflict {
+		t.Errorf("status = %d, want %d", retry.Code, http.StatusConflict)
+	}
+}
+
+func TestIdempotencyMiddlewareScopedToClient(t *testing.T) {
+	idempotency := NewIdempotency(NewMemoryIdempotencyStore(), time.Hour, 1024)
+	handler := idempotency.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	for _, keyID := range []int64{1, 2} {
+		req := newIdempotentRequest("key", "{}")
+		req = req.WithContext(usecase.WithAPIKey(req.Context(), &domain.APIKey{KeyID: keyID}))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if got := rec.Header().Get("Idempotent-Replayed"); got != "" {
+			t.Errorf("key %d: Idempotent-Replayed = %q, want none", keyID, got)
+		}
+	}
+}
+
+func newIdempotentRequest(key, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/vocabularies", strings.NewReader(body))
+	req.Header.Set("Idempotency-Key", key)
+	return req
+}
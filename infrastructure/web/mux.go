@@ -1,29 +1,197 @@
 package web
 
 import (
+	"encoding/json"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/takumi616/golang-backend-sample/domain"
+	"github.com/takumi616/golang-backend-sample/infrastructure/web/openapi"
 	"github.com/takumi616/golang-backend-sample/interface/controller"
+	"github.com/takumi616/golang-backend-sample/interface/controller/response"
 )
 
 type ServeMux struct {
 	VocabularyController *controller.VocabularyController
+	WebhookController    *controller.WebhookController
+	APIKeyController     *controller.APIKeyController
+
+	// Default time limit of a handler
+	HandlerTimeout time.Duration
 }
 
-func NewServeMux(vocabularyController *controller.VocabularyController) *ServeMux {
+// Cache-Control policies of the routes
+const (
+	// Clients may keep the response but must revalidate it with its ETag
+	cacheRevalidate = "no-cache"
+
+	// The response must not be kept at all
+	cacheNone = "no-store"
+
+	// The response changes only with a new release
+	cacheStatic = "public, max-age=300"
+)
+
+type route struct {
+	Pattern string
+	Handler http.HandlerFunc
+
+	// Overrides HandlerTimeout when set. A negative value disables the time limit.
+	Timeout time.Duration
+
+	// Cache-Control header of the responses
+	CacheControl string
+
+	// Permission the request needs from its API key or the anonymous scopes. The route is public when empty.
+	Scope domain.Scope
+}
+
+func NewServeMux(
+	vocabularyController *controller.VocabularyController, webhookController *controller.WebhookController,
+	apiKeyController *controller.APIKeyController, handlerTimeout time.Duration,
+) *ServeMux {
 	return &ServeMux{
 		VocabularyController: vocabularyController,
+		WebhookController:    webhookController,
+		APIKeyController:     apiKeyController,
+		HandlerTimeout:       handlerTimeout,
+	}
+}
+
+// routes lists every registered route. Each of them must be documented in openapi/openapi.json.
+func (s *ServeMux) routes() []route {
+	return []route{
+		{
+			Pattern: "POST /api/vocabularies", Handler: s.VocabularyController.AddVocabulary,
+			CacheControl: cacheNone, Scope: domain.ScopeVocabWrite,
+		},
+		{
+			Pattern: "GET /api/vocabularies/{vocabularyNo}", Handler: s.VocabularyController.FetchVocabularyByNo,
+			CacheControl: cacheRevalidate, Scope: domain.ScopeVocabRead,
+		},
+		{
+			Pattern: "GET /api/vocabularies", Handler: s.VocabularyController.FetchVocabularyList,
+			CacheControl: cacheRevalidate, Scope: domain.ScopeVocabRead,
+		},
+		{
+			Pattern: "PUT /api/vocabularies/{vocabularyNo}", Handler: s.VocabularyController.UpdateVocabulary,
+			CacheControl: cacheNone, Scope: domain.ScopeVocabWrite,
+		},
+		{
+			Pattern: "DELETE /api/vocabularies/{vocabularyNo}", Handler: s.VocabularyController.DeleteVocabulary,
+			CacheControl: cacheNone, Scope: domain.ScopeVocabWrite,
+		},
+		{
+			Pattern: "POST /api/vocabularies:batch", Handler: s.VocabularyController.ApplyBatch,
+			CacheControl: cacheNone, Scope: domain.ScopeVocabWrite,
+		},
+
+		// The event stream stays open until the client disconnects
+		{
+			Pattern: "GET /api/vocabularies/events", Handler: s.VocabularyController.StreamVocabularyEvents,
+			Timeout: -1, CacheControl: cacheNone, Scope: domain.ScopeVocabRead,
+		},
+
+		// Webhook subscriptions and their delivery log
+		{
+			Pattern: "POST /api/webhooks", Handler: s.WebhookController.AddWebhook,
+			CacheControl: cacheNone, Scope: domain.ScopeWebhookWrite,
+		},
+		{
+			Pattern: "GET /api/webhooks", Handler: s.WebhookController.FetchWebhookList,
+			CacheControl: cacheNone, Scope: domain.ScopeWebhookRead,
+		},
+		{
+			Pattern: "GET /api/webhooks/{webhookID}", Handler: s.WebhookController.FetchWebhookByID,
+			CacheControl: cacheNone, Scope: domain.ScopeWebhookRead,
+		},
+		{
+			Pattern: "DELETE /api/webhooks/{webhookID}", Handler: s.WebhookController.DeleteWebhook,
+			CacheControl: cacheNone, Scope: domain.ScopeWebhookWrite,
+		},
+		{
+			Pattern: "GET /api/webhooks/{webhookID}/deliveries", Handler: s.WebhookController.FetchDeliveryList,
+			CacheControl: cacheNone, Scope: domain.ScopeWebhookRead,
+		},
+
+		// API keys of the machine clients
+		{
+			Pattern: "POST /api/keys", Handler: s.APIKeyController.CreateAPIKey,
+			CacheControl: cacheNone, Scope: domain.ScopeAPIKeyAdmin,
+		},
+		{
+			Pattern: "GET /api/keys", Handler: s.APIKeyController.FetchAPIKeyList,
+			CacheControl: cacheNone, Scope: domain.ScopeAPIKeyAdmin,
+		},
+		{
+			Pattern: "DELETE /api/keys/{keyID}", Handler: s.APIKeyController.RevokeAPIKey,
+			CacheControl: cacheNone, Scope: domain.ScopeAPIKeyAdmin,
+		},
+
+		// API documentation
+		{Pattern: "GET /openapi.json", Handler: openapi.ServeSpec, CacheControl: cacheStatic},
+		{Pattern: "GET /docs", Handler: openapi.ServeDocs, CacheControl: cacheStatic},
 	}
 }
 
 func (s *ServeMux) RegisterHandler() http.Handler {
 	mux := http.NewServeMux()
 
-	mux.HandleFunc("POST /api/vocabularies", s.VocabularyController.AddVocabulary)
-	mux.HandleFunc("GET /api/vocabularies/{vocabularyNo}", s.VocabularyController.FetchVocabularyByNo)
-	mux.HandleFunc("GET /api/vocabularies", s.VocabularyController.FetchVocabularyList)
-	mux.HandleFunc("PUT /api/vocabularies/{vocabularyNo}", s.VocabularyController.UpdateVocabulary)
-	mux.HandleFunc("DELETE /api/vocabularies/{vocabularyNo}", s.VocabularyController.DeleteVocabulary)
+	// Methods registered for each path, in registration order
+	var paths []string
+	methods := make(map[string][]string)
+
+	for _, route := range s.routes() {
+		// The scope is checked under the Cache-Control of the route, so that a rejection is not cached
+		route.Handler = withScope(route.Scope, route.Handler)
+		mux.Handle(route.Pattern, withCacheControl(route.CacheControl, s.withTimeout(route)))
+
+		method, path, _ := strings.Cut(route.Pattern, " ")
+		if _, ok := methods[path]; !ok {
+			paths = append(paths, path)
+		}
+		methods[path] = append(methods[path], method)
+	}
+
+	// Answer CORS preflight requests for every registered path
+	for _, path := range paths {
+		mux.HandleFunc("OPTIONS "+path, preflightHandler(methods[path]))
+	}
 
 	return mux
 }
+
+// withTimeout cancels the request context when the handler runs out of time,
+// so that database queries abort, and responds with 503
+func (s *ServeMux) withTimeout(route route) http.Handler {
+	timeout := s.HandlerTimeout
+	if route.Timeout != 0 {
+		timeout = route.Timeout
+	}
+
+	if timeout < 0 {
+		return route.Handler
+	}
+
+	body, _ := json.Marshal(response.ErrorRes{Message: "The request timed out. Please retry later."})
+	timeoutHandler := http.TimeoutHandler(route.Handler, timeout, string(body))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Used for the timeout response only; the handler sets its own headers otherwise
+		w.Header().Set("Content-Type", "application/json")
+		timeoutHandler.ServeHTTP(w, r)
+	})
+}
+
+// withCacheControl sets the Cache-Control header before the handler runs
+func withCacheControl(policy string, next http.Handler) http.Handler {
+	if policy == "" {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", policy)
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,98 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/infrastructure/web/openapi"
+)
+
+func TestRoutesAreDocumented(t *testing.T) {
+	var spec struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(openapi.Spec, &spec); err != nil {
+		t.Fatalf("openapi.json is not valid JSON: %v", err)
+	}
+
+	if !strings.HasPrefix(spec.OpenAPI, "3.1") {
+		t.Errorf("openapi = %q, want 3.1.x", spec.OpenAPI)
+	}
+
+	for _, route := range NewServeMux(nil, nil, nil, time.Second).routes() {
+		method, path, ok := strings.Cut(route.Pattern, " ")
+		if !ok {
+			t.Errorf("route %q has no method", route.Pattern)
+			continue
+		}
+
+		if _, ok := spec.Paths[path][strings.ToLower(method)]; !ok {
+			t.Errorf("route %q is missing from openapi.json", route.Pattern)
+		}
+	}
+}
+
+func TestServeSpec(t *testing.T) {
+	handler := NewServeMux(nil, nil, nil, time.Second).RegisterHandler()
+
+	for path, contentType := range map[string]string{
+		"/openapi.json": "application/json",
+		"/docs":         "text/html; charset=utf-8",
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("GET %s status = %d, want %d", path, rec.Code, http.StatusOK)
+		}
+		if got := rec.Header().Get("Content-Type"); got != contentType {
+			t.Errorf("GET %s Content-Type = %q, want %q", path, got, contentType)
+		}
+	}
+}
+
+func TestHandlerTimeout(t *testing.T) {
+	cancelled := make(chan error, 1)
+	slow := route{
+		Pattern: "GET /slow",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			// Blocks like a long database query until the context is cancelled
+			<-r.Context().Done()
+			cancelled <- r.Context().Err()
+		},
+	}
+
+	rec := httptest.NewRecorder()
+	NewServeMux(nil, nil, nil, 10*time.Millisecond).withTimeout(slow).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+	if err := <-cancelled; err != context.DeadlineExceeded {
+		t.Errorf("context error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+}
+
+func TestCacheControl(t *testing.T) {
+	for _, route := range NewServeMux(nil, nil, nil, time.Second).routes() {
+		if route.CacheControl == "" {
+			t.Errorf("route %q has no Cache-Control policy", route.Pattern)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	NewServeMux(nil, nil, nil, time.Second).RegisterHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if got := rec.Header().Get("Cache-Control"); got != cacheStatic {
+		t.Errorf("Cache-Control = %q, want %q", got, cacheStatic)
+	}
+}
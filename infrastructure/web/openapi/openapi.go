@@ -0,0 +1,32 @@
+package openapi
+
+import (
+	_ "embed"
+	"log/slog"
+	"net/http"
+)
+
+//go:embed openapi.json
+var Spec []byte
+
+//go:embed docs.html
+var docsPage []byte
+
+// ServeSpec writes the OpenAPI document
+func ServeSpec(w http.ResponseWriter, r *http.Request) {
+	write(w, r, "application/json", Spec)
+}
+
+// ServeDocs writes the Redoc page rendering the OpenAPI document
+func ServeDocs(w http.ResponseWriter, r *http.Request) {
+	write(w, r, "text/html; charset=utf-8", docsPage)
+}
+
+func write(w http.ResponseWriter, r *http.Request, contentType string, body []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := w.Write(body); err != nil {
+		slog.ErrorContext(r.Context(), "failed to write an response", "err", err)
+	}
+}
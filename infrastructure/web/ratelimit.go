@@ -0,0 +1,110 @@
+package web
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/interface/controller/helper"
+	"github.com/takumi616/golang-backend-sample/interface/controller/response"
+)
+
+// RateLimit is a token bucket refilled at Rate tokens per second up to Burst tokens
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+type RateLimitResult struct {
+	Allowed   bool
+	Remaining int
+
+	// Time until the next token is available
+	RetryAfter time.Duration
+
+	// Time until the bucket is full again
+	Reset time.Duration
+}
+
+// RateLimitStore keeps the token buckets.
+// Implementations shared between processes (e.g. Redis) can replace MemoryRateLimitStore.
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, limit RateLimit, now time.Time) (RateLimitResult, error)
+}
+
+type RateLimiter struct {
+	Store RateLimitStore
+	Read  RateLimit
+	Write RateLimit
+}
+
+func NewRateLimiter(store RateLimitStore, read, write RateLimit) *RateLimiter {
+	return &RateLimiter{
+		Store: store,
+		Read:  read,
+		Write: write,
+	}
+}
+
+// Middleware limits requests per client with separate buckets for read and write routes
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		limit, scope := l.Write, "write"
+		if isReadMethod(r.Method) {
+			limit, scope = l.Read, "read"
+		}
+
+		result, err := l.Store.Take(ctx, scope+":"+clientKey(r), limit, time.Now())
+		if err != nil {
+			// Fail open so that a broken store does not take the API down
+			slog.ErrorContext(ctx, "failed to check the rate limit", slog.String("error", err.Error()))
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(limit.Burst))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+		if !result.Allowed {
+			slog.WarnContext(ctx, "rate limit exceeded", slog.String("scope", scope), slog.String("path", r.URL.Path))
+			w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			helper.WriteResponse(
+				ctx, w, http.StatusTooManyRequests,
+				response.ErrorRes{Message: "Too many requests. Please retry after a while."},
+			)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// clientKey identifies the client by its verified API key, otherwise by its IP address.
+// An unverified token never picks the bucket, so that sending random tokens does not get fresh buckets.
+func clientKey(r *http.Request) string {
+	if key := usecase.APIKeyFrom(r.Context()); key != nil {
+		return "key:" + strconv.FormatInt(key.KeyID, 10)
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
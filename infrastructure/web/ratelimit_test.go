@@ -0,0 +1,103 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+)
+
+func TestRateLimiterMiddleware(t *testing.T) {
+	limiter := NewRateLimiter(
+		NewMemoryRateLimitStore(),
+		RateLimit{Rate: 1, Burst: 3},
+		RateLimit{Rate: 0.5, Burst: 2},
+	)
+	handler := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	// key is the verified API key of the request, or nil
+	send := func(method, remoteAddr string, key *domain.APIKey) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/vocabularies", nil)
+		req.RemoteAddr = remoteAddr
+		if key != nil {
+			req = req.WithContext(usecase.WithAPIKey(req.Context(), key))
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// The write bucket allows the burst and then rejects
+	for i, wantRemaining := range []string{"1", "0"} {
+		rec := send(http.MethodPost, "192.0.2.1:1234", nil)
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("write %d: status = %d, want %d", i, rec.Code, http.StatusNoContent)
+		}
+		if got := rec.Header().Get("RateLimit-Remaining"); got != wantRemaining {
+			t.Errorf("write %d: RateLimit-Remaining = %s, want %s", i, got, wantRemaining)
+		}
+	}
+
+	rec := send(http.MethodPost, "192.0.2.1:5678", nil)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want 2", got)
+	}
+	if got := rec.Header().Get("RateLimit-Limit"); got != "2" {
+		t.Errorf("RateLimit-Limit = %q, want 2", got)
+	}
+
+	// Reads have their own bucket
+	if rec := send(http.MethodGet, "192.0.2.1:1234", nil); rec.Code != http.StatusNoContent {
+		t.Errorf("read status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+
+	// Other clients are not affected
+	if rec := send(http.MethodPost, "192.0.2.2:1234", nil); rec.Code != http.StatusNoContent {
+		t.Errorf("other ip status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	if rec := send(http.MethodPost, "192.0.2.1:1234", &domain.APIKey{KeyID: 1}); rec.Code != http.StatusNoContent {
+		t.Errorf("api key status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+
+	// A token that is not verified does not get a bucket of its own
+	req := httptest.NewRequest(http.MethodPost, "/api/vocabularies", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	req.Header.Set("X-API-Key", "random")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("unverified token status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+}
+
+func TestMemoryRateLimitStoreRefill(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryRateLimitStore()
+	limit := RateLimit{Rate: 2, Burst: 1}
+	now := time.Now()
+
+	if result, _ := store.Take(ctx, "client", limit, now); !result.Allowed {
+		t.Fatal("first request was rejected")
+	}
+
+	result, _ := store.Take(ctx, "client", limit, now.Add(100*time.Millisecond))
+	if result.Allowed {
+		t.Fatal("request before the refill was allowed")
+	}
+	if got := result.RetryAfter.Round(time.Millisecond); got != 400*time.Millisecond {
+		t.Errorf("RetryAfter = %v, want 400ms", got)
+	}
+
+	if result, _ := store.Take(ctx, "client", limit, now.Add(600*time.Millisecond)); !result.Allowed {
+		t.Error("request after the refill was rejected")
+	}
+}
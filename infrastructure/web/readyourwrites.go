@@ -0,0 +1,98 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/infrastructure/db"
+)
+
+// ReadYourWritesCookie carries the time, in Unix milliseconds, until which the reads of a client go to the primary
+const ReadYourWritesCookie = "read_your_writes_until"
+
+// ReadYourWrites sends the database reads of a request to the primary after the request writes, and for Window
+// after a write of an earlier request of the same client, unless Window is zero, so that a client never misses its own changes because
+// of replication lag. The earlier write is carried by the ReadYourWritesCookie cookie. A client forging it can only
+// send its own reads to the primary.
+type ReadYourWrites struct {
+	Window time.Duration
+}
+
+func NewReadYourWrites(window time.Duration) *ReadYourWrites {
+	return &ReadYourWrites{
+		Window: window,
+	}
+}
+
+func (m *ReadYourWrites) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := db.WithReadYourWritesUntil(r.Context(), m.pinnedUntil(r))
+		writer := &pinWriter{ResponseWriter: w, r: r.WithContext(ctx), window: m.Window}
+		next.ServeHTTP(writer, writer.r)
+
+		// A response without a body is sent after the handler returns
+		writer.setCookie()
+	})
+}
+
+// pinnedUntil reads the cookie, which never pins for more than Window
+func (m *ReadYourWrites) pinnedUntil(r *http.Request) time.Time {
+	cookie, err := r.Cookie(ReadYourWritesCookie)
+	if err != nil {
+		return time.Time{}
+	}
+
+	millis, err := strconv.ParseInt(cookie.Value, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+
+	until := time.UnixMilli(millis)
+	if latest := time.Now().Add(m.Window); until.After(latest) {
+		return latest
+	}
+	return until
+}
+
+// pinWriter sets the cookie with the header of a response to a request that wrote
+type pinWriter struct {
+	http.ResponseWriter
+	r           *http.Request
+	window      time.Duration
+	wroteHeader bool
+}
+
+func (w *pinWriter) WriteHeader(statusCode int) {
+	w.setCookie()
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *pinWriter) Write(b []byte) (int, error) {
+	w.setCookie()
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *pinWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *pinWriter) setCookie() {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	if w.window <= 0 || !db.Written(w.r.Context()) {
+		return
+	}
+	http.SetCookie(w.ResponseWriter, &http.Cookie{
+		Name:     ReadYourWritesCookie,
+		Value:    strconv.FormatInt(time.Now().Add(w.window).UnixMilli(), 10),
+		Path:     "/",
+		MaxAge:   int((w.window + time.Second - 1) / time.Second),
+		Secure:   w.r.TLS != nil,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
@@ -0,0 +1,85 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/infrastructure/db"
+)
+
+func TestReadYourWrites(t *testing.T) {
+	router := &db.Router{}
+
+	// The handler writes on POST and reports whether its reads were pinned
+	handler := NewReadYourWrites(5 * time.Second).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pinned := db.Pinned(r.Context())
+		if r.Method == http.MethodPost {
+			router.Writer(r.Context())
+		}
+		w.Header().Set("X-Pinned", strconv.FormatBool(pinned))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	millis := func(d time.Duration) string {
+		return strconv.FormatInt(time.Now().Add(d).UnixMilli(), 10)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		cookie     string
+		wantPinned bool
+		wantCookie bool
+	}{
+		{name: "read", method: http.MethodGet},
+		{name: "write", method: http.MethodPost, wantCookie: true},
+		{name: "read after a write", method: http.MethodGet, cookie: millis(3 * time.Second), wantPinned: true},
+		{name: "read after the window", method: http.MethodGet, cookie: millis(-time.Second)},
+		{name: "invalid cookie", method: http.MethodGet, cookie: "soon"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/vocabularies", nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: ReadYourWritesCookie, Value: tt.cookie})
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if got := rec.Header().Get("X-Pinned"); got != strconv.FormatBool(tt.wantPinned) {
+				t.Errorf("pinned = %s, want %t", got, tt.wantPinned)
+			}
+
+			cookies := rec.Result().Cookies()
+			if !tt.wantCookie {
+				if len(cookies) != 0 {
+					t.Errorf("cookies = %v, want none", cookies)
+				}
+				return
+			}
+			if len(cookies) != 1 || cookies[0].Name != ReadYourWritesCookie || cookies[0].MaxAge != 5 || !cookies[0].HttpOnly {
+				t.Fatalf("cookies = %v, want %s for 5s", cookies, ReadYourWritesCookie)
+			}
+			until, err := strconv.ParseInt(cookies[0].Value, 10, 64)
+			if err != nil || time.Until(time.UnixMilli(until)) < 4*time.Second {
+				t.Errorf("cookie value = %s, want 5s from now", cookies[0].Value)
+			}
+		})
+	}
+}
+
+func TestReadYourWritesCapsTheCookie(t *testing.T) {
+	m := NewReadYourWrites(5 * time.Second)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/vocabularies", nil)
+	req.AddCookie(&http.Cookie{Name: ReadYourWritesCookie, Value: strconv.FormatInt(time.Now().Add(time.Hour).UnixMilli(), 10)})
+
+	// A forged cookie pins for Window at most
+	if d := time.Until(m.pinnedUntil(req)); d > 5*time.Second {
+		t.Errorf("pinned for %v, want 5s at most", d)
+	}
+}
@@ -13,15 +13,39 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
+type ServerTimeouts struct {
+	ReadHeader time.Duration
+	Read       time.Duration
+	Write      time.Duration
+	Idle       time.Duration
+
+	// Grace period for in-flight requests on shutdown
+	Shutdown time.Duration
+}
+
 type Server struct {
-	Port    string
-	Handler http.Handler
+	Port     string
+	Handler  http.Handler
+	Timeouts ServerTimeouts
+
+	// Serves HTTPS when set, otherwise plain HTTP
+	TLS *TLSReloader
+
+	// Called when the server starts shutting down, to end the long-lived responses
+	// that the shutdown would otherwise wait for
+	OnShutdown []func()
+
+	// Run alongside the server in its errgroup. Their context is cancelled when the server starts
+	// shutting down, and Run waits for them to drain before it returns.
+	Background []func(ctx context.Context) error
 }
 
-func NewServer(port string, handler http.Handler) *Server {
+func NewServer(port string, handler http.Handler, timeouts ServerTimeouts, tlsReloader *TLSReloader) *Server {
 	return &Server{
-		Port:    port,
-		Handler: handler,
+		Port:     port,
+		Handler:  handler,
+		Timeouts: timeouts,
+		TLS:      tlsReloader,
 	}
 }
 
@@ -37,14 +61,37 @@ func (s *Server) Run(ctx context.Context) error {
 		return err
 	}
 
+	// Enable HTTP/2, which is negotiated over TLS
+	var protocols http.Protocols
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(true)
+
 	server := &http.Server{
-		Handler: s.Handler,
+		Handler:           s.Handler,
+		Protocols:         &protocols,
+		ReadHeaderTimeout: s.Timeouts.ReadHeader,
+		ReadTimeout:       s.Timeouts.Read,
+		WriteTimeout:      s.Timeouts.Write,
+		IdleTimeout:       s.Timeouts.Idle,
+	}
+	if s.TLS != nil {
+		server.TLSConfig = s.TLS.TLSConfig()
+	}
+	for _, f := range s.OnShutdown {
+		server.RegisterOnShutdown(f)
 	}
 
 	// Start the http server
 	eg, ctx := errgroup.WithContext(ctx)
 	eg.Go(func() error {
-		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+		var err error
+		if s.TLS != nil {
+			err = server.ServeTLS(listener, "", "")
+		} else {
+			err = server.Serve(listener)
+		}
+
+		if err != nil && err != http.ErrServerClosed {
 			slog.ErrorContext(ctx, "failed to serve http", "error", err)
 			return err
 		}
@@ -52,14 +99,28 @@ func (s *Server) Run(ctx context.Context) error {
 		return nil
 	})
 
+	// Reload the certificate while the server is running
+	if s.TLS != nil {
+		eg.Go(func() error {
+			return s.TLS.Watch(ctx)
+		})
+	}
+
+	// Start the background workers
+	for _, task := range s.Background {
+		eg.Go(func() error {
+			return task(ctx)
+		})
+	}
+
 	// Shutdown the http server
 	<-ctx.Done()
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.Timeouts.Shutdown)
 	defer cancel()
 	if err = server.Shutdown(shutdownCtx); err != nil {
 		slog.ErrorContext(ctx, "failed to shut down the http server", "error", err)
 	}
 
-	// Wait for returned value from the goroutine
+	// Wait for returned value from the goroutines
 	return eg.Wait()
 }
@@ -0,0 +1,173 @@
+package web
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// TLSReloader serves the certificate and key files and reloads them on SIGHUP or when the files change.
+// When ClientCAFile is set, clients must present a certificate signed by one of its CAs (mTLS).
+type TLSReloader struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+
+	// How often the files are checked for changes
+	Interval time.Duration
+
+	mu       sync.RWMutex
+	config   *tls.Config
+	modTimes map[string]time.Time
+}
+
+func NewTLSReloader(certFile, keyFile, clientCAFile string) (*TLSReloader, error) {
+	r := &TLSReloader{
+		CertFile:     certFile,
+		KeyFile:      keyFile,
+		ClientCAFile: clientCAFile,
+		Interval:     30 * time.Second,
+	}
+
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Reload loads the files again. The current certificate stays in use if loading fails.
+func (r *TLSReloader) Reload() error {
+	modTimes, err := r.statFiles()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.CertFile, r.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load the certificate: %w", err)
+	}
+
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		// Offer HTTP/2 first through ALPN
+		NextProtos: []string{"h2", "http/1.1"},
+	}
+
+	if r.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read the client CA bundle: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return errors.New("no certificate found in the client CA bundle")
+		}
+
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	r.mu.Lock()
+	r.config = config
+	r.modTimes = modTimes
+	r.mu.Unlock()
+
+	return nil
+}
+
+// TLSConfig returns the configuration for http.Server.
+// Every handshake picks up the latest loaded files through GetConfigForClient.
+func (r *TLSReloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: []string{"h2", "http/1.1"},
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			return &r.config.Certificates[0], nil
+		},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			return r.config, nil
+		},
+	}
+}
+
+// Watch reloads the files on SIGHUP or when their modification time changes, until ctx is done
+func (r *TLSReloader) Watch(ctx context.Context) error {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-hup:
+			r.reload(ctx, "SIGHUP")
+		case <-ticker.C:
+			if r.changed() {
+				r.reload(ctx, "file change")
+			}
+		}
+	}
+}
+
+func (r *TLSReloader) reload(ctx context.Context, trigger string) {
+	if err := r.Reload(); err != nil {
+		slog.ErrorContext(ctx, "failed to reload the TLS certificate", slog.String("trigger", trigger), slog.String("error", err.Error()))
+		return
+	}
+
+	slog.InfoContext(ctx, "TLS certificate was reloaded", slog.String("trigger", trigger))
+}
+
+func (r *TLSReloader) changed() bool {
+	modTimes, err := r.statFiles()
+	if err != nil {
+		// The files may be in the middle of being replaced; try again at the next tick
+		return false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for file, modTime := range modTimes {
+		if !modTime.Equal(r.modTimes[file]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *TLSReloader) statFiles() (map[string]time.Time, error) {
+	modTimes := make(map[string]time.Time)
+	for _, file := range []string{r.CertFile, r.KeyFile, r.ClientCAFile} {
+		if file == "" {
+			continue
+		}
+
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[file] = info.ModTime()
+	}
+
+	return modTimes, nil
+}
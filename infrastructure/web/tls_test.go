@@ -0,0 +1,213 @@
+package web
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// newTestCert creates a certificate signed by parent, or a self-signed CA when parent is nil
+func newTestCert(t *testing.T, serial int64, parent *testCert) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	signerCert, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		signerCert, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signerCert, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// handshake connects to a TLS listener served with config and returns the connection state
+func handshake(t *testing.T, config *tls.Config, clientConfig *tls.Config) (tls.ConnectionState, error) {
+	t.Helper()
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if err := conn.(*tls.Conn).Handshake(); err == nil {
+			conn.Write([]byte{1})
+		}
+	}()
+
+	conn, err := tls.Dial("tcp", listener.Addr().String(), clientConfig)
+	if err != nil {
+		return tls.ConnectionState{}, err
+	}
+	defer conn.Close()
+
+	// With TLS 1.3 a rejected client certificate is reported on the first read
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := conn.Read(make([]byte, 1)); err != nil {
+		return tls.ConnectionState{}, err
+	}
+
+	return conn.ConnectionState(), nil
+}
+
+func TestTLSReloaderMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCert(t, 1, nil)
+	server := newTestCert(t, 2, ca)
+	client := newTestCert(t, 3, ca)
+
+	certFile, keyFile, caFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem"), filepath.Join(dir, "ca.pem")
+	writeFile(t, certFile, server.certPEM)
+	writeFile(t, keyFile, server.keyPEM)
+	writeFile(t, caFile, ca.certPEM)
+
+	reloader, err := NewTLSReloader(certFile, keyFile, caFile)
+	if err != nil {
+		t.Fatalf("NewTLSReloader returned an error: %v", err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	// A client without a certificate is rejected
+	_, err = handshake(t, reloader.TLSConfig(), &tls.Config{RootCAs: roots, ServerName: "localhost"})
+	if err == nil {
+		t.Error("handshake without a client certificate succeeded")
+	}
+
+	// A client with a certificate signed by the CA negotiates HTTP/2
+	clientCert := tls.Certificate{Certificate: [][]byte{client.cert.Raw}, PrivateKey: client.key}
+	state, err := handshake(t, reloader.TLSConfig(), &tls.Config{
+		RootCAs:      roots,
+		ServerName:   "localhost",
+		Certificates: []tls.Certificate{clientCert},
+		NextProtos:   []string{"h2", "http/1.1"},
+	})
+	if err != nil {
+		t.Fatalf("handshake with a client certificate failed: %v", err)
+	}
+	if state.NegotiatedProtocol != "h2" {
+		t.Errorf("NegotiatedProtocol = %q, want h2", state.NegotiatedProtocol)
+	}
+}
+
+func TestTLSReloaderReload(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCert(t, 1, nil)
+	first := newTestCert(t, 10, ca)
+	second := newTestCert(t, 20, ca)
+
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	writeFile(t, certFile, first.certPEM)
+	writeFile(t, keyFile, first.keyPEM)
+
+	reloader, err := NewTLSReloader(certFile, keyFile, "")
+	if err != nil {
+		t.Fatalf("NewTLSReloader returned an error: %v", err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	clientConfig := &tls.Config{RootCAs: roots, ServerName: "localhost"}
+
+	servedSerial := func() int64 {
+		t.Helper()
+		state, err := handshake(t, reloader.TLSConfig(), clientConfig)
+		if err != nil {
+			t.Fatalf("handshake failed: %v", err)
+		}
+		return state.PeerCertificates[0].SerialNumber.Int64()
+	}
+
+	if got := servedSerial(); got != 10 {
+		t.Fatalf("served serial = %d, want 10", got)
+	}
+
+	// Replace the files; the change is detected by the modification time
+	writeFile(t, certFile, second.certPEM)
+	writeFile(t, keyFile, second.keyPEM)
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(certFile, later, later)
+
+	if !reloader.changed() {
+		t.Error("changed() = false after the files were replaced")
+	}
+	if err := reloader.Reload(); err != nil {
+		t.Fatalf("Reload returned an error: %v", err)
+	}
+	if got := servedSerial(); got != 20 {
+		t.Errorf("served serial after Reload = %d, want 20", got)
+	}
+
+	// A broken file keeps the current certificate
+	writeFile(t, keyFile, []byte("broken"))
+	if err := reloader.Reload(); err == nil {
+		t.Error("Reload with a broken key returned no error")
+	}
+	if got := servedSerial(); got != 20 {
+		t.Errorf("served serial after a failed Reload = %d, want 20", got)
+	}
+}
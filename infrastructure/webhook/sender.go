@@ -0,0 +1,194 @@
+// Package webhook posts the vocabulary events to the registered webhooks.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+)
+
+// Payload is the JSON body posted to a webhook
+type Payload struct {
+	// ID of the delivery. It is the same in every attempt, so receivers can drop duplicates.
+	DeliveryID int64  `json:"delivery_id"`
+	Type       string `json:"type"`
+	OccurredAt string `json:"occurred_at"`
+	Actor      string `json:"actor"`
+
+	Data PayloadData `json:"data"`
+}
+
+type PayloadData struct {
+	VocabularyNo int64 `json:"vocabulary_no"`
+
+	// Content written by a create or an update. Omitted for a delete.
+	Title    string `json:"title,omitempty"`
+	Meaning  string `json:"meaning,omitempty"`
+	Sentence string `json:"sentence,omitempty"`
+}
+
+// errDeliveryFailed is recorded for a delivery that got no response. The cause is only logged, so that
+// the delivery log does not tell what the server can reach.
+var errDeliveryFailed = errors.New("the webhook could not be reached")
+
+// Sender posts the deliveries signed with HMAC-SHA256.
+// The Webhook-Signature header is "t=<unix time>,v1=<hex signature>", and the signature covers
+// "<unix time>.<body>" so that a receiver can reject old requests replayed to it.
+type Sender struct {
+	Client *http.Client
+
+	// Value of the User-Agent header
+	UserAgent string
+}
+
+// NewSender returns a sender that refuses to connect to private, loopback, link-local and other internal
+// addresses, unless they are in allowed. The address is checked when connecting, after the name is resolved,
+// so that a name cannot be pointed elsewhere after a check.
+func NewSender(timeout time.Duration, userAgent string, allowed []netip.Prefix) *Sender {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			return checkAddress(address, allowed)
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+
+	// A proxy would be the only address checked
+	transport.Proxy = nil
+
+	return &Sender{
+		Client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+
+			// A redirect would send the signed payload to another endpoint
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		UserAgent: userAgent,
+	}
+}
+
+func (s *Sender) Send(ctx context.Context, delivery *usecase.WebhookDelivery) (int, error) {
+	body, err := json.Marshal(ToPayload(delivery))
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", s.UserAgent)
+	req.Header.Set("Webhook-ID", strconv.FormatInt(delivery.DeliveryID, 10))
+	req.Header.Set("Webhook-Event", string(delivery.Event.Type))
+	req.Header.Set("Webhook-Signature", "t="+timestamp+",v1="+Sign(delivery.Webhook.Secret, timestamp, body))
+
+	res, err := s.Client.Do(req)
+	if err != nil {
+		slog.WarnContext(
+			ctx, "failed to send the webhook delivery",
+			slog.Int64("deliveryID", delivery.DeliveryID), slog.String("error", err.Error()),
+		)
+		return 0, errDeliveryFailed
+	}
+	defer res.Body.Close()
+
+	// Drain a little of the body so that the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(res.Body, 4096))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("the webhook responded with status %d", res.StatusCode)
+	}
+	return res.StatusCode, nil
+}
+
+// Special-purpose ranges that netip does not classify, and IPv6 forms that embed an IPv4 address
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "this network"
+	netip.MustParsePrefix("100.64.0.0/10"),  // shared address space of carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),   // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),  // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),    // reserved, and the limited broadcast address
+	netip.MustParsePrefix("::/96"),          // IPv4-compatible addresses
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64
+	netip.MustParsePrefix("64:ff9b:1::/48"), // local-use NAT64
+	netip.MustParsePrefix("2002::/16"),      // 6to4
+	netip.MustParsePrefix("2001::/32"),      // Teredo
+}
+
+// checkAddress rejects an internal address that is not allowed
+func checkAddress(address string, allowed []netip.Prefix) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+
+	// Check an IPv4-mapped IPv6 address as the IPv4 address it maps to
+	addr := addrPort.Addr().Unmap().WithZone("")
+	for _, prefix := range allowed {
+		if prefix.Contains(addr) {
+			return nil
+		}
+	}
+
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified() {
+		return fmt.Errorf("the address %s is not allowed", addr)
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return fmt.Errorf("the address %s is not allowed", addr)
+		}
+	}
+	return nil
+}
+
+// Sign returns the hex HMAC-SHA256 of "<timestamp>.<body>" with the secret
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ToPayload converts a delivery into the posted body
+func ToPayload(delivery *usecase.WebhookDelivery) *Payload {
+	event := delivery.Event
+	payload := &Payload{
+		DeliveryID: delivery.DeliveryID,
+		Type:       string(event.Type),
+		OccurredAt: event.OccurredAt.UTC().Format(time.RFC3339Nano),
+		Actor:      event.Actor,
+		Data:       PayloadData{VocabularyNo: event.VocabularyNo},
+	}
+
+	if event.Vocabulary != nil {
+		payload.Data.Title = event.Vocabulary.Title
+		payload.Data.Meaning = event.Vocabulary.Meaning
+		payload.Data.Sentence = event.Vocabulary.Sentence
+	}
+	return payload
+}
@@ -0,0 +1,201 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+)
+
+// loopback lets the tests reach the httptest servers
+var loopback = []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}
+
+func newDelivery(url string) *usecase.WebhookDelivery {
+	return &usecase.WebhookDelivery{
+		DeliveryID: 7,
+		Event: usecase.VocabularyEvent{
+			Type:         usecase.VocabularyCreated,
+			VocabularyNo: 1,
+			Vocabulary:   &domain.Vocabulary{VocabularyNo: 1, Title: "apple", Meaning: "fruit", Sentence: "I ate an apple."},
+			Actor:        "alice",
+			OccurredAt:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		Webhook: &domain.Webhook{URL: url, Secret: "0123456789abcdef"},
+	}
+}
+
+func TestSenderSend(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := r.Header.Get("Webhook-ID"); got != "7" {
+			t.Errorf("Webhook-ID = %q, want 7", got)
+		}
+		if got := r.Header.Get("Webhook-Event"); got != "vocabulary.created" {
+			t.Errorf("Webhook-Event = %q, want vocabulary.created", got)
+		}
+		if got := r.Header.Get("User-Agent"); got != "test-agent" {
+			t.Errorf("User-Agent = %q, want test-agent", got)
+		}
+
+		// Verify the signature the way a receiver does
+		timestamp, signature, ok := strings.Cut(r.Header.Get("Webhook-Signature"), ",v1=")
+		timestamp, found := strings.CutPrefix(timestamp, "t=")
+		if !ok || !found || signature != Sign("0123456789abcdef", timestamp, body) {
+			t.Errorf("Webhook-Signature = %q does not match the body", r.Header.Get("Webhook-Signature"))
+		}
+
+		want := `{"delivery_id":7,"type":"vocabulary.created","occurred_at":"2024-01-02T03:04:05Z","actor":"alice",` +
+			`"data":{"vocabulary_no":1,"title":"apple","meaning":"fruit","sentence":"I ate an apple."}}`
+		if string(body) != want {
+			t.Errorf("body = %s, want %s", body, want)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	statusCode, err := NewSender(time.Second, "test-agent", loopback).Send(context.Background(), newDelivery(server.URL))
+	if err != nil || statusCode != http.StatusNoContent {
+		t.Errorf("Send() = %d, %v, want 204, nil", statusCode, err)
+	}
+}
+
+func TestSenderSendFailure(t *testing.T) {
+	tests := []struct {
+		name           string
+		handler        http.HandlerFunc
+		wantStatusCode int
+	}{
+		{
+			name: "server error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+		{
+			// A redirect is not followed
+			name: "redirect",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, "/elsewhere", http.StatusFound)
+			},
+			wantStatusCode: http.StatusFound,
+		},
+		{
+			name: "timeout",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(time.Second)
+			},
+			wantStatusCode: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			statusCode, err := NewSender(100*time.Millisecond, "test-agent", loopback).Send(context.Background(), newDelivery(server.URL))
+			if err == nil || statusCode != tt.wantStatusCode {
+				t.Errorf("Send() = %d, %v, want %d and an error", statusCode, err, tt.wantStatusCode)
+			}
+		})
+	}
+}
+
+func TestSenderRejectsInternalAddresses(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	// The error does not tell why the connection failed
+	statusCode, err := NewSender(time.Second, "test-agent", nil).Send(context.Background(), newDelivery(server.URL))
+	if !errors.Is(err, errDeliveryFailed) || err.Error() != errDeliveryFailed.Error() || statusCode != 0 {
+		t.Errorf("Send() = %d, %v, want 0, %v", statusCode, err, errDeliveryFailed)
+	}
+	if called {
+		t.Error("the loopback server was called")
+	}
+}
+
+func TestCheckAddress(t *testing.T) {
+	allowed := []netip.Prefix{netip.MustParsePrefix("10.1.0.0/16")}
+
+	tests := []struct {
+		address string
+		wantErr bool
+	}{
+		{"93.184.215.14:443", false},
+		{"[2606:2800:21f:cb07:6820:80da:af6b:8b2c]:443", false},
+		{"127.0.0.1:80", true},
+		{"[::1]:80", true},
+		{"[::ffff:127.0.0.1]:80", true},
+		{"10.0.0.1:80", true},
+		{"172.16.0.1:80", true},
+		{"192.168.1.1:80", true},
+		{"[fd00::1]:80", true},
+		{"169.254.169.254:80", true},
+		{"[fe80::1]:80", true},
+		{"0.0.0.0:80", true},
+		{"0.1.2.3:80", true},
+		{"100.64.0.1:80", true},
+		{"100.127.255.254:80", true},
+		{"100.128.0.1:80", false},
+		{"192.0.0.8:80", true},
+		{"198.18.0.1:80", true},
+		{"240.0.0.1:80", true},
+		{"255.255.255.255:80", true},
+		{"224.0.0.1:80", true},
+		{"[::ffff:10.0.0.1]:80", true},
+		{"[::ffff:192.168.1.1]:80", true},
+		{"[::ffff:169.254.169.254]:80", true},
+		{"[::ffff:100.64.0.1]:80", true},
+		{"[::ffff:0.0.0.0]:80", true},
+		{"[::ffff:93.184.215.14]:443", false},
+		{"[::127.0.0.1]:80", true},
+		{"[64:ff9b::a9fe:a9fe]:80", true},
+		{"[2002:a9fe:a9fe::1]:80", true},
+		{"[fe80::1%eth0]:80", true},
+		{"10.1.2.3:80", false},
+		{"[::ffff:10.1.2.3]:80", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			if err := checkAddress(tt.address, allowed); (err != nil) != tt.wantErr {
+				t.Errorf("checkAddress(%s) = %v, want an error: %t", tt.address, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestToPayloadDelete(t *testing.T) {
+	delivery := newDelivery("")
+	delivery.Event.Type = usecase.VocabularyDeleted
+	delivery.Event.Vocabulary = nil
+
+	body, err := json.Marshal(ToPayload(delivery))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The content is omitted for a delete
+	want := `{"delivery_id":7,"type":"vocabulary.deleted","occurred_at":"2024-01-02T03:04:05Z","actor":"alice","data":{"vocabulary_no":1}}`
+	if string(body) != want {
+		t.Errorf("body = %s, want %s", body, want)
+	}
+}
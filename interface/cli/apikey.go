@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/domain"
+	"github.com/takumi616/golang-backend-sample/interface/controller/request"
+	"github.com/takumi616/golang-backend-sample/interface/controller/response"
+	"github.com/takumi616/golang-backend-sample/interface/controller/transformer"
+)
+
+// APIKeyCommand manages the API keys from the server host. It is how the first admin key is created.
+type APIKeyCommand struct {
+	Usecase APIKeyUsecase
+	Stdout  io.Writer
+	Stderr  io.Writer
+}
+
+func NewAPIKeyCommand(usecase APIKeyUsecase, stdout, stderr io.Writer) *APIKeyCommand {
+	return &APIKeyCommand{
+		Usecase: usecase,
+		Stdout:  stdout,
+		Stderr:  stderr,
+	}
+}
+
+// APIKey dispatches "apikey create|list|revoke"
+func (c *APIKeyCommand) APIKey(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return UsageError("apikey requires a subcommand: create, list or revoke")
+	}
+
+	switch args[0] {
+	case "create":
+		return c.Create(ctx, args[1:])
+	case "list":
+		return c.List(ctx, args[1:])
+	case "revoke":
+		return c.Revoke(ctx, args[1:])
+	default:
+		return UsageError("unknown apikey subcommand %q", args[0])
+	}
+}
+
+// Create prints the token of the new key, which cannot be shown again
+func (c *APIKeyCommand) Create(ctx context.Context, args []string) error {
+	fs, output := newFlagSet(c.Stderr, "apikey create --name <name> --scopes <scope,...> [--expires-in <duration>]")
+	var req request.APIKeyReq
+	fs.StringVar(&req.Name, "name", "", "label of the key")
+	scopes := fs.String("scopes", "", "comma separated scopes: "+joinScopes(domain.Scopes))
+	expiresIn := fs.Duration("expires-in", 0, "lifetime of the key, such as 720h; the key never expires when omitted")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	if *scopes != "" {
+		for _, scope := range strings.Split(*scopes, ",") {
+			req.Scopes = append(req.Scopes, strings.TrimSpace(scope))
+		}
+	}
+	if *expiresIn < 0 {
+		return UsageError("--expires-in must be positive, got %s", *expiresIn)
+	}
+	if *expiresIn > 0 {
+		expiresAt := time.Now().Add(*expiresIn)
+		req.ExpiresAt = &expiresAt
+	}
+
+	// Validation check
+	if err := req.Validate(); err != nil {
+		return UsageError("invalid input parameters: %w", err)
+	}
+
+	// Execute the application layer logic
+	key := transformer.ToAPIKey(&req)
+	token, err := c.Usecase.CreateAPIKey(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(c.Stderr, "Store the token now; it cannot be shown again.")
+	return writeOutput(
+		c.Stdout, *output, response.CreatedAPIKeyRes{APIKeyRes: *transformer.ToAPIKeyResponse(key), Token: token},
+	)
+}
+
+func (c *APIKeyCommand) List(ctx context.Context, args []string) error {
+	fs, output := newFlagSet(c.Stderr, "apikey list")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	// Execute the application layer logic
+	keys, err := c.Usecase.FetchAPIKeyList(ctx)
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(c.Stdout, *output, transformer.ToAPIKeyResponseList(keys))
+}
+
+func (c *APIKeyCommand) Revoke(ctx context.Context, args []string) error {
+	fs, output := newFlagSet(c.Stderr, "apikey revoke <keyID>")
+	positional, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	keyID, err := strconv.ParseInt(positional[0], 10, 64)
+	if err != nil {
+		return UsageError("invalid keyID %q", positional[0])
+	}
+
+	// Execute the application layer logic
+	rowsAffected, err := c.Usecase.RevokeAPIKey(ctx, keyID)
+	if errors.Is(err, domain.ErrAPIKeyNotFound) {
+		return &ExitError{Code: ExitNotFound, Err: fmt.Errorf("api key %d is not registered", keyID)}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(c.Stdout, *output, response.RowsAffectedRes{RowsAffected: rowsAffected})
+}
+
+func joinScopes(scopes []domain.Scope) string {
+	names := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		names = append(names, string(scope))
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// PrintConfig writes the effective configuration for "config print".
+// values must already have the secrets redacted.
+func PrintConfig(stdout, stderr io.Writer, args []string, values map[string]string) error {
+	if len(args) == 0 || args[0] != "print" {
+		return UsageError("usage: config print [--output text|json]")
+	}
+
+	fs := flag.NewFlagSet("config print", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.String("output", OutputText, "output format: text or json")
+	if _, err := parseFlags(fs, args[1:], 0); err != nil {
+		return err
+	}
+
+	output := fs.Lookup("output").Value.String()
+	if output == OutputJSON {
+		return writeOutput(stdout, output, values)
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, err := fmt.Fprintf(stdout, "%s=%s\n", name, values[name]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, ExitOK},
+		{"plain error", errors.New("connection refused"), ExitFailure},
+		{"usage", UsageError("unknown command %q", "foo"), ExitUsage},
+		{"not found", &ExitError{Code: ExitNotFound, Err: errors.New("missing")}, ExitNotFound},
+		{"wrapped", fmt.Errorf("import: %w", &ExitError{Code: ExitConflict, Err: errors.New("duplicate")}), ExitConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExitErrorUnwrap(t *testing.T) {
+	cause := errors.New("cause")
+	err := &ExitError{Code: ExitFailure, Err: cause}
+
+	if !errors.Is(err, cause) || err.Error() != "cause" {
+		t.Errorf("ExitError = %v, want to wrap the cause", err)
+	}
+}
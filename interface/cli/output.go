@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/takumi616/golang-backend-sample/interface/controller/response"
+)
+
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+)
+
+type ImportRes struct {
+	Imported int `json:"imported"`
+	Skipped  int `json:"skipped"`
+}
+
+type ExportRes struct {
+	Exported int    `json:"exported"`
+	File     string `json:"file"`
+}
+
+func validateOutput(output string) error {
+	if output != OutputText && output != OutputJSON {
+		return UsageError("unknown output format %q, use %q or %q", output, OutputText, OutputJSON)
+	}
+
+	return nil
+}
+
+// writeOutput writes the result of a command in the requested format
+func writeOutput(w io.Writer, output string, body any) error {
+	if output == OutputJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(body)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	switch v := body.(type) {
+	case *response.VocabularyRes:
+		fmt.Fprintf(tw, "vocabulary_no\t%d\n", v.VocabularyNo)
+		fmt.Fprintf(tw, "title\t%s\n", v.Title)
+		fmt.Fprintf(tw, "meaning\t%s\n", v.Meaning)
+		fmt.Fprintf(tw, "sentence\t%s\n", v.Sentence)
+	case []*response.VocabularyRes:
+		fmt.Fprintln(tw, "VOCABULARY_NO\tTITLE\tMEANING")
+		for _, vocabulary := range v {
+			fmt.Fprintf(tw, "%d\t%s\t%s\n", vocabulary.VocabularyNo, vocabulary.Title, vocabulary.Meaning)
+		}
+	case response.VocabularyNoRes:
+		fmt.Fprintf(tw, "vocabulary_no\t%d\n", v.VocabularyNo)
+	case response.RowsAffectedRes:
+		fmt.Fprintf(tw, "rows_affected\t%d\n", v.RowsAffected)
+	case response.CreatedAPIKeyRes:
+		fmt.Fprintf(tw, "key_id\t%d\n", v.KeyID)
+		fmt.Fprintf(tw, "name\t%s\n", v.Name)
+		fmt.Fprintf(tw, "prefix\t%s\n", v.Prefix)
+		fmt.Fprintf(tw, "scopes\t%s\n", strings.Join(v.Scopes, ","))
+		fmt.Fprintf(tw, "expires_at\t%s\n", orDash(v.ExpiresAt))
+		fmt.Fprintf(tw, "token\t%s\n", v.Token)
+	case []*response.APIKeyRes:
+		fmt.Fprintln(tw, "KEY_ID\tNAME\tPREFIX\tSCOPES\tEXPIRES_AT\tLAST_USED_AT\tREVOKED_AT")
+		for _, key := range v {
+			fmt.Fprintf(
+				tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", key.KeyID, key.Name, key.Prefix, strings.Join(key.Scopes, ","),
+				orDash(key.ExpiresAt), orDash(key.LastUsedAt), orDash(key.RevokedAt),
+			)
+		}
+	case ImportRes:
+		fmt.Fprintf(tw, "imported\t%d\n", v.Imported)
+		fmt.Fprintf(tw, "skipped\t%d\n", v.Skipped)
+	case ExportRes:
+		fmt.Fprintf(tw, "exported\t%d\n", v.Exported)
+		fmt.Fprintf(tw, "file\t%s\n", v.File)
+	default:
+		fmt.Fprintf(tw, "%v\n", v)
+	}
+
+	return tw.Flush()
+}
+
+// orDash shows an unset time as "-"
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/takumi616/golang-backend-sample/interface/controller/response"
+)
+
+func TestWriteOutput(t *testing.T) {
+	vocabulary := &response.VocabularyRes{VocabularyNo: 1, Title: "apple", Meaning: "fruit", Sentence: "I ate an apple."}
+
+	tests := []struct {
+		name   string
+		output string
+		body   any
+		want   string
+	}{
+		{
+			name:   "vocabulary",
+			output: OutputText,
+			body:   vocabulary,
+			want:   "vocabulary_no  1\ntitle          apple\nmeaning        fruit\nsentence       I ate an apple.\n",
+		},
+		{
+			name:   "vocabulary list",
+			output: OutputText,
+			body:   []*response.VocabularyRes{vocabulary, {VocabularyNo: 12, Title: "banana", Meaning: "yellow fruit"}},
+			want:   "VOCABULARY_NO  TITLE   MEANING\n1              apple   fruit\n12             banana  yellow fruit\n",
+		},
+		{
+			name:   "rows affected",
+			output: OutputText,
+			body:   response.RowsAffectedRes{RowsAffected: 1},
+			want:   "rows_affected  1\n",
+		},
+		{
+			name:   "api key list",
+			output: OutputText,
+			body: []*response.APIKeyRes{{
+				KeyID: 1, Name: "importer", Prefix: "abc", Scopes: []string{"vocab:read", "vocab:write"},
+				LastUsedAt: "2024-01-02T03:04:05Z",
+			}},
+			want: "KEY_ID  NAME      PREFIX  SCOPES                  EXPIRES_AT  LAST_USED_AT          REVOKED_AT\n" +
+				"1       importer  abc     vocab:read,vocab:write  -           2024-01-02T03:04:05Z  -\n",
+		},
+		{
+			name:   "import",
+			output: OutputText,
+			body:   ImportRes{Imported: 2, Skipped: 1},
+			want:   "imported  2\nskipped   1\n",
+		},
+		{
+			name:   "export",
+			output: OutputText,
+			body:   ExportRes{Exported: 3, File: "vocabularies.json"},
+			want:   "exported  3\nfile      vocabularies.json\n",
+		},
+		{
+			name:   "json",
+			output: OutputJSON,
+			body:   ImportRes{Imported: 2, Skipped: 1},
+			want:   "{\n  \"imported\": 2,\n  \"skipped\": 1\n}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeOutput(&buf, tt.output, tt.body); err != nil {
+				t.Fatalf("writeOutput returned an error: %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("writeOutput() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
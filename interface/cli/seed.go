@@ -0,0 +1,31 @@
+package cli
+
+import "github.com/takumi616/golang-backend-sample/interface/controller/request"
+
+var seedVocabularies = []request.VocabularyReq{
+	{
+		Title:    "abundant",
+		Meaning:  "existing in large quantities",
+		Sentence: "The region has abundant natural resources.",
+	},
+	{
+		Title:    "meticulous",
+		Meaning:  "showing great attention to detail",
+		Sentence: "She kept meticulous records of every experiment.",
+	},
+	{
+		Title:    "resilient",
+		Meaning:  "able to recover quickly from difficulties",
+		Sentence: "Children are often more resilient than adults.",
+	},
+	{
+		Title:    "ubiquitous",
+		Meaning:  "present or found everywhere",
+		Sentence: "Smartphones have become ubiquitous in daily life.",
+	},
+	{
+		Title:    "candid",
+		Meaning:  "truthful and straightforward",
+		Sentence: "He gave a candid answer about the project's problems.",
+	},
+}
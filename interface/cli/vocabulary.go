@@ -0,0 +1,319 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+	"github.com/takumi616/golang-backend-sample/interface/controller/request"
+	"github.com/takumi616/golang-backend-sample/interface/controller/response"
+	"github.com/takumi616/golang-backend-sample/interface/controller/transformer"
+)
+
+type VocabularyCommand struct {
+	Usecase VocabularyUsecase
+	Stdout  io.Writer
+	Stderr  io.Writer
+}
+
+func NewVocabularyCommand(usecase VocabularyUsecase, stdout, stderr io.Writer) *VocabularyCommand {
+	return &VocabularyCommand{
+		Usecase: usecase,
+		Stdout:  stdout,
+		Stderr:  stderr,
+	}
+}
+
+// Vocab dispatches "vocab get|list|add|delete"
+func (c *VocabularyCommand) Vocab(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return UsageError("vocab requires a subcommand: get, list, add or delete")
+	}
+
+	switch args[0] {
+	case "get":
+		return c.Get(ctx, args[1:])
+	case "list":
+		return c.List(ctx, args[1:])
+	case "add":
+		return c.Add(ctx, args[1:])
+	case "delete":
+		return c.Delete(ctx, args[1:])
+	default:
+		return UsageError("unknown vocab subcommand %q", args[0])
+	}
+}
+
+func (c *VocabularyCommand) Get(ctx context.Context, args []string) error {
+	fs, output := c.newFlagSet("vocab get <vocabularyNo>")
+	vocabularyNo, err := parseVocabularyNo(fs, args)
+	if err != nil {
+		return err
+	}
+
+	// Execute the application layer logic
+	vocabulary, err := c.Usecase.FetchVocabularyByNo(ctx, vocabularyNo)
+	if errors.Is(err, domain.ErrVocabularyNotFound) {
+		return &ExitError{Code: ExitNotFound, Err: fmt.Errorf("vocabulary %d is not registered", vocabularyNo)}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(c.Stdout, *output, transformer.ToResponse(vocabulary))
+}
+
+func (c *VocabularyCommand) List(ctx context.Context, args []string) error {
+	fs, output := c.newFlagSet("vocab list")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	// Execute the application layer logic
+	vocabularyList, err := c.Usecase.FetchVocabularyList(ctx, usecase.VocabularyQuery{})
+	if err != nil {
+		return err
+	}
+
+	// Transform the domain model into the response struct
+	vocabularyResponse := []*response.VocabularyRes{}
+	for _, vocabulary := range vocabularyList {
+		vocabularyResponse = append(vocabularyResponse, transformer.ToResponse(vocabulary))
+	}
+
+	return writeOutput(c.Stdout, *output, vocabularyResponse)
+}
+
+func (c *VocabularyCommand) Add(ctx context.Context, args []string) error {
+	fs, output := c.newFlagSet("vocab add --title <title> --meaning <meaning> --sentence <sentence>")
+	var req request.VocabularyReq
+	fs.StringVar(&req.Title, "title", "", "title of the vocabulary")
+	fs.StringVar(&req.Meaning, "meaning", "", "meaning of the vocabulary")
+	fs.StringVar(&req.Sentence, "sentence", "", "example sentence")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	// Validation check
+	if err := req.Validate(); err != nil {
+		return UsageError("invalid input parameters: %w", err)
+	}
+
+	// Execute the application layer logic
+	vocabularyNo, err := c.Usecase.AddVocabulary(ctx, transformer.ToDomain(&req))
+	if errors.Is(err, domain.ErrVocabularyDuplicate) {
+		return &ExitError{Code: ExitConflict, Err: fmt.Errorf("vocabulary %q is already registered", req.Title)}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(c.Stdout, *output, response.VocabularyNoRes{VocabularyNo: vocabularyNo})
+}
+
+func (c *VocabularyCommand) Delete(ctx context.Context, args []string) error {
+	fs, output := c.newFlagSet("vocab delete <vocabularyNo>")
+	vocabularyNo, err := parseVocabularyNo(fs, args)
+	if err != nil {
+		return err
+	}
+
+	// Execute the application layer logic
+	rowsAffected, err := c.Usecase.DeleteVocabulary(ctx, vocabularyNo)
+	if errors.Is(err, domain.ErrVocabularyNotFound) {
+		return &ExitError{Code: ExitNotFound, Err: fmt.Errorf("vocabulary %d is not registered", vocabularyNo)}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(c.Stdout, *output, response.RowsAffectedRes{RowsAffected: rowsAffected})
+}
+
+// Import adds the vocabularies in a JSON file, skipping titles that are already registered
+func (c *VocabularyCommand) Import(ctx context.Context, args []string) error {
+	fs, output := c.newFlagSet("import <file>")
+	positional, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(positional[0])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var reqList []request.VocabularyReq
+	if err := json.NewDecoder(file).Decode(&reqList); err != nil {
+		return UsageError("failed to parse %s: %w", positional[0], err)
+	}
+
+	// Validate every entry before writing anything
+	for i := range reqList {
+		if err := reqList[i].Validate(); err != nil {
+			return UsageError("invalid entry at index %d: %w", i, err)
+		}
+	}
+
+	result, err := c.addAll(ctx, reqList)
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(c.Stdout, *output, result)
+}
+
+// Export writes every vocabulary to a JSON file that Import can read back
+func (c *VocabularyCommand) Export(ctx context.Context, args []string) error {
+	fs, output := c.newFlagSet("export <file>")
+	positional, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	// Execute the application layer logic
+	vocabularyList, err := c.Usecase.FetchVocabularyList(ctx, usecase.VocabularyQuery{})
+	if err != nil {
+		return err
+	}
+
+	vocabularyResponse := []*response.VocabularyRes{}
+	for _, vocabulary := range vocabularyList {
+		vocabularyResponse = append(vocabularyResponse, transformer.ToResponse(vocabulary))
+	}
+
+	file, err := os.Create(positional[0])
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(vocabularyResponse); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return writeOutput(c.Stdout, *output, ExportRes{Exported: len(vocabularyResponse), File: positional[0]})
+}
+
+// Seed registers a small set of sample vocabularies for local development
+func (c *VocabularyCommand) Seed(ctx context.Context, args []string) error {
+	fs, output := c.newFlagSet("seed")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	result, err := c.addAll(ctx, seedVocabularies)
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(c.Stdout, *output, result)
+}
+
+// addAll adds the vocabularies in one bulk insert, and falls back to
+// adding them one by one to skip the duplicates when the bulk insert conflicts
+func (c *VocabularyCommand) addAll(ctx context.Context, reqList []request.VocabularyReq) (ImportRes, error) {
+	vocabularies := make([]*domain.Vocabulary, 0, len(reqList))
+	for i := range reqList {
+		vocabularies = append(vocabularies, transformer.ToDomain(&reqList[i]))
+	}
+
+	inserted, err := c.Usecase.AddVocabularies(ctx, vocabularies)
+	if err == nil {
+		return ImportRes{Imported: int(inserted)}, nil
+	}
+	if !errors.Is(err, domain.ErrVocabularyDuplicate) {
+		return ImportRes{}, err
+	}
+
+	var result ImportRes
+	for _, vocabulary := range vocabularies {
+		_, err := c.Usecase.AddVocabulary(ctx, vocabulary)
+
+		// The title is already registered
+		if errors.Is(err, domain.ErrVocabularyDuplicate) {
+			result.Skipped++
+			continue
+		}
+
+		if err != nil {
+			return result, err
+		}
+		result.Imported++
+	}
+
+	return result, nil
+}
+
+func (c *VocabularyCommand) newFlagSet(usage string) (*flag.FlagSet, *string) {
+	return newFlagSet(c.Stderr, usage)
+}
+
+// newFlagSet returns the flags of a command with its --output flag
+func newFlagSet(stderr io.Writer, usage string) (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet(usage, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	output := fs.String("output", OutputText, "output format: text or json")
+	return fs, output
+}
+
+// parseFlags parses args, allowing flags after positional arguments,
+// and checks the number of positional arguments
+func parseFlags(fs *flag.FlagSet, args []string, nArg int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, &ExitError{Code: ExitUsage, Err: err}
+		}
+
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if len(positional) != nArg {
+		return nil, UsageError("usage: %s", fs.Name())
+	}
+
+	output := fs.Lookup("output")
+	if output != nil {
+		if err := validateOutput(output.Value.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	return positional, nil
+}
+
+func parseVocabularyNo(fs *flag.FlagSet, args []string) (int64, error) {
+	positional, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return 0, err
+	}
+
+	vocabularyNo, err := strconv.ParseInt(positional[0], 10, 64)
+	if err != nil {
+		return 0, UsageError("invalid vocabularyNo %q", positional[0])
+	}
+
+	return vocabularyNo, nil
+}
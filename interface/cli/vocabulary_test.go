@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+)
+
+// fakeVocabularyUsecase fails every call with err, or succeeds with the vocabulary 1
+type fakeVocabularyUsecase struct {
+	err error
+}
+
+func (f *fakeVocabularyUsecase) AddVocabulary(context.Context, *domain.Vocabulary) (int64, error) {
+	return 1, f.err
+}
+
+func (f *fakeVocabularyUsecase) AddVocabularies(_ context.Context, vocabularies []*domain.Vocabulary) (int64, error) {
+	return int64(len(vocabularies)), f.err
+}
+
+func (f *fakeVocabularyUsecase) FetchVocabularyByNo(_ context.Context, vocabularyNo int64) (*domain.Vocabulary, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &domain.Vocabulary{VocabularyNo: vocabularyNo, Title: "apple", Meaning: "fruit", Sentence: "s"}, nil
+}
+
+func (f *fakeVocabularyUsecase) FetchVocabularyList(context.Context, usecase.VocabularyQuery) ([]*domain.Vocabulary, error) {
+	return nil, f.err
+}
+
+func (f *fakeVocabularyUsecase) DeleteVocabulary(context.Context, int64) (int64, error) {
+	return 1, f.err
+}
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		nArg           int
+		wantPositional []string
+		wantName       string
+		wantCode       int
+	}{
+		{name: "no arguments", nArg: 0},
+		{name: "positional", args: []string{"1"}, nArg: 1, wantPositional: []string{"1"}},
+		{name: "flag first", args: []string{"--name", "alice", "1"}, nArg: 1, wantPositional: []string{"1"}, wantName: "alice"},
+		{name: "flag after", args: []string{"1", "--name", "alice"}, nArg: 1, wantPositional: []string{"1"}, wantName: "alice"},
+		{name: "flags around", args: []string{"1", "--output", "json", "2"}, nArg: 2, wantPositional: []string{"1", "2"}},
+		{name: "too few", nArg: 1, wantCode: ExitUsage},
+		{name: "too many", args: []string{"1", "2"}, nArg: 1, wantCode: ExitUsage},
+		{name: "unknown flag", args: []string{"--color"}, wantCode: ExitUsage},
+		{name: "unknown output", args: []string{"--output", "yaml"}, wantCode: ExitUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs, _ := newFlagSet(&bytes.Buffer{}, "test")
+			name := fs.String("name", "", "name")
+
+			positional, err := parseFlags(fs, tt.args, tt.nArg)
+			if got := ExitCode(err); got != tt.wantCode {
+				t.Fatalf("parseFlags(%q) = %v, want the exit code %d", tt.args, err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+			if !slices.Equal(positional, tt.wantPositional) || *name != tt.wantName {
+				t.Errorf(
+					"parseFlags(%q) = %q with --name %q, want %q with %q",
+					tt.args, positional, *name, tt.wantPositional, tt.wantName,
+				)
+			}
+		})
+	}
+}
+
+func TestVocabExitCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		err      error
+		wantCode int
+	}{
+		{name: "get", args: []string{"get", "1"}, wantCode: ExitOK},
+		{name: "get missing", args: []string{"get", "1"}, err: domain.ErrVocabularyNotFound, wantCode: ExitNotFound},
+		{name: "get invalid number", args: []string{"get", "one"}, wantCode: ExitUsage},
+		{
+			name:     "add duplicate",
+			args:     []string{"add", "--title", "apple", "--meaning", "fruit", "--sentence", "s"},
+			err:      domain.ErrVocabularyDuplicate,
+			wantCode: ExitConflict,
+		},
+		{name: "add invalid", args: []string{"add", "--title", "apple"}, wantCode: ExitUsage},
+		{name: "delete missing", args: []string{"delete", "1"}, err: domain.ErrVocabularyNotFound, wantCode: ExitNotFound},
+		{name: "list failure", args: []string{"list"}, err: errors.New("connection refused"), wantCode: ExitFailure},
+		{name: "unknown subcommand", args: []string{"rename"}, wantCode: ExitUsage},
+		{name: "no subcommand", wantCode: ExitUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command := NewVocabularyCommand(&fakeVocabularyUsecase{err: tt.err}, &bytes.Buffer{}, &bytes.Buffer{})
+			if got := ExitCode(command.Vocab(context.Background(), tt.args)); got != tt.wantCode {
+				t.Errorf("vocab %q exited with %d, want %d", tt.args, got, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestExportOutput(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "vocabularies.json")
+	var stdout bytes.Buffer
+	command := NewVocabularyCommand(&fakeVocabularyUsecase{}, &stdout, &bytes.Buffer{})
+
+	if err := command.Export(context.Background(), []string{file, "--output", "json"}); err != nil {
+		t.Fatalf("export returned an error: %v", err)
+	}
+
+	want := "{\n  \"exported\": 0,\n  \"file\": " + strconv.Quote(file) + "\n}\n"
+	if got := stdout.String(); got != want {
+		t.Errorf("export --output json wrote\n%s\nwant\n%s", got, want)
+	}
+	if content, err := os.ReadFile(file); err != nil || string(content) != "[]\n" {
+		t.Errorf("exported file = %q, %v, want an empty list", content, err)
+	}
+}
@@ -0,0 +1,20 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+)
+
+type VocabularyUsecase interface {
+	AddVocabulary(ctx context.Context, vocabulary *domain.Vocabulary) (int64, error)
+
+	AddVocabularies(ctx context.Context, vocabularies []*domain.Vocabulary) (int64, error)
+
+	FetchVocabularyByNo(ctx context.Context, vocabularyNo int64) (*domain.Vocabulary, error)
+
+	FetchVocabularyList(ctx context.Context, query usecase.VocabularyQuery) ([]*domain.Vocabulary, error)
+
+	DeleteVocabulary(ctx context.Context, vocabularyNo int64) (int64, error)
+}
@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/takumi616/golang-backend-sample/domain"
+	"github.com/takumi616/golang-backend-sample/interface/controller/helper"
+	"github.com/takumi616/golang-backend-sample/interface/controller/request"
+	"github.com/takumi616/golang-backend-sample/interface/controller/response"
+	"github.com/takumi616/golang-backend-sample/interface/controller/transformer"
+)
+
+type APIKeyController struct {
+	Usecase APIKeyUsecase
+
+	// Maximum size of a request body in bytes
+	MaxBodyBytes int64
+}
+
+func NewAPIKeyController(usecase APIKeyUsecase, maxBodyBytes int64) *APIKeyController {
+	return &APIKeyController{
+		Usecase:      usecase,
+		MaxBodyBytes: maxBodyBytes,
+	}
+}
+
+// CreateAPIKey responds with the token of the new key, which is shown only this once
+func (c *APIKeyController) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Read http request body
+	var req request.APIKeyReq
+	if err := helper.DecodeJSON(w, r, &req, c.MaxBodyBytes); err != nil {
+		slog.ErrorContext(ctx, "failed to read a request body", slog.String("error", err.Error()))
+		helper.WriteResponse(ctx, w, err.StatusCode, response.ErrorRes{Message: err.Message})
+		return
+	}
+	defer r.Body.Close()
+
+	// Validation check
+	if err := req.Validate(); err != nil {
+		slog.ErrorContext(ctx, "invalid request parameters", slog.String("error", err.Error()))
+		helper.WriteResponse(
+			ctx, w, http.StatusBadRequest,
+			response.ErrorRes{Message: fmt.Sprintf("Invalid input parameters: %s.", err)},
+		)
+		return
+	}
+
+	// Execute the application layer logic
+	key := transformer.ToAPIKey(&req)
+	token, err := c.Usecase.CreateAPIKey(ctx, key)
+	if err != nil {
+		writeAPIKeyError(ctx, w, err, "Failed to create the api key due to a server error.")
+		return
+	}
+
+	// Write a returned result to the response body
+	helper.WriteResponse(
+		ctx, w, http.StatusCreated,
+		response.CreatedAPIKeyRes{APIKeyRes: *transformer.ToAPIKeyResponse(key), Token: token},
+	)
+}
+
+func (c *APIKeyController) FetchAPIKeyList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Execute the application layer logic
+	keys, err := c.Usecase.FetchAPIKeyList(ctx)
+	if err != nil {
+		writeAPIKeyError(ctx, w, err, "Failed to get the api keys due to a server error.")
+		return
+	}
+
+	// Write a returned result to the response body
+	helper.WriteResponse(ctx, w, http.StatusOK, transformer.ToAPIKeyResponseList(keys))
+}
+
+func (c *APIKeyController) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Get the keyID from the request path
+	keyID, err := strconv.ParseInt(r.PathValue("keyID"), 10, 64)
+	if err != nil {
+		slog.ErrorContext(ctx, "invalid path value", slog.String("error", err.Error()))
+		helper.WriteResponse(
+			ctx, w, http.StatusBadRequest,
+			response.ErrorRes{Message: "Invalid request path value. Please check your http request path."},
+		)
+		return
+	}
+
+	// Execute the application layer logic
+	rowsAffected, err := c.Usecase.RevokeAPIKey(ctx, keyID)
+	if err != nil {
+		writeAPIKeyError(ctx, w, err, "Failed to revoke the api key due to a server error.")
+		return
+	}
+
+	// Write a returned result to the response body
+	helper.WriteResponse(ctx, w, http.StatusOK, response.RowsAffectedRes{RowsAffected: rowsAffected})
+}
+
+// writeAPIKeyError responds with the status of a usecase error, or 500 with serverErrorMessage
+func writeAPIKeyError(ctx context.Context, w http.ResponseWriter, err error, serverErrorMessage string) {
+	switch {
+	case errors.Is(err, domain.ErrAPIKeyNotFound):
+		helper.WriteResponse(
+			ctx, w, http.StatusNotFound,
+			response.ErrorRes{Message: "The api key is not registered."},
+		)
+	default:
+		helper.WriteResponse(ctx, w, http.StatusInternalServerError, response.ErrorRes{Message: serverErrorMessage})
+	}
+}
@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/takumi616/golang-backend-sample/domain"
+)
+
+type fakeAPIKeyUsecase struct {
+	createAPIKey    func(ctx context.Context, key *domain.APIKey) (string, error)
+	fetchAPIKeyList func(ctx context.Context) ([]*domain.APIKey, error)
+	revokeAPIKey    func(ctx context.Context, keyID int64) (int64, error)
+}
+
+func (f *fakeAPIKeyUsecase) CreateAPIKey(ctx context.Context, key *domain.APIKey) (string, error) {
+	return f.createAPIKey(ctx, key)
+}
+
+func (f *fakeAPIKeyUsecase) FetchAPIKeyList(ctx context.Context) ([]*domain.APIKey, error) {
+	return f.fetchAPIKeyList(ctx)
+}
+
+func (f *fakeAPIKeyUsecase) RevokeAPIKey(ctx context.Context, keyID int64) (int64, error) {
+	return f.revokeAPIKey(ctx, keyID)
+}
+
+func TestCreateAPIKey(t *testing.T) {
+	const validBody = `{"name":"importer","scopes":["vocab:read","vocab:write"],"expires_at":"2099-01-01T00:00:00Z"}`
+
+	tests := []struct {
+		name       string
+		body       string
+		createErr  error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			// The token is returned once, next to the prefix that identifies the key later
+			name:       "created",
+			body:       validBody,
+			wantStatus: http.StatusCreated,
+			wantBody: `{"key_id":1,"name":"importer","prefix":"vk_0123456789ab","scopes":["vocab:read","vocab:write"],` +
+				`"created_at":"2024-01-02T03:04:05Z","expires_at":"2099-01-01T00:00:00Z","token":"vk_0123456789ab_secret"}`,
+		},
+		{
+			name:       "validation error",
+			body:       `{"name":"importer","scopes":["vocab:delete"]}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   errorBody(t, `Invalid input parameters: unknown scope "vocab:delete".`),
+		},
+		{
+			name:       "server error",
+			body:       validBody,
+			createErr:  errServer,
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   errorBody(t, "Failed to create the api key due to a server error."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := &fakeAPIKeyUsecase{
+				createAPIKey: func(ctx context.Context, key *domain.APIKey) (string, error) {
+					if tt.createErr != nil {
+						return "", tt.createErr
+					}
+					key.KeyID, key.Prefix, key.CreatedAt = 1, "vk_0123456789ab", createdAt
+					return "vk_0123456789ab_secret", nil
+				},
+			}
+
+			rec := httptest.NewRecorder()
+			NewAPIKeyController(usecase, 1024).CreateAPIKey(rec, newRequest(http.MethodPost, "", tt.body))
+			checkResponse(t, rec, tt.wantStatus, tt.wantBody)
+		})
+	}
+}
+
+func TestFetchAPIKeyList(t *testing.T) {
+	usecase := &fakeAPIKeyUsecase{
+		fetchAPIKeyList: func(ctx context.Context) ([]*domain.APIKey, error) {
+			return []*domain.APIKey{{
+				KeyID: 1, Name: "importer", Prefix: "vk_0123456789ab", SecretHash: "hash",
+				Scopes: []domain.Scope{domain.ScopeVocabRead}, CreatedAt: createdAt, LastUsedAt: createdAt, RevokedAt: createdAt,
+			}}, nil
+		},
+	}
+
+	// The hash is never written to the response
+	rec := httptest.NewRecorder()
+	NewAPIKeyController(usecase, 1024).FetchAPIKeyList(rec, newRequest(http.MethodGet, "", ""))
+	checkResponse(
+		t, rec, http.StatusOK,
+		`[{"key_id":1,"name":"importer","prefix":"vk_0123456789ab","scopes":["vocab:read"],"created_at":"2024-01-02T03:04:05Z",`+
+			`"last_used_at":"2024-01-02T03:04:05Z","revoked_at":"2024-01-02T03:04:05Z"}]`,
+	)
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		keyID      string
+		revokeErr  error
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "ok", keyID: "1", wantStatus: http.StatusOK, wantBody: `{"rows_affected":1}`},
+		{
+			name:       "invalid path value",
+			keyID:      "abc",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   errorBody(t, "Invalid request path value. Please check your http request path."),
+		},
+		{
+			name:       "not found",
+			keyID:      "9",
+			revokeErr:  domain.ErrAPIKeyNotFound,
+			wantStatus: http.StatusNotFound,
+			wantBody:   errorBody(t, "The api key is not registered."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := &fakeAPIKeyUsecase{
+				revokeAPIKey: func(ctx context.Context, keyID int64) (int64, error) {
+					if tt.revokeErr != nil {
+						return 0, tt.revokeErr
+					}
+					return 1, nil
+				},
+			}
+
+			req := newRequest(http.MethodDelete, "", "")
+			req.SetPathValue("keyID", tt.keyID)
+			rec := httptest.NewRecorder()
+			NewAPIKeyController(usecase, 1024).RevokeAPIKey(rec, req)
+			checkResponse(t, rec, tt.wantStatus, tt.wantBody)
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/takumi616/golang-backend-sample/domain"
+)
+
+type APIKeyUsecase interface {
+	CreateAPIKey(ctx context.Context, key *domain.APIKey) (string, error)
+
+	FetchAPIKeyList(ctx context.Context) ([]*domain.APIKey, error)
+
+	RevokeAPIKey(ctx context.Context, keyID int64) (int64, error)
+}
@@ -0,0 +1,111 @@
+package helper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// DecodeError describes why a request body was rejected and how to respond to it
+type DecodeError struct {
+	StatusCode int
+	Message    string
+	Err        error
+}
+
+func (e *DecodeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeJSON decodes a single JSON value from the request body into dst.
+// The body must be sent as JSON, be at most maxBytes long, contain only known fields
+// and have nothing after the JSON value.
+func DecodeJSON(w http.ResponseWriter, r *http.Request, dst any, maxBytes int64) *DecodeError {
+	// Check the Content-Type header
+	if !isJSONContentType(r.Header.Get("Content-Type")) {
+		return &DecodeError{
+			StatusCode: http.StatusUnsupportedMediaType,
+			Message:    "Unsupported Content-Type. Please send the request body as application/json.",
+			Err:        fmt.Errorf("unsupported content type %q", r.Header.Get("Content-Type")),
+		}
+	}
+
+	// Limit the size of the request body
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(dst); err != nil {
+		return toDecodeError(err)
+	}
+
+	// Reject anything after the JSON value
+	if err := decoder.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return toDecodeError(err)
+		}
+
+		return &DecodeError{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid request format. The request body must contain a single JSON value.",
+			Err:        errors.New("request body has data after the JSON value"),
+		}
+	}
+
+	return nil
+}
+
+func toDecodeError(err error) *DecodeError {
+	var maxBytesErr *http.MaxBytesError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return &DecodeError{
+			StatusCode: http.StatusRequestEntityTooLarge,
+			Message:    fmt.Sprintf("Request body is too large. It must be %d bytes or fewer.", maxBytesErr.Limit),
+			Err:        err,
+		}
+	case errors.As(err, &typeErr):
+		return &DecodeError{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("Invalid request format. Field %q must be %s.", typeErr.Field, typeErr.Type),
+			Err:        err,
+		}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json has no error type for unknown fields
+		field := strings.TrimPrefix(err.Error(), "json: unknown field ")
+		return &DecodeError{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("Invalid request format. Unknown field %s.", field),
+			Err:        err,
+		}
+	default:
+		return &DecodeError{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid request format. Failed to parse JSON.",
+			Err:        err,
+		}
+	}
+}
+
+// isJSONContentType accepts application/json and application/*+json
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "application/json" ||
+		(strings.HasPrefix(mediaType, "application/") && strings.HasSuffix(mediaType, "+json"))
+}
@@ -0,0 +1,130 @@
+package helper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	type body struct {
+		Title string `json:"title"`
+		Count int    `json:"count"`
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantStatus  int
+		wantMessage string
+	}{
+		{
+			name:        "ok",
+			contentType: "application/json",
+			body:        `{"title":"apple","count":1}`,
+		},
+		{
+			name:        "charset and trailing whitespace",
+			contentType: "application/json; charset=utf-8",
+			body:        "{\"title\":\"apple\"}\n",
+		},
+		{
+			name:        "json suffix",
+			contentType: "application/merge-patch+json",
+			body:        `{"title":"apple"}`,
+		},
+		{
+			name:        "missing content type",
+			body:        `{"title":"apple"}`,
+			wantStatus:  http.StatusUnsupportedMediaType,
+			wantMessage: "Unsupported Content-Type. Please send the request body as application/json.",
+		},
+		{
+			name:        "form content type",
+			contentType: "application/x-www-form-urlencoded",
+			body:        `{"title":"apple"}`,
+			wantStatus:  http.StatusUnsupportedMediaType,
+			wantMessage: "Unsupported Content-Type. Please send the request body as application/json.",
+		},
+		{
+			name:        "too large",
+			contentType: "application/json",
+			body:        `{"title":"` + strings.Repeat("a", 64) + `"}`,
+			wantStatus:  http.StatusRequestEntityTooLarge,
+			wantMessage: "Request body is too large. It must be 32 bytes or fewer.",
+		},
+		{
+			name:        "unknown field",
+			contentType: "application/json",
+			body:        `{"title":"apple","color":"red"}`,
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: `Invalid request format. Unknown field "color".`,
+		},
+		{
+			name:        "wrong type",
+			contentType: "application/json",
+			body:        `{"count":"one"}`,
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: `Invalid request format. Field "count" must be int.`,
+		},
+		{
+			name:        "trailing value",
+			contentType: "application/json",
+			body:        `{"title":"apple"}{}`,
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: "Invalid request format. The request body must contain a single JSON value.",
+		},
+		{
+			name:        "trailing garbage",
+			contentType: "application/json",
+			body:        `{"title":"apple"} x`,
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: "Invalid request format. The request body must contain a single JSON value.",
+		},
+		{
+			name:        "syntax error",
+			contentType: "application/json",
+			body:        `{"title":`,
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: "Invalid request format. Failed to parse JSON.",
+		},
+		{
+			name:        "empty body",
+			contentType: "application/json",
+			body:        ``,
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: "Invalid request format. Failed to parse JSON.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+
+			var dst body
+			err := DecodeJSON(httptest.NewRecorder(), r, &dst, 32)
+
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("DecodeJSON returned an error: %v", err)
+				}
+				if dst.Title != "apple" {
+					t.Errorf("title = %q, want apple", dst.Title)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("DecodeJSON returned no error, want status %d", tt.wantStatus)
+			}
+			if err.StatusCode != tt.wantStatus || err.Message != tt.wantMessage {
+				t.Errorf("DecodeJSON = {%d %q}, want {%d %q}", err.StatusCode, err.Message, tt.wantStatus, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package helper
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/takumi616/golang-backend-sample/domain"
+)
+
+// VocabularyETag returns a strong entity tag that changes whenever the vocabulary is updated
+func VocabularyETag(vocabulary *domain.Vocabulary) string {
+	return fmt.Sprintf(`"%d-%d"`, vocabulary.VocabularyNo, vocabulary.Version)
+}
+
+// VocabularyListETag returns a strong entity tag that changes whenever
+// a vocabulary in the list is added, updated or deleted
+func VocabularyListETag(vocabularyList []*domain.Vocabulary) string {
+	hash := sha256.New()
+	for _, vocabulary := range vocabularyList {
+		fmt.Fprintf(hash, "%d-%d;", vocabulary.VocabularyNo, vocabulary.Version)
+	}
+	return `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+}
+
+// CheckNotModified sets the ETag header and responds with 304 Not Modified
+// when If-None-Match matches etag. The caller must not write anything else when it returns true.
+func CheckNotModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set("ETag", etag)
+
+	if !matchesETag(r.Header.Get("If-None-Match"), etag) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// matchesETag compares the tags of an If-None-Match header with the weak comparison of RFC 9110
+func matchesETag(ifNoneMatch, etag string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
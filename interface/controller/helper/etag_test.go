@@ -0,0 +1,62 @@
+package helper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/takumi616/golang-backend-sample/domain"
+)
+
+func TestCheckNotModified(t *testing.T) {
+	etag := VocabularyETag(&domain.Vocabulary{VocabularyNo: 5, Version: 2})
+
+	tests := []struct {
+		ifNoneMatch string
+		want        bool
+	}{
+		{"", false},
+		{`"5-1"`, false},
+		{`"5-2"`, true},
+		{`W/"5-2"`, true},
+		{`"5-1", "5-2"`, true},
+		{"*", true},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/api/vocabularies/5", nil)
+		if tt.ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", tt.ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+
+		if got := CheckNotModified(rec, r, etag); got != tt.want {
+			t.Errorf("CheckNotModified with If-None-Match %q = %v, want %v", tt.ifNoneMatch, got, tt.want)
+		}
+		if got := rec.Header().Get("ETag"); got != `"5-2"` {
+			t.Errorf("ETag = %s, want \"5-2\"", got)
+		}
+		if tt.want && rec.Code != http.StatusNotModified {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusNotModified)
+		}
+	}
+}
+
+func TestVocabularyListETag(t *testing.T) {
+	list := []*domain.Vocabulary{{VocabularyNo: 1, Version: 1}, {VocabularyNo: 2, Version: 1}}
+	etag := VocabularyListETag(list)
+
+	if got := VocabularyListETag([]*domain.Vocabulary{{VocabularyNo: 1, Version: 1}, {VocabularyNo: 2, Version: 1}}); got != etag {
+		t.Errorf("ETag of the same list = %s, want %s", got, etag)
+	}
+
+	for name, changed := range map[string][]*domain.Vocabulary{
+		"updated": {{VocabularyNo: 1, Version: 1}, {VocabularyNo: 2, Version: 2}},
+		"deleted": {{VocabularyNo: 1, Version: 1}},
+		"added":   {{VocabularyNo: 1, Version: 1}, {VocabularyNo: 2, Version: 1}, {VocabularyNo: 3, Version: 1}},
+	} {
+		if got := VocabularyListETag(changed); got == etag {
+			t.Errorf("ETag did not change when a vocabulary was %s", name)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// WriteEvent writes a server-sent event with the data encoded in JSON. An empty id is omitted.
+func WriteEvent(w io.Writer, id, name string, data any) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if id != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", id); err != nil {
+			return err
+		}
+	}
+
+	// JSON has no raw newline, so the data fits in one line
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, body)
+	return err
+}
@@ -0,0 +1,54 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/takumi616/golang-backend-sample/domain"
+)
+
+const maxAPIKeyNameLength = 100
+
+type APIKeyReq struct {
+	// Label of the key, such as the name of the client
+	Name string `json:"name"`
+
+	// Permissions of the key, such as vocab:read
+	Scopes []string `json:"scopes"`
+
+	// The key never expires when omitted
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func (r *APIKeyReq) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+	if utf8.RuneCountInString(r.Name) > maxAPIKeyNameLength {
+		return fmt.Errorf("name must be %d characters or fewer", maxAPIKeyNameLength)
+	}
+
+	if len(r.Scopes) == 0 {
+		return errors.New("scopes is required")
+	}
+	for i, scope := range r.Scopes {
+		if !slices.Contains(domain.Scopes, domain.Scope(scope)) {
+			return fmt.Errorf("unknown scope %q", scope)
+		}
+		if slices.Contains(r.Scopes[:i], scope) {
+			return fmt.Errorf("duplicate scope %q", scope)
+		}
+	}
+
+	if r.ExpiresAt != nil && !r.ExpiresAt.After(time.Now()) {
+		return errors.New("expires_at must be in the future")
+	}
+
+	return nil
+}
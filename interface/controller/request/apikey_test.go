@@ -0,0 +1,76 @@
+package request
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAPIKeyReqValidate(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		req     APIKeyReq
+		wantErr string
+	}{
+		{
+			name: "valid",
+			req:  APIKeyReq{Name: " importer ", Scopes: []string{"vocab:read", "vocab:write"}, ExpiresAt: &future},
+		},
+		{
+			name: "never expires",
+			req:  APIKeyReq{Name: "importer", Scopes: []string{"apikey:admin"}},
+		},
+		{
+			name:    "empty name",
+			req:     APIKeyReq{Name: " ", Scopes: []string{"vocab:read"}},
+			wantErr: "name is required",
+		},
+		{
+			name:    "name too long",
+			req:     APIKeyReq{Name: strings.Repeat("a", 101), Scopes: []string{"vocab:read"}},
+			wantErr: "name must be 100 characters or fewer",
+		},
+		{
+			name:    "no scopes",
+			req:     APIKeyReq{Name: "importer"},
+			wantErr: "scopes is required",
+		},
+		{
+			name:    "unknown scope",
+			req:     APIKeyReq{Name: "importer", Scopes: []string{"vocab:admin"}},
+			wantErr: `unknown scope "vocab:admin"`,
+		},
+		{
+			name:    "duplicate scope",
+			req:     APIKeyReq{Name: "importer", Scopes: []string{"vocab:read", "vocab:read"}},
+			wantErr: `duplicate scope "vocab:read"`,
+		},
+		{
+			name:    "expired",
+			req:     APIKeyReq{Name: "importer", Scopes: []string{"vocab:read"}, ExpiresAt: &past},
+			wantErr: "expires_at must be in the future",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("Validate() = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Validate() returned an error: %v", err)
+			}
+			if tt.req.Name != "importer" {
+				t.Errorf("name = %q, want it trimmed", tt.req.Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+)
+
+type BatchReq struct {
+	// Roll back every operation when one of them fails
+	Atomic bool `json:"atomic"`
+
+	Operations []BatchOperationReq `json:"operations"`
+}
+
+type BatchOperationReq struct {
+	// create, update or delete
+	Op string `json:"op"`
+
+	// Target of an update or a delete
+	VocabularyNo int64 `json:"vocabulary_no"`
+
+	// Content of a create or an update
+	Vocabulary *VocabularyReq `json:"vocabulary"`
+}
+
+// Validate checks the number of operations and each of them
+func (r *BatchReq) Validate(maxOperations int) error {
+	if len(r.Operations) == 0 {
+		return errors.New("operations are required")
+	}
+	if len(r.Operations) > maxOperations {
+		return fmt.Errorf("a batch must have %d operations or fewer, got %d", maxOperations, len(r.Operations))
+	}
+
+	for i := range r.Operations {
+		if err := r.Operations[i].Validate(); err != nil {
+			return fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *BatchOperationReq) Validate() error {
+	switch r.Op {
+	case "create":
+		if r.VocabularyNo != 0 {
+			return errors.New("vocabulary_no must not be set for create")
+		}
+	case "update":
+		if r.VocabularyNo <= 0 {
+			return errors.New("vocabulary_no is required for update")
+		}
+	case "delete":
+		if r.VocabularyNo <= 0 {
+			return errors.New("vocabulary_no is required for delete")
+		}
+		if r.Vocabulary != nil {
+			return errors.New("vocabulary must not be set for delete")
+		}
+		return nil
+	default:
+		return fmt.Errorf("op must be create, update or delete, got %q", r.Op)
+	}
+
+	if r.Vocabulary == nil {
+		return fmt.Errorf("vocabulary is required for %s", r.Op)
+	}
+	return r.Vocabulary.Validate()
+}
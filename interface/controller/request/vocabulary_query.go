@@ -0,0 +1,81 @@
+package request
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+)
+
+// Query parameters of the vocabulary list
+var vocabularyQueryParams = []string{
+	"sort", "title_prefix", "sentence_contains", "created_from", "created_until", "updated_from", "updated_until",
+}
+
+// Maximum length of a filter term
+const maxFilterLength = 100
+
+// ParseVocabularyQuery turns the query parameters of the vocabulary list into a VocabularyQuery.
+// Unknown and repeated parameters are rejected.
+func ParseVocabularyQuery(values url.Values) (usecase.VocabularyQuery, error) {
+	var query usecase.VocabularyQuery
+
+	// Report the parameters in a stable order
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		if !slices.Contains(vocabularyQueryParams, name) {
+			return usecase.VocabularyQuery{}, fmt.Errorf("unknown query parameter %q", name)
+		}
+		if len(values[name]) > 1 {
+			return usecase.VocabularyQuery{}, fmt.Errorf("query parameter %q must be given once", name)
+		}
+	}
+
+	if value := values.Get("sort"); value != "" {
+		sorts, err := usecase.ParseVocabularySort(value)
+		if err != nil {
+			return usecase.VocabularyQuery{}, err
+		}
+		query.Sort = sorts
+	}
+
+	for _, filter := range []struct {
+		name string
+		dst  *string
+	}{
+		{"title_prefix", &query.TitlePrefix},
+		{"sentence_contains", &query.SentenceContains},
+	} {
+		value := values.Get(filter.name)
+		if len(value) > maxFilterLength {
+			return usecase.VocabularyQuery{}, fmt.Errorf("%s must be %d characters or fewer", filter.name, maxFilterLength)
+		}
+		*filter.dst = value
+	}
+
+	// Time ranges in RFC 3339
+	for _, bound := range []struct {
+		name string
+		dst  *time.Time
+	}{
+		{"created_from", &query.CreatedFrom},
+		{"created_until", &query.CreatedUntil},
+		{"updated_from", &query.UpdatedFrom},
+		{"updated_until", &query.UpdatedUntil},
+	} {
+		value := values.Get(bound.name)
+		if value == "" {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return usecase.VocabularyQuery{}, fmt.Errorf("%s must be an RFC 3339 time, got %q", bound.name, value)
+		}
+		*bound.dst = t
+	}
+
+	return query, nil
+}
@@ -0,0 +1,110 @@
+package request
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+)
+
+func TestParseVocabularyQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    usecase.VocabularyQuery
+		wantErr string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:  "filters and sort",
+			query: "title_prefix=ap&sentence_contains=day&sort=-title,vocabulary_no",
+			want: usecase.VocabularyQuery{
+				TitlePrefix:      "ap",
+				SentenceContains: "day",
+				Sort: []usecase.VocabularySort{
+					{Field: usecase.SortByTitle, Descending: true},
+					{Field: usecase.SortByVocabularyNo},
+				},
+			},
+		},
+		{
+			name:  "time ranges",
+			query: "created_from=2024-01-01T00:00:00Z&created_until=2024-02-01T09:00:00%2B09:00&updated_from=2024-01-15T00:00:00.5Z&sort=-created_at,updated_at",
+			want: usecase.VocabularyQuery{
+				CreatedFrom:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				CreatedUntil: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedFrom:  time.Date(2024, 1, 15, 0, 0, 0, 500_000_000, time.UTC),
+				Sort: []usecase.VocabularySort{
+					{Field: usecase.SortByCreatedAt, Descending: true},
+					{Field: usecase.SortByUpdatedAt},
+				},
+			},
+		},
+		{
+			name:    "invalid time",
+			query:   "updated_until=2024-01-01",
+			wantErr: `updated_until must be an RFC 3339 time, got "2024-01-01"`,
+		},
+		{
+			name:    "unknown parameter",
+			query:   "title=apple",
+			wantErr: `unknown query parameter "title"`,
+		},
+		{
+			name:    "repeated parameter",
+			query:   "title_prefix=a&title_prefix=b",
+			wantErr: `query parameter "title_prefix" must be given once`,
+		},
+		{
+			name:    "unknown sort field",
+			query:   "sort=meaning",
+			wantErr: `unknown sort field "meaning"`,
+		},
+		{
+			name:    "duplicate sort field",
+			query:   "sort=title,-title",
+			wantErr: `duplicate sort field "title"`,
+		},
+		{
+			name:    "filter too long",
+			query:   "sentence_contains=" + strings.Repeat("a", 101),
+			wantErr: "sentence_contains must be 100 characters or fewer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ParseVocabularyQuery(values)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !equalQuery(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// equalQuery compares the times by the instant they represent
+func equalQuery(a, b usecase.VocabularyQuery) bool {
+	return a.CreatedFrom.Equal(b.CreatedFrom) && a.CreatedUntil.Equal(b.CreatedUntil) &&
+		a.UpdatedFrom.Equal(b.UpdatedFrom) && a.UpdatedUntil.Equal(b.UpdatedUntil) &&
+		a.TitlePrefix == b.TitlePrefix && a.SentenceContains == b.SentenceContains && reflect.DeepEqual(a.Sort, b.Sort)
+}
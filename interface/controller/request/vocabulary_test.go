@@ -0,0 +1,70 @@
+package request
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVocabularyReqValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     VocabularyReq
+		want    VocabularyReq
+		wantErr string
+	}{
+		{
+			name: "valid",
+			req:  VocabularyReq{Title: "apple", Meaning: "fruit", Sentence: "I ate an apple."},
+			want: VocabularyReq{Title: "apple", Meaning: "fruit", Sentence: "I ate an apple."},
+		},
+		{
+			name: "trimmed",
+			req:  VocabularyReq{Title: "  apple\t", Meaning: " fruit ", Sentence: "\nI ate an apple. "},
+			want: VocabularyReq{Title: "apple", Meaning: "fruit", Sentence: "I ate an apple."},
+		},
+		{
+			name: "title of 20 characters",
+			req:  VocabularyReq{Title: strings.Repeat("a", 20), Meaning: "fruit", Sentence: "sentence"},
+			want: VocabularyReq{Title: strings.Repeat("a", 20), Meaning: "fruit", Sentence: "sentence"},
+		},
+		{
+			name:    "empty title",
+			req:     VocabularyReq{Title: "   ", Meaning: "fruit", Sentence: "sentence"},
+			wantErr: "title is required",
+		},
+		{
+			name:    "title too long",
+			req:     VocabularyReq{Title: strings.Repeat("a", 21), Meaning: "fruit", Sentence: "sentence"},
+			wantErr: "title must be 20 characters or fewer",
+		},
+		{
+			name:    "empty meaning",
+			req:     VocabularyReq{Title: "apple", Meaning: "", Sentence: "sentence"},
+			wantErr: "meaning is required",
+		},
+		{
+			name:    "empty sentence",
+			req:     VocabularyReq{Title: "apple", Meaning: "fruit", Sentence: " "},
+			wantErr: "sentence is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("Validate() = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Validate() returned an error: %v", err)
+			}
+			if tt.req != tt.want {
+				t.Errorf("Validate() left %+v, want %+v", tt.req, tt.want)
+			}
+		})
+	}
+}
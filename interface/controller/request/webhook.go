@@ -0,0 +1,107 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+)
+
+// Limits of a webhook registration
+const (
+	maxWebhookURLLength = 2048
+	minWebhookSecret    = 16
+	maxWebhookSecret    = 256
+)
+
+// Number of deliveries listed when no limit is given, and the largest limit
+const (
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 100
+)
+
+type WebhookReq struct {
+	// http or https URL the events are posted to
+	URL string `json:"url"`
+
+	// Key of the HMAC-SHA256 signature of the payloads
+	Secret string `json:"secret"`
+
+	// Types of the events delivered, such as vocabulary.created
+	EventTypes []string `json:"event_types"`
+}
+
+func (r *WebhookReq) Validate() error {
+	r.URL = strings.TrimSpace(r.URL)
+
+	if r.URL == "" {
+		return errors.New("url is required")
+	}
+	if len(r.URL) > maxWebhookURLLength {
+		return fmt.Errorf("url must be %d characters or fewer", maxWebhookURLLength)
+	}
+	u, err := url.Parse(r.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+
+	if len(r.Secret) < minWebhookSecret || len(r.Secret) > maxWebhookSecret {
+		return fmt.Errorf("secret must be %d to %d characters", minWebhookSecret, maxWebhookSecret)
+	}
+
+	if len(r.EventTypes) == 0 {
+		return errors.New("event_types is required")
+	}
+	for i, eventType := range r.EventTypes {
+		if !slices.Contains(usecase.VocabularyEventTypes, usecase.VocabularyEventType(eventType)) {
+			return fmt.Errorf("unknown event type %q", eventType)
+		}
+		if slices.Contains(r.EventTypes[:i], eventType) {
+			return fmt.Errorf("duplicate event type %q", eventType)
+		}
+	}
+
+	return nil
+}
+
+type DeliveryQuery struct {
+	// Every status when empty
+	Status usecase.WebhookDeliveryStatus
+
+	Limit int
+}
+
+// ParseDeliveryQuery parses the status and limit parameters of the delivery log
+func ParseDeliveryQuery(values url.Values) (*DeliveryQuery, error) {
+	for name := range values {
+		if name != "status" && name != "limit" {
+			return nil, fmt.Errorf("unknown query parameter %q", name)
+		}
+		if len(values[name]) > 1 {
+			return nil, fmt.Errorf("query parameter %q is repeated", name)
+		}
+	}
+
+	query := &DeliveryQuery{Limit: defaultDeliveryLimit}
+
+	switch status := usecase.WebhookDeliveryStatus(values.Get("status")); status {
+	case "", usecase.DeliveryPending, usecase.DeliveryDelivered, usecase.DeliveryDead:
+		query.Status = status
+	default:
+		return nil, fmt.Errorf("status must be pending, delivered or dead, got %q", status)
+	}
+
+	if value := values.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxDeliveryLimit {
+			return nil, fmt.Errorf("limit must be an integer from 1 to %d, got %q", maxDeliveryLimit, value)
+		}
+		query.Limit = limit
+	}
+
+	return query, nil
+}
@@ -0,0 +1,124 @@
+package request
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+)
+
+func TestWebhookReqValidate(t *testing.T) {
+	const secret = "0123456789abcdef"
+
+	tests := []struct {
+		name    string
+		req     WebhookReq
+		wantErr string
+	}{
+		{
+			name: "valid",
+			req:  WebhookReq{URL: " https://example.com/hook ", Secret: secret, EventTypes: []string{"vocabulary.created", "vocabulary.deleted"}},
+		},
+		{
+			name:    "empty url",
+			req:     WebhookReq{URL: " ", Secret: secret, EventTypes: []string{"vocabulary.created"}},
+			wantErr: "url is required",
+		},
+		{
+			name:    "relative url",
+			req:     WebhookReq{URL: "/hook", Secret: secret, EventTypes: []string{"vocabulary.created"}},
+			wantErr: "url must be an absolute http or https URL",
+		},
+		{
+			name:    "unsupported scheme",
+			req:     WebhookReq{URL: "ftp://example.com/hook", Secret: secret, EventTypes: []string{"vocabulary.created"}},
+			wantErr: "url must be an absolute http or https URL",
+		},
+		{
+			name:    "url too long",
+			req:     WebhookReq{URL: "https://example.com/" + strings.Repeat("a", 2048), Secret: secret, EventTypes: []string{"vocabulary.created"}},
+			wantErr: "url must be 2048 characters or fewer",
+		},
+		{
+			name:    "short secret",
+			req:     WebhookReq{URL: "https://example.com/hook", Secret: "short", EventTypes: []string{"vocabulary.created"}},
+			wantErr: "secret must be 16 to 256 characters",
+		},
+		{
+			name:    "no event types",
+			req:     WebhookReq{URL: "https://example.com/hook", Secret: secret},
+			wantErr: "event_types is required",
+		},
+		{
+			name:    "unknown event type",
+			req:     WebhookReq{URL: "https://example.com/hook", Secret: secret, EventTypes: []string{"vocabulary.read"}},
+			wantErr: `unknown event type "vocabulary.read"`,
+		},
+		{
+			name:    "duplicate event type",
+			req:     WebhookReq{URL: "https://example.com/hook", Secret: secret, EventTypes: []string{"vocabulary.created", "vocabulary.created"}},
+			wantErr: `duplicate event type "vocabulary.created"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("Validate() = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Validate() returned an error: %v", err)
+			}
+			if tt.req.URL != "https://example.com/hook" {
+				t.Errorf("url = %q, want it trimmed", tt.req.URL)
+			}
+		})
+	}
+}
+
+func TestParseDeliveryQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    DeliveryQuery
+		wantErr bool
+	}{
+		{name: "default", query: "", want: DeliveryQuery{Limit: 50}},
+		{name: "status and limit", query: "status=dead&limit=100", want: DeliveryQuery{Status: usecase.DeliveryDead, Limit: 100}},
+		{name: "unknown status", query: "status=failed", wantErr: true},
+		{name: "limit too large", query: "limit=101", wantErr: true},
+		{name: "zero limit", query: "limit=0", wantErr: true},
+		{name: "unknown parameter", query: "page=2", wantErr: true},
+		{name: "repeated parameter", query: "status=dead&status=pending", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ParseDeliveryQuery(values)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseDeliveryQuery(%q) = %+v, want an error", tt.query, got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseDeliveryQuery(%q) returned an error: %v", tt.query, err)
+			}
+			if *got != tt.want {
+				t.Errorf("ParseDeliveryQuery(%q) = %+v, want %+v", tt.query, *got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package response
+
+// APIKeyRes never includes the token or its hash
+type APIKeyRes struct {
+	KeyID  int64    `json:"key_id"`
+	Name   string   `json:"name"`
+	Prefix string   `json:"prefix"`
+	Scopes []string `json:"scopes"`
+
+	CreatedAt  string `json:"created_at"`
+	ExpiresAt  string `json:"expires_at,omitempty"`
+	LastUsedAt string `json:"last_used_at,omitempty"`
+	RevokedAt  string `json:"revoked_at,omitempty"`
+}
+
+// CreatedAPIKeyRes carries the token of a new key. It cannot be retrieved again.
+type CreatedAPIKeyRes struct {
+	APIKeyRes
+	Token string `json:"token"`
+}
@@ -0,0 +1,19 @@
+package response
+
+type BatchRes struct {
+	// false when an atomic batch was rolled back
+	Committed bool `json:"committed"`
+
+	Results []BatchResultRes `json:"results"`
+}
+
+type BatchResultRes struct {
+	Index int    `json:"index"`
+	Op    string `json:"op"`
+
+	// HTTP status code the operation would have returned on its own endpoint
+	Status int `json:"status"`
+
+	VocabularyNo int64  `json:"vocabulary_no,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
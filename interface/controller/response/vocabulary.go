@@ -9,6 +9,13 @@ type VocabularyRes struct {
 	Title        string `json:"title"`
 	Meaning      string `json:"meaning"`
 	Sentence     string `json:"sentence"`
+
+	// RFC 3339 timestamps in UTC
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+
+	CreatedBy string `json:"created_by"`
+	UpdatedBy string `json:"updated_by"`
 }
 
 type RowsAffectedRes struct {
@@ -0,0 +1,14 @@
+package response
+
+// VocabularyEventRes is the data of a server-sent vocabulary event
+type VocabularyEventRes struct {
+	VocabularyNo int64 `json:"vocabulary_no"`
+
+	// Content written by a create or an update. Omitted for a delete.
+	Title    string `json:"title,omitempty"`
+	Meaning  string `json:"meaning,omitempty"`
+	Sentence string `json:"sentence,omitempty"`
+
+	Actor      string `json:"actor"`
+	OccurredAt string `json:"occurred_at"`
+}
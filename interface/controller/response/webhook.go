@@ -0,0 +1,32 @@
+package response
+
+type WebhookIDRes struct {
+	WebhookID int64 `json:"webhook_id"`
+}
+
+// WebhookRes never includes the secret
+type WebhookRes struct {
+	WebhookID  int64    `json:"webhook_id"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+type WebhookDeliveryRes struct {
+	DeliveryID   int64  `json:"delivery_id"`
+	EventType    string `json:"event_type"`
+	VocabularyNo int64  `json:"vocabulary_no"`
+
+	// pending, delivered or dead
+	Status   string `json:"status"`
+	Attempts int    `json:"attempts"`
+
+	// Only for a pending delivery
+	NextAttemptAt string `json:"next_attempt_at,omitempty"`
+
+	// Outcome of the last failed attempt
+	LastError string `json:"last_error,omitempty"`
+
+	CreatedAt   string `json:"created_at"`
+	DeliveredAt string `json:"delivered_at,omitempty"`
+}
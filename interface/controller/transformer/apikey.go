@@ -0,0 +1,53 @@
+package transformer
+
+import (
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/domain"
+	"github.com/takumi616/golang-backend-sample/interface/controller/request"
+	"github.com/takumi616/golang-backend-sample/interface/controller/response"
+)
+
+// http request -> api key
+func ToAPIKey(req *request.APIKeyReq) *domain.APIKey {
+	key := &domain.APIKey{Name: req.Name}
+	for _, scope := range req.Scopes {
+		key.Scopes = append(key.Scopes, domain.Scope(scope))
+	}
+	if req.ExpiresAt != nil {
+		key.ExpiresAt = *req.ExpiresAt
+	}
+	return key
+}
+
+// api key -> http response
+func ToAPIKeyResponse(key *domain.APIKey) *response.APIKeyRes {
+	res := &response.APIKeyRes{
+		KeyID:     key.KeyID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    make([]string, 0, len(key.Scopes)),
+		CreatedAt: key.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+	for _, scope := range key.Scopes {
+		res.Scopes = append(res.Scopes, string(scope))
+	}
+	if !key.ExpiresAt.IsZero() {
+		res.ExpiresAt = key.ExpiresAt.UTC().Format(time.RFC3339Nano)
+	}
+	if !key.LastUsedAt.IsZero() {
+		res.LastUsedAt = key.LastUsedAt.UTC().Format(time.RFC3339Nano)
+	}
+	if !key.RevokedAt.IsZero() {
+		res.RevokedAt = key.RevokedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return res
+}
+
+func ToAPIKeyResponseList(keys []*domain.APIKey) []*response.APIKeyRes {
+	resList := make([]*response.APIKeyRes, 0, len(keys))
+	for _, key := range keys {
+		resList = append(resList, ToAPIKeyResponse(key))
+	}
+	return resList
+}
@@ -0,0 +1,67 @@
+package transformer
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+	"github.com/takumi616/golang-backend-sample/interface/controller/request"
+	"github.com/takumi616/golang-backend-sample/interface/controller/response"
+)
+
+// http request -> batch operations
+func ToBatchOperations(req *request.BatchReq) []usecase.BatchOperation {
+	operations := make([]usecase.BatchOperation, 0, len(req.Operations))
+	for _, op := range req.Operations {
+		operation := usecase.BatchOperation{
+			Type:         usecase.BatchOperationType(op.Op),
+			VocabularyNo: op.VocabularyNo,
+		}
+		if op.Vocabulary != nil {
+			operation.Vocabulary = ToDomain(op.Vocabulary)
+		}
+		operations = append(operations, operation)
+	}
+	return operations
+}
+
+// batch results -> http response
+func ToBatchResponse(req *request.BatchReq, results []usecase.BatchResult) *response.BatchRes {
+	res := &response.BatchRes{
+		Committed: true,
+		Results:   make([]response.BatchResultRes, 0, len(results)),
+	}
+
+	for i, result := range results {
+		op := req.Operations[i].Op
+		resultRes := response.BatchResultRes{Index: i, Op: op, VocabularyNo: result.VocabularyNo}
+
+		switch {
+		case result.Err == nil && op == "create":
+			resultRes.Status = http.StatusCreated
+		case result.Err == nil:
+			resultRes.Status = http.StatusOK
+		case errors.Is(result.Err, domain.ErrVocabularyNotFound):
+			resultRes.Status = http.StatusNotFound
+			resultRes.Error = "The vocabulary is not registered."
+		case errors.Is(result.Err, domain.ErrVocabularyDuplicate):
+			resultRes.Status = http.StatusConflict
+			resultRes.Error = "The title is already registered."
+		case errors.Is(result.Err, usecase.ErrBatchAborted):
+			resultRes.Status = http.StatusFailedDependency
+			resultRes.Error = "Not applied since another operation of the batch failed."
+		default:
+			resultRes.Status = http.StatusInternalServerError
+			resultRes.Error = "Failed due to a server error."
+		}
+
+		// Every result is rolled back when an atomic batch fails
+		if result.Err != nil && req.Atomic {
+			res.Committed = false
+		}
+		res.Results = append(res.Results, resultRes)
+	}
+
+	return res
+}
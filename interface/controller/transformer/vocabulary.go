@@ -1,6 +1,8 @@
 package transformer
 
 import (
+	"time"
+
 	"github.com/takumi616/golang-backend-sample/domain"
 	"github.com/takumi616/golang-backend-sample/interface/controller/request"
 	"github.com/takumi616/golang-backend-sample/interface/controller/response"
@@ -22,5 +24,9 @@ func ToResponse(vocabulary *domain.Vocabulary) *response.VocabularyRes {
 		Title:        vocabulary.Title,
 		Meaning:      vocabulary.Meaning,
 		Sentence:     vocabulary.Sentence,
+		CreatedAt:    vocabulary.CreatedAt.UTC().Format(time.RFC3339Nano),
+		UpdatedAt:    vocabulary.UpdatedAt.UTC().Format(time.RFC3339Nano),
+		CreatedBy:    vocabulary.CreatedBy,
+		UpdatedBy:    vocabulary.UpdatedBy,
 	}
 }
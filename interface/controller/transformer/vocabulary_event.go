@@ -0,0 +1,24 @@
+package transformer
+
+import (
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/interface/controller/response"
+)
+
+// vocabulary event -> server-sent event data
+func ToVocabularyEventResponse(event usecase.VocabularyEvent) *response.VocabularyEventRes {
+	res := &response.VocabularyEventRes{
+		VocabularyNo: event.VocabularyNo,
+		Actor:        event.Actor,
+		OccurredAt:   event.OccurredAt.UTC().Format(time.RFC3339Nano),
+	}
+
+	if event.Vocabulary != nil {
+		res.Title = event.Vocabulary.Title
+		res.Meaning = event.Vocabulary.Meaning
+		res.Sentence = event.Vocabulary.Sentence
+	}
+	return res
+}
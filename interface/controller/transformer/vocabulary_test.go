@@ -0,0 +1,42 @@
+package transformer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/domain"
+	"github.com/takumi616/golang-backend-sample/interface/controller/request"
+	"github.com/takumi616/golang-backend-sample/interface/controller/response"
+)
+
+func TestToDomain(t *testing.T) {
+	req := &request.VocabularyReq{Title: "apple", Meaning: "fruit", Sentence: "I ate an apple."}
+
+	got := ToDomain(req)
+
+	want := domain.Vocabulary{Title: "apple", Meaning: "fruit", Sentence: "I ate an apple."}
+	if *got != want {
+		t.Errorf("ToDomain() = %+v, want %+v", got, want)
+	}
+}
+
+func TestToResponse(t *testing.T) {
+	vocabulary := &domain.Vocabulary{
+		VocabularyNo: 7, Title: "apple", Meaning: "fruit", Sentence: "I ate an apple.",
+		CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		UpdatedAt: time.Date(2024, 1, 2, 12, 4, 5, 123_000, time.FixedZone("JST", 9*60*60)),
+		CreatedBy: "alice", UpdatedBy: "bob",
+	}
+
+	got := ToResponse(vocabulary)
+
+	// Times are written in UTC
+	want := response.VocabularyRes{
+		VocabularyNo: 7, Title: "apple", Meaning: "fruit", Sentence: "I ate an apple.",
+		CreatedAt: "2024-01-02T03:04:05Z", UpdatedAt: "2024-01-02T03:04:05.000123Z",
+		CreatedBy: "alice", UpdatedBy: "bob",
+	}
+	if *got != want {
+		t.Errorf("ToResponse() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,61 @@
+package transformer
+
+import (
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+	"github.com/takumi616/golang-backend-sample/interface/controller/request"
+	"github.com/takumi616/golang-backend-sample/interface/controller/response"
+)
+
+// http request -> webhook
+func ToWebhook(req *request.WebhookReq) *domain.Webhook {
+	return &domain.Webhook{
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: req.EventTypes,
+	}
+}
+
+// webhook -> http response
+func ToWebhookResponse(webhook *domain.Webhook) *response.WebhookRes {
+	return &response.WebhookRes{
+		WebhookID:  webhook.WebhookID,
+		URL:        webhook.URL,
+		EventTypes: webhook.EventTypes,
+		CreatedAt:  webhook.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+func ToWebhookResponseList(webhooks []*domain.Webhook) []*response.WebhookRes {
+	resList := make([]*response.WebhookRes, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		resList = append(resList, ToWebhookResponse(webhook))
+	}
+	return resList
+}
+
+// webhook deliveries -> http response
+func ToDeliveryResponseList(deliveries []*usecase.WebhookDelivery) []*response.WebhookDeliveryRes {
+	resList := make([]*response.WebhookDeliveryRes, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		res := &response.WebhookDeliveryRes{
+			DeliveryID:   delivery.DeliveryID,
+			EventType:    string(delivery.Event.Type),
+			VocabularyNo: delivery.Event.VocabularyNo,
+			Status:       string(delivery.Status),
+			Attempts:     delivery.Attempts,
+			LastError:    delivery.LastError,
+			CreatedAt:    delivery.CreatedAt.UTC().Format(time.RFC3339Nano),
+		}
+		if delivery.Status == usecase.DeliveryPending {
+			res.NextAttemptAt = delivery.NextAttemptAt.UTC().Format(time.RFC3339Nano)
+		}
+		if !delivery.DeliveredAt.IsZero() {
+			res.DeliveredAt = delivery.DeliveredAt.UTC().Format(time.RFC3339Nano)
+		}
+		resList = append(resList, res)
+	}
+	return resList
+}
@@ -1,13 +1,16 @@
 package controller
 
 import (
-	"database/sql"
-	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
 	"github.com/takumi616/golang-backend-sample/interface/controller/helper"
 	"github.com/takumi616/golang-backend-sample/interface/controller/request"
 	"github.com/takumi616/golang-backend-sample/interface/controller/response"
@@ -16,11 +19,25 @@ import (
 
 type VocabularyController struct {
 	Usecase VocabularyUsecase
+
+	// Maximum size of a request body in bytes
+	MaxBodyBytes int64
+
+	// Maximum number of operations in a batch
+	MaxBatchOperations int
+
+	// Interval of the comments that keep an idle event stream open
+	EventHeartbeat time.Duration
 }
 
-func NewVocabularyController(usecase VocabularyUsecase) *VocabularyController {
+func NewVocabularyController(
+	usecase VocabularyUsecase, maxBodyBytes int64, maxBatchOperations int, eventHeartbeat time.Duration,
+) *VocabularyController {
 	return &VocabularyController{
-		Usecase: usecase,
+		Usecase:            usecase,
+		MaxBodyBytes:       maxBodyBytes,
+		MaxBatchOperations: maxBatchOperations,
+		EventHeartbeat:     eventHeartbeat,
 	}
 }
 
@@ -29,12 +46,9 @@ func (c *VocabularyController) AddVocabulary(w http.ResponseWriter, r *http.Requ
 
 	// Read http request body
 	var req request.VocabularyReq
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := helper.DecodeJSON(w, r, &req, c.MaxBodyBytes); err != nil {
 		slog.ErrorContext(ctx, "failed to read a request body", slog.String("error", err.Error()))
-		helper.WriteResponse(
-			ctx, w, http.StatusBadRequest,
-			response.ErrorRes{Message: "Invalid request format. Failed to parse JSON."},
-		)
+		helper.WriteResponse(ctx, w, err.StatusCode, response.ErrorRes{Message: err.Message})
 		return
 	}
 	defer r.Body.Close()
@@ -54,6 +68,14 @@ func (c *VocabularyController) AddVocabulary(w http.ResponseWriter, r *http.Requ
 
 	// Execute the application layer logic
 	vocabularyNo, err := c.Usecase.AddVocabulary(ctx, vocabulary)
+	if errors.Is(err, domain.ErrVocabularyDuplicate) {
+		helper.WriteResponse(
+			ctx, w, http.StatusConflict,
+			response.ErrorRes{Message: "Failed to add the vocabulary since the title is already registered."},
+		)
+		return
+	}
+
 	if err != nil {
 		helper.WriteResponse(
 			ctx, w, http.StatusInternalServerError,
@@ -82,7 +104,7 @@ func (c *VocabularyController) FetchVocabularyByNo(w http.ResponseWriter, r *htt
 
 	// Execute the application layer logic
 	vocabulary, err := c.Usecase.FetchVocabularyByNo(ctx, int64(vocabularyNo))
-	if errors.Is(err, sql.ErrNoRows) {
+	if errors.Is(err, domain.ErrVocabularyNotFound) {
 		helper.WriteResponse(
 			ctx, w, http.StatusNotFound,
 			response.ErrorRes{Message: "Failed to get the vocabulary since specified data may not be registered."},
@@ -98,6 +120,11 @@ func (c *VocabularyController) FetchVocabularyByNo(w http.ResponseWriter, r *htt
 		return
 	}
 
+	// Let the client reuse its copy when the vocabulary has not been updated
+	if helper.CheckNotModified(w, r, helper.VocabularyETag(vocabulary)) {
+		return
+	}
+
 	// Write a returned result to the response body
 	helper.WriteResponse(ctx, w, http.StatusOK, transformer.ToResponse(vocabulary))
 }
@@ -105,13 +132,29 @@ func (c *VocabularyController) FetchVocabularyByNo(w http.ResponseWriter, r *htt
 func (c *VocabularyController) FetchVocabularyList(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	// Parse the filters and the order of the list
+	query, err := request.ParseVocabularyQuery(r.URL.Query())
+	if err != nil {
+		slog.ErrorContext(ctx, "invalid query parameters", slog.String("error", err.Error()))
+		helper.WriteResponse(
+			ctx, w, http.StatusBadRequest,
+			response.ErrorRes{Message: fmt.Sprintf("Invalid query parameters: %s.", err)},
+		)
+		return
+	}
+
 	// Execute the application layer logic
-	vocabularyList, err := c.Usecase.FetchVocabularyList(ctx)
+	vocabularyList, err := c.Usecase.FetchVocabularyList(ctx, query)
 	if err != nil {
 		helper.WriteResponse(ctx, w, http.StatusInternalServerError, response.ErrorRes{Message: "Failed to get the vocabularies due to a server error."})
 		return
 	}
 
+	// Let the client reuse its copy when no vocabulary has changed
+	if helper.CheckNotModified(w, r, helper.VocabularyListETag(vocabularyList)) {
+		return
+	}
+
 	// Transform the domain model into the response struct
 	var vocabularyResponse []*response.VocabularyRes
 	for _, vocabulary := range vocabularyList {
@@ -138,12 +181,9 @@ func (c *VocabularyController) UpdateVocabulary(w http.ResponseWriter, r *http.R
 
 	// Read http request body
 	var req request.VocabularyReq
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := helper.DecodeJSON(w, r, &req, c.MaxBodyBytes); err != nil {
 		slog.ErrorContext(ctx, "failed to read a request body", slog.String("error", err.Error()))
-		helper.WriteResponse(
-			ctx, w, http.StatusBadRequest,
-			response.ErrorRes{Message: "Invalid request format. Failed to parse JSON."},
-		)
+		helper.WriteResponse(ctx, w, err.StatusCode, response.ErrorRes{Message: err.Message})
 		return
 	}
 	defer r.Body.Close()
@@ -153,7 +193,7 @@ func (c *VocabularyController) UpdateVocabulary(w http.ResponseWriter, r *http.R
 
 	// Execute the application layer logic
 	updated, err := c.Usecase.UpdateVocabulary(ctx, int64(vocabularyNo), vocabulary)
-	if errors.Is(err, sql.ErrNoRows) {
+	if errors.Is(err, domain.ErrVocabularyNotFound) {
 		helper.WriteResponse(
 			ctx, w, http.StatusNotFound,
 			response.ErrorRes{Message: "Failed to update the vocabulary since specified data may not be registered."},
@@ -161,6 +201,14 @@ func (c *VocabularyController) UpdateVocabulary(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	if errors.Is(err, domain.ErrVocabularyDuplicate) {
+		helper.WriteResponse(
+			ctx, w, http.StatusConflict,
+			response.ErrorRes{Message: "Failed to update the vocabulary since the title is already registered."},
+		)
+		return
+	}
+
 	if err != nil {
 		helper.WriteResponse(
 			ctx, w, http.StatusInternalServerError,
@@ -189,7 +237,7 @@ func (c *VocabularyController) DeleteVocabulary(w http.ResponseWriter, r *http.R
 
 	// Execute the application layer logic
 	rowsAffected, err := c.Usecase.DeleteVocabulary(ctx, int64(vocabularyNo))
-	if errors.Is(err, sql.ErrNoRows) {
+	if errors.Is(err, domain.ErrVocabularyNotFound) {
 		helper.WriteResponse(
 			ctx, w, http.StatusNotFound,
 			response.ErrorRes{Message: "Failed to delete the vocabulary since specified data may not be registered."},
@@ -208,3 +256,119 @@ func (c *VocabularyController) DeleteVocabulary(w http.ResponseWriter, r *http.R
 	// Write a returned result to the response body
 	helper.WriteResponse(ctx, w, http.StatusOK, response.RowsAffectedRes{RowsAffected: rowsAffected})
 }
+
+func (c *VocabularyController) ApplyBatch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Read http request body
+	var req request.BatchReq
+	if err := helper.DecodeJSON(w, r, &req, c.MaxBodyBytes); err != nil {
+		slog.ErrorContext(ctx, "failed to read a request body", slog.String("error", err.Error()))
+		helper.WriteResponse(ctx, w, err.StatusCode, response.ErrorRes{Message: err.Message})
+		return
+	}
+	defer r.Body.Close()
+
+	// Validation check
+	if err := req.Validate(c.MaxBatchOperations); err != nil {
+		slog.ErrorContext(ctx, "invalid request parameters", slog.String("error", err.Error()))
+		helper.WriteResponse(
+			ctx, w, http.StatusBadRequest,
+			response.ErrorRes{Message: fmt.Sprintf("Invalid input parameters: %s.", err)},
+		)
+		return
+	}
+
+	// Execute the application layer logic
+	results, err := c.Usecase.ApplyBatch(ctx, transformer.ToBatchOperations(&req), req.Atomic)
+	if err != nil {
+		helper.WriteResponse(
+			ctx, w, http.StatusInternalServerError,
+			response.ErrorRes{Message: "Failed to apply the batch due to a server error."},
+		)
+		return
+	}
+
+	// Write a returned result to the response body
+	res := transformer.ToBatchResponse(&req, results)
+	statusCode := http.StatusOK
+	if !res.Committed {
+		statusCode = http.StatusUnprocessableEntity
+	}
+	helper.WriteResponse(ctx, w, statusCode, res)
+}
+
+// StreamVocabularyEvents streams the changes of the vocabularies as server-sent events until the client disconnects
+func (c *VocabularyController) StreamVocabularyEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Subscribe first so that no event is missed between the replay and the stream
+	subscription, err := c.Usecase.SubscribeVocabularyEvents(r.Header.Get("Last-Event-ID"))
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to subscribe to the vocabulary events", slog.String("error", err.Error()))
+		helper.WriteResponse(
+			ctx, w, http.StatusServiceUnavailable,
+			response.ErrorRes{Message: "The event stream is not available."},
+		)
+		return
+	}
+	defer subscription.Unsubscribe()
+
+	// The stream outlives the read and write timeouts of the server
+	rc := http.NewResponseController(w)
+	for _, err := range []error{rc.SetReadDeadline(time.Time{}), rc.SetWriteDeadline(time.Time{})} {
+		if err != nil && !errors.Is(err, http.ErrNotSupported) {
+			slog.WarnContext(ctx, "failed to clear the deadline of the event stream", slog.String("error", err.Error()))
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+
+	write := func(event usecase.VocabularyEvent) error {
+		return helper.WriteEvent(w, event.ID, string(event.Type), transformer.ToVocabularyEventResponse(event))
+	}
+
+	// Ask the client to reload when the events it missed are no longer kept
+	if !subscription.Complete {
+		if err := helper.WriteEvent(w, subscription.LastEventID, "vocabulary.reset", struct{}{}); err != nil {
+			return
+		}
+	}
+
+	for _, event := range subscription.Replay {
+		if err := write(event); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		slog.ErrorContext(ctx, "the event stream cannot be flushed", slog.String("error", err.Error()))
+		return
+	}
+
+	heartbeat := time.NewTicker(c.EventHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-subscription.Events:
+			// Closed when the client fell behind or the server shuts down. The client resumes with Last-Event-ID.
+			if !ok {
+				return
+			}
+			if err := write(event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
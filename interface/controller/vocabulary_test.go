@@ -0,0 +1,603 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+	"github.com/takumi616/golang-backend-sample/interface/controller/response"
+)
+
+var errServer = errors.New("server error")
+
+// Attribution of the vocabularies returned by the fake usecase, and how it is written in the responses
+var (
+	createdAt = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	updatedAt = time.Date(2024, 1, 2, 4, 4, 5, 500_000_000, time.FixedZone("JST", 9*60*60))
+)
+
+const attributionJSON = `"created_at":"2024-01-02T03:04:05Z","updated_at":"2024-01-01T19:04:05.5Z","created_by":"alice","updated_by":"bob"`
+
+type fakeVocabularyUsecase struct {
+	addVocabulary       func(ctx context.Context, vocabulary *domain.Vocabulary) (int64, error)
+	fetchVocabularyByNo func(ctx context.Context, vocabularyNo int64) (*domain.Vocabulary, error)
+	fetchVocabularyList func(ctx context.Context, query usecase.VocabularyQuery) ([]*domain.Vocabulary, error)
+	updateVocabulary    func(ctx context.Context, vocabularyNo int64, vocabulary *domain.Vocabulary) (int64, error)
+	deleteVocabulary    func(ctx context.Context, vocabularyNo int64) (int64, error)
+	applyBatch          func(ctx context.Context, operations []usecase.BatchOperation, atomic bool) ([]usecase.BatchResult, error)
+	subscribe           func(lastEventID string) (*usecase.VocabularySubscription, error)
+}
+
+func (f *fakeVocabularyUsecase) AddVocabulary(ctx context.Context, vocabulary *domain.Vocabulary) (int64, error) {
+	return f.addVocabulary(ctx, vocabulary)
+}
+
+func (f *fakeVocabularyUsecase) FetchVocabularyByNo(ctx context.Context, vocabularyNo int64) (*domain.Vocabulary, error) {
+	return f.fetchVocabularyByNo(ctx, vocabularyNo)
+}
+
+func (f *fakeVocabularyUsecase) FetchVocabularyList(ctx context.Context, query usecase.VocabularyQuery) ([]*domain.Vocabulary, error) {
+	return f.fetchVocabularyList(ctx, query)
+}
+
+func (f *fakeVocabularyUsecase) UpdateVocabulary(ctx context.Context, vocabularyNo int64, vocabulary *domain.Vocabulary) (int64, error) {
+	return f.updateVocabulary(ctx, vocabularyNo, vocabulary)
+}
+
+func (f *fakeVocabularyUsecase) DeleteVocabulary(ctx context.Context, vocabularyNo int64) (int64, error) {
+	return f.deleteVocabulary(ctx, vocabularyNo)
+}
+
+func (f *fakeVocabularyUsecase) ApplyBatch(
+	ctx context.Context, operations []usecase.BatchOperation, atomic bool,
+) ([]usecase.BatchResult, error) {
+	return f.applyBatch(ctx, operations, atomic)
+}
+
+func (f *fakeVocabularyUsecase) SubscribeVocabularyEvents(lastEventID string) (*usecase.VocabularySubscription, error) {
+	return f.subscribe(lastEventID)
+}
+
+func newRequest(method, vocabularyNo, body string) *http.Request {
+	req := httptest.NewRequest(method, "/api/vocabularies", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if vocabularyNo != "" {
+		req.SetPathValue("vocabularyNo", vocabularyNo)
+	}
+	return req
+}
+
+func checkResponse(t *testing.T, rec *httptest.ResponseRecorder, wantStatus int, wantBody string) {
+	t.Helper()
+
+	if rec.Code != wantStatus {
+		t.Errorf("status = %d, want %d", rec.Code, wantStatus)
+	}
+
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+
+	if got := strings.TrimSpace(rec.Body.String()); got != wantBody {
+		t.Errorf("body = %s, want %s", got, wantBody)
+	}
+}
+
+func errorBody(t *testing.T, message string) string {
+	t.Helper()
+
+	body, err := json.Marshal(response.ErrorRes{Message: message})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestAddVocabulary(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		addErr     error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "created",
+			body:       `{"title":" apple ","meaning":"fruit","sentence":"I ate an apple."}`,
+			wantStatus: http.StatusCreated,
+			wantBody:   `{"vocabulary_no":1}`,
+		},
+		{
+			name:       "invalid json",
+			body:       `{"title":`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   errorBody(t, "Invalid request format. Failed to parse JSON."),
+		},
+		{
+			name:       "validation error",
+			body:       `{"title":"","meaning":"fruit","sentence":"I ate an apple."}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   errorBody(t, "Invalid input parameters. Please check your request."),
+		},
+		{
+			name:       "duplicate",
+			body:       `{"title":"apple","meaning":"fruit","sentence":"I ate an apple."}`,
+			addErr:     domain.ErrVocabularyDuplicate,
+			wantStatus: http.StatusConflict,
+			wantBody:   errorBody(t, "Failed to add the vocabulary since the title is already registered."),
+		},
+		{
+			name:       "server error",
+			body:       `{"title":"apple","meaning":"fruit","sentence":"I ate an apple."}`,
+			addErr:     errServer,
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   errorBody(t, "Failed to add the vocabulary due to a server error."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := &fakeVocabularyUsecase{
+				addVocabulary: func(ctx context.Context, vocabulary *domain.Vocabulary) (int64, error) {
+					// The request body is trimmed before it reaches the usecase
+					if vocabulary.Title != "apple" {
+						t.Errorf("title = %q, want apple", vocabulary.Title)
+					}
+					if tt.addErr != nil {
+						return 0, tt.addErr
+					}
+					return 1, nil
+				},
+			}
+
+			rec := httptest.NewRecorder()
+			NewVocabularyController(usecase, 1024, 10, time.Minute).AddVocabulary(rec, newRequest(http.MethodPost, "", tt.body))
+			checkResponse(t, rec, tt.wantStatus, tt.wantBody)
+		})
+	}
+}
+
+func TestFetchVocabularyByNo(t *testing.T) {
+	tests := []struct {
+		name         string
+		vocabularyNo string
+		fetchErr     error
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name:         "ok",
+			vocabularyNo: "1",
+			wantStatus:   http.StatusOK,
+			wantBody:     `{"vocabulary_no":1,"title":"apple","meaning":"fruit","sentence":"I ate an apple.",` + attributionJSON + `}`,
+		},
+		{
+			name:         "invalid path value",
+			vocabularyNo: "abc",
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     errorBody(t, "Invalid request path value. Please check your http request path."),
+		},
+		{
+			name:         "not found",
+			vocabularyNo: "1",
+			fetchErr:     domain.ErrVocabularyNotFound,
+			wantStatus:   http.StatusNotFound,
+			wantBody:     errorBody(t, "Failed to get the vocabulary since specified data may not be registered."),
+		},
+		{
+			name:         "server error",
+			vocabularyNo: "1",
+			fetchErr:     errServer,
+			wantStatus:   http.StatusInternalServerError,
+			wantBody:     errorBody(t, "Failed to get the vocabulary due to a server error."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := &fakeVocabularyUsecase{
+				fetchVocabularyByNo: func(ctx context.Context, vocabularyNo int64) (*domain.Vocabulary, error) {
+					if tt.fetchErr != nil {
+						return nil, tt.fetchErr
+					}
+					return &domain.Vocabulary{
+						VocabularyNo: vocabularyNo, Title: "apple", Meaning: "fruit", Sentence: "I ate an apple.",
+						CreatedAt: createdAt, UpdatedAt: updatedAt, CreatedBy: "alice", UpdatedBy: "bob",
+					}, nil
+				},
+			}
+
+			rec := httptest.NewRecorder()
+			NewVocabularyController(usecase, 1024, 10, time.Minute).FetchVocabularyByNo(rec, newRequest(http.MethodGet, tt.vocabularyNo, ""))
+			checkResponse(t, rec, tt.wantStatus, tt.wantBody)
+		})
+	}
+}
+
+func TestFetchVocabularyByNoNotModified(t *testing.T) {
+	usecase := &fakeVocabularyUsecase{
+		fetchVocabularyByNo: func(ctx context.Context, vocabularyNo int64) (*domain.Vocabulary, error) {
+			return &domain.Vocabulary{VocabularyNo: vocabularyNo, Title: "apple", Version: 3}, nil
+		},
+	}
+	controller := NewVocabularyController(usecase, 1024, 10, time.Minute)
+
+	// The first response carries the ETag
+	rec := httptest.NewRecorder()
+	controller.FetchVocabularyByNo(rec, newRequest(http.MethodGet, "1", ""))
+	etag := rec.Header().Get("ETag")
+	if rec.Code != http.StatusOK || etag != `"1-3"` {
+		t.Fatalf("status = %d, ETag = %s, want %d and \"1-3\"", rec.Code, etag, http.StatusOK)
+	}
+
+	// A conditional request with the same ETag gets no body
+	req := newRequest(http.MethodGet, "1", "")
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	controller.FetchVocabularyByNo(rec, req)
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("status = %d with %d bytes of body, want %d without a body", rec.Code, rec.Body.Len(), http.StatusNotModified)
+	}
+}
+
+func TestFetchVocabularyList(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantQuery  usecase.VocabularyQuery
+		list       []*domain.Vocabulary
+		fetchErr   error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "ok",
+			list: []*domain.Vocabulary{
+				{
+					VocabularyNo: 1, Title: "apple", Meaning: "fruit", Sentence: "I ate an apple.",
+					CreatedAt: createdAt, UpdatedAt: updatedAt, CreatedBy: "alice", UpdatedBy: "bob",
+				},
+				{
+					VocabularyNo: 2, Title: "run", Meaning: "move fast", Sentence: "I run every day.",
+					CreatedAt: createdAt, UpdatedAt: updatedAt, CreatedBy: "alice", UpdatedBy: "bob",
+				},
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `[{"vocabulary_no":1,"title":"apple","meaning":"fruit","sentence":"I ate an apple.",` + attributionJSON + `},` +
+				`{"vocabulary_no":2,"title":"run","meaning":"move fast","sentence":"I run every day.",` + attributionJSON + `}]`,
+		},
+		{
+			name:      "filtered and sorted",
+			query:     "title_prefix=ap&sort=-title",
+			wantQuery: usecase.VocabularyQuery{TitlePrefix: "ap", Sort: []usecase.VocabularySort{{Field: usecase.SortByTitle, Descending: true}}},
+			list: []*domain.Vocabulary{{
+				VocabularyNo: 1, Title: "apple", Meaning: "fruit", Sentence: "I ate an apple.",
+				CreatedAt: createdAt, UpdatedAt: updatedAt, CreatedBy: "alice", UpdatedBy: "bob",
+			}},
+			wantStatus: http.StatusOK,
+			wantBody:   `[{"vocabulary_no":1,"title":"apple","meaning":"fruit","sentence":"I ate an apple.",` + attributionJSON + `}]`,
+		},
+		{
+			name:       "unknown query parameter",
+			query:      "title=apple",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   errorBody(t, `Invalid query parameters: unknown query parameter "title".`),
+		},
+		{
+			name:       "server error",
+			fetchErr:   errServer,
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   errorBody(t, "Failed to get the vocabularies due to a server error."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := &fakeVocabularyUsecase{
+				fetchVocabularyList: func(ctx context.Context, query usecase.VocabularyQuery) ([]*domain.Vocabulary, error) {
+					if !reflect.DeepEqual(query, tt.wantQuery) {
+						t.Errorf("query = %+v, want %+v", query, tt.wantQuery)
+					}
+					return tt.list, tt.fetchErr
+				},
+			}
+
+			rec := httptest.NewRecorder()
+			req := newRequest(http.MethodGet, "", "")
+			req.URL.RawQuery = tt.query
+			NewVocabularyController(usecase, 1024, 10, time.Minute).FetchVocabularyList(rec, req)
+			checkResponse(t, rec, tt.wantStatus, tt.wantBody)
+		})
+	}
+}
+
+func TestUpdateVocabulary(t *testing.T) {
+	tests := []struct {
+		name         string
+		vocabularyNo string
+		body         string
+		updateErr    error
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name:         "ok",
+			vocabularyNo: "3",
+			body:         `{"title":"apple","meaning":"fruit","sentence":"I ate an apple."}`,
+			wantStatus:   http.StatusOK,
+			wantBody:     `{"vocabulary_no":3}`,
+		},
+		{
+			name:         "invalid path value",
+			vocabularyNo: "abc",
+			body:         `{"title":"apple","meaning":"fruit","sentence":"I ate an apple."}`,
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     errorBody(t, "Invalid request path value. Please check your http request path."),
+		},
+		{
+			name:         "invalid json",
+			vocabularyNo: "3",
+			body:         `not json`,
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     errorBody(t, "Invalid request format. Failed to parse JSON."),
+		},
+		{
+			name:         "not found",
+			vocabularyNo: "3",
+			body:         `{"title":"apple","meaning":"fruit","sentence":"I ate an apple."}`,
+			updateErr:    domain.ErrVocabularyNotFound,
+			wantStatus:   http.StatusNotFound,
+			wantBody:     errorBody(t, "Failed to update the vocabulary since specified data may not be registered."),
+		},
+		{
+			name:         "duplicate",
+			vocabularyNo: "3",
+			body:         `{"title":"apple","meaning":"fruit","sentence":"I ate an apple."}`,
+			updateErr:    domain.ErrVocabularyDuplicate,
+			wantStatus:   http.StatusConflict,
+			wantBody:     errorBody(t, "Failed to update the vocabulary since the title is already registered."),
+		},
+		{
+			name:         "server error",
+			vocabularyNo: "3",
+			body:         `{"title":"apple","meaning":"fruit","sentence":"I ate an apple."}`,
+			updateErr:    errServer,
+			wantStatus:   http.StatusInternalServerError,
+			wantBody:     errorBody(t, "Failed to update the vocabulary due to a server error."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := &fakeVocabularyUsecase{
+				updateVocabulary: func(ctx context.Context, vocabularyNo int64, vocabulary *domain.Vocabulary) (int64, error) {
+					if tt.updateErr != nil {
+						return 0, tt.updateErr
+					}
+					return vocabularyNo, nil
+				},
+			}
+
+			rec := httptest.NewRecorder()
+			NewVocabularyController(usecase, 1024, 10, time.Minute).UpdateVocabulary(rec, newRequest(http.MethodPut, tt.vocabularyNo, tt.body))
+			checkResponse(t, rec, tt.wantStatus, tt.wantBody)
+		})
+	}
+}
+
+func TestDeleteVocabulary(t *testing.T) {
+	tests := []struct {
+		name         string
+		vocabularyNo string
+		deleteErr    error
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name:         "ok",
+			vocabularyNo: "1",
+			wantStatus:   http.StatusOK,
+			wantBody:     `{"rows_affected":1}`,
+		},
+		{
+			name:         "invalid path value",
+			vocabularyNo: "abc",
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     errorBody(t, "Invalid request path value. Please check your http request path."),
+		},
+		{
+			name:         "not found",
+			vocabularyNo: "1",
+			deleteErr:    domain.ErrVocabularyNotFound,
+			wantStatus:   http.StatusNotFound,
+			wantBody:     errorBody(t, "Failed to delete the vocabulary since specified data may not be registered."),
+		},
+		{
+			name:         "server error",
+			vocabularyNo: "1",
+			deleteErr:    errServer,
+			wantStatus:   http.StatusInternalServerError,
+			wantBody:     errorBody(t, "Failed to delete the vocabulary due to a server error."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := &fakeVocabularyUsecase{
+				deleteVocabulary: func(ctx context.Context, vocabularyNo int64) (int64, error) {
+					if tt.deleteErr != nil {
+						return 0, tt.deleteErr
+					}
+					return 1, nil
+				},
+			}
+
+			rec := httptest.NewRecorder()
+			NewVocabularyController(usecase, 1024, 10, time.Minute).DeleteVocabulary(rec, newRequest(http.MethodDelete, tt.vocabularyNo, ""))
+			checkResponse(t, rec, tt.wantStatus, tt.wantBody)
+		})
+	}
+}
+
+func TestApplyBatch(t *testing.T) {
+	const operations = `[
+		{"op":"create","vocabulary":{"title":"apple","meaning":"fruit","sentence":"I ate an apple."}},
+		{"op":"update","vocabulary_no":2,"vocabulary":{"title":"pear","meaning":"fruit","sentence":"I ate a pear."}},
+		{"op":"delete","vocabulary_no":3}
+	]`
+
+	tests := []struct {
+		name       string
+		body       string
+		results    []usecase.BatchResult
+		batchErr   error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "committed",
+			body:       `{"operations":` + operations + `}`,
+			results:    []usecase.BatchResult{{VocabularyNo: 1}, {VocabularyNo: 2}, {Err: domain.ErrVocabularyNotFound}},
+			wantStatus: http.StatusOK,
+			wantBody: `{"committed":true,"results":[` +
+				`{"index":0,"op":"create","status":201,"vocabulary_no":1},` +
+				`{"index":1,"op":"update","status":200,"vocabulary_no":2},` +
+				`{"index":2,"op":"delete","status":404,"error":"The vocabulary is not registered."}]}`,
+		},
+		{
+			name: "rolled back",
+			body: `{"atomic":true,"operations":` + operations + `}`,
+			results: []usecase.BatchResult{
+				{Err: usecase.ErrBatchAborted}, {Err: domain.ErrVocabularyDuplicate}, {Err: usecase.ErrBatchAborted},
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody: `{"committed":false,"results":[` +
+				`{"index":0,"op":"create","status":424,"error":"Not applied since another operation of the batch failed."},` +
+				`{"index":1,"op":"update","status":409,"error":"The title is already registered."},` +
+				`{"index":2,"op":"delete","status":424,"error":"Not applied since another operation of the batch failed."}]}`,
+		},
+		{
+			name:       "no operations",
+			body:       `{"operations":[]}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   errorBody(t, "Invalid input parameters: operations are required."),
+		},
+		{
+			name:       "too many operations",
+			body:       `{"operations":[` + strings.Repeat(`{"op":"delete","vocabulary_no":1},`, 10) + `{"op":"delete","vocabulary_no":1}]}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   errorBody(t, "Invalid input parameters: a batch must have 10 operations or fewer, got 11."),
+		},
+		{
+			name:       "invalid operation",
+			body:       `{"operations":[{"op":"delete","vocabulary_no":1},{"op":"create","vocabulary":{"title":"","meaning":"m","sentence":"s"}}]}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   errorBody(t, "Invalid input parameters: operation 1: title is required."),
+		},
+		{
+			name:       "server error",
+			body:       `{"operations":` + operations + `}`,
+			batchErr:   errServer,
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   errorBody(t, "Failed to apply the batch due to a server error."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := &fakeVocabularyUsecase{
+				applyBatch: func(ctx context.Context, operations []usecase.BatchOperation, atomic bool) ([]usecase.BatchResult, error) {
+					if len(operations) != 3 || operations[1].VocabularyNo != 2 || operations[1].Vocabulary.Title != "pear" {
+						t.Errorf("operations = %+v", operations)
+					}
+					return tt.results, tt.batchErr
+				},
+			}
+
+			rec := httptest.NewRecorder()
+			NewVocabularyController(usecase, 1024, 10, time.Minute).ApplyBatch(rec, newRequest(http.MethodPost, "", tt.body))
+			checkResponse(t, rec, tt.wantStatus, tt.wantBody)
+		})
+	}
+}
+
+func TestStreamVocabularyEvents(t *testing.T) {
+	occurredAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	created := usecase.VocabularyEvent{
+		ID: "e-1", Type: usecase.VocabularyCreated, VocabularyNo: 1, Actor: "alice", OccurredAt: occurredAt,
+		Vocabulary: &domain.Vocabulary{Title: "apple", Meaning: "fruit", Sentence: "s"},
+	}
+	deleted := usecase.VocabularyEvent{ID: "e-2", Type: usecase.VocabularyDeleted, VocabularyNo: 1, Actor: "bob", OccurredAt: occurredAt}
+
+	const (
+		createdEvent = "id: e-1\nevent: vocabulary.created\n" +
+			`data: {"vocabulary_no":1,"title":"apple","meaning":"fruit","sentence":"s","actor":"alice","occurred_at":"2024-01-02T03:04:05Z"}` + "\n\n"
+		deletedEvent = "id: e-2\nevent: vocabulary.deleted\n" +
+			`data: {"vocabulary_no":1,"actor":"bob","occurred_at":"2024-01-02T03:04:05Z"}` + "\n\n"
+	)
+
+	tests := []struct {
+		name         string
+		lastEventID  string
+		subscription *usecase.VocabularySubscription
+		want         string
+	}{
+		{
+			name:         "replay and new events",
+			lastEventID:  "e-0",
+			subscription: &usecase.VocabularySubscription{Complete: true, Replay: []usecase.VocabularyEvent{created}},
+			want:         createdEvent + deletedEvent,
+		},
+		{
+			name:         "missed events are no longer kept",
+			lastEventID:  "old",
+			subscription: &usecase.VocabularySubscription{LastEventID: "e-1"},
+			want:         "id: e-1\nevent: vocabulary.reset\ndata: {}\n\n" + deletedEvent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The stream ends when the subscription is closed
+			events := make(chan usecase.VocabularyEvent, 1)
+			events <- deleted
+			close(events)
+			tt.subscription.Events = events
+
+			unsubscribed := false
+			tt.subscription.Unsubscribe = func() { unsubscribed = true }
+
+			usecase := &fakeVocabularyUsecase{
+				subscribe: func(lastEventID string) (*usecase.VocabularySubscription, error) {
+					if lastEventID != tt.lastEventID {
+						t.Errorf("lastEventID = %q, want %q", lastEventID, tt.lastEventID)
+					}
+					return tt.subscription, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/vocabularies/events", nil)
+			req.Header.Set("Last-Event-ID", tt.lastEventID)
+			rec := httptest.NewRecorder()
+			NewVocabularyController(usecase, 1024, 10, time.Minute).StreamVocabularyEvents(rec, req)
+
+			if got := rec.Header().Get("Content-Type"); got != "text/event-stream" {
+				t.Errorf("Content-Type = %q, want text/event-stream", got)
+			}
+			if got := rec.Body.String(); got != tt.want {
+				t.Errorf("body = %q, want %q", got, tt.want)
+			}
+			if !unsubscribed {
+				t.Error("the subscription was not released")
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package controller
 import (
 	"context"
 
+	"github.com/takumi616/golang-backend-sample/application/usecase"
 	"github.com/takumi616/golang-backend-sample/domain"
 )
 
@@ -11,9 +12,13 @@ type VocabularyUsecase interface {
 
 	FetchVocabularyByNo(ctx context.Context, vocabularyNo int64) (*domain.Vocabulary, error)
 
-	FetchVocabularyList(ctx context.Context) ([]*domain.Vocabulary, error)
+	FetchVocabularyList(ctx context.Context, query usecase.VocabularyQuery) ([]*domain.Vocabulary, error)
 
 	UpdateVocabulary(ctx context.Context, vocabularyNo int64, vocabulary *domain.Vocabulary) (int64, error)
 
 	DeleteVocabulary(ctx context.Context, vocabularyNo int64) (int64, error)
+
+	ApplyBatch(ctx context.Context, operations []usecase.BatchOperation, atomic bool) ([]usecase.BatchResult, error)
+
+	SubscribeVocabularyEvents(lastEventID string) (*usecase.VocabularySubscription, error)
 }
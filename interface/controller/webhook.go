@@ -0,0 +1,182 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+	"github.com/takumi616/golang-backend-sample/interface/controller/helper"
+	"github.com/takumi616/golang-backend-sample/interface/controller/request"
+	"github.com/takumi616/golang-backend-sample/interface/controller/response"
+	"github.com/takumi616/golang-backend-sample/interface/controller/transformer"
+)
+
+type WebhookController struct {
+	Usecase WebhookUsecase
+
+	// Maximum size of a request body in bytes
+	MaxBodyBytes int64
+}
+
+func NewWebhookController(usecase WebhookUsecase, maxBodyBytes int64) *WebhookController {
+	return &WebhookController{
+		Usecase:      usecase,
+		MaxBodyBytes: maxBodyBytes,
+	}
+}
+
+func (c *WebhookController) AddWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Read http request body
+	var req request.WebhookReq
+	if err := helper.DecodeJSON(w, r, &req, c.MaxBodyBytes); err != nil {
+		slog.ErrorContext(ctx, "failed to read a request body", slog.String("error", err.Error()))
+		helper.WriteResponse(ctx, w, err.StatusCode, response.ErrorRes{Message: err.Message})
+		return
+	}
+	defer r.Body.Close()
+
+	// Validation check
+	if err := req.Validate(); err != nil {
+		slog.ErrorContext(ctx, "invalid request parameters", slog.String("error", err.Error()))
+		helper.WriteResponse(
+			ctx, w, http.StatusBadRequest,
+			response.ErrorRes{Message: fmt.Sprintf("Invalid input parameters: %s.", err)},
+		)
+		return
+	}
+
+	// Execute the application layer logic
+	webhookID, err := c.Usecase.AddWebhook(ctx, transformer.ToWebhook(&req))
+	if err != nil {
+		writeWebhookError(ctx, w, err, "Failed to add the webhook due to a server error.")
+		return
+	}
+
+	// Write a returned result to the response body
+	helper.WriteResponse(ctx, w, http.StatusCreated, response.WebhookIDRes{WebhookID: webhookID})
+}
+
+func (c *WebhookController) FetchWebhookList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Execute the application layer logic
+	webhooks, err := c.Usecase.FetchWebhookList(ctx)
+	if err != nil {
+		writeWebhookError(ctx, w, err, "Failed to get the webhooks due to a server error.")
+		return
+	}
+
+	// Write a returned result to the response body
+	helper.WriteResponse(ctx, w, http.StatusOK, transformer.ToWebhookResponseList(webhooks))
+}
+
+func (c *WebhookController) FetchWebhookByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Get the webhookID from the request path
+	webhookID, ok := webhookIDFrom(w, r)
+	if !ok {
+		return
+	}
+
+	// Execute the application layer logic
+	webhook, err := c.Usecase.FetchWebhookByID(ctx, webhookID)
+	if err != nil {
+		writeWebhookError(ctx, w, err, "Failed to get the webhook due to a server error.")
+		return
+	}
+
+	// Write a returned result to the response body
+	helper.WriteResponse(ctx, w, http.StatusOK, transformer.ToWebhookResponse(webhook))
+}
+
+func (c *WebhookController) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Get the webhookID from the request path
+	webhookID, ok := webhookIDFrom(w, r)
+	if !ok {
+		return
+	}
+
+	// Execute the application layer logic
+	rowsAffected, err := c.Usecase.DeleteWebhook(ctx, webhookID)
+	if err != nil {
+		writeWebhookError(ctx, w, err, "Failed to delete the webhook due to a server error.")
+		return
+	}
+
+	// Write a returned result to the response body
+	helper.WriteResponse(ctx, w, http.StatusOK, response.RowsAffectedRes{RowsAffected: rowsAffected})
+}
+
+// FetchDeliveryList returns the delivery log of a webhook, newest first
+func (c *WebhookController) FetchDeliveryList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Get the webhookID from the request path
+	webhookID, ok := webhookIDFrom(w, r)
+	if !ok {
+		return
+	}
+
+	// Parse the filter and the limit of the log
+	query, err := request.ParseDeliveryQuery(r.URL.Query())
+	if err != nil {
+		slog.ErrorContext(ctx, "invalid query parameters", slog.String("error", err.Error()))
+		helper.WriteResponse(
+			ctx, w, http.StatusBadRequest,
+			response.ErrorRes{Message: fmt.Sprintf("Invalid query parameters: %s.", err)},
+		)
+		return
+	}
+
+	// Execute the application layer logic
+	deliveries, err := c.Usecase.FetchDeliveryList(ctx, webhookID, query.Status, query.Limit)
+	if err != nil {
+		writeWebhookError(ctx, w, err, "Failed to get the webhook deliveries due to a server error.")
+		return
+	}
+
+	// Write a returned result to the response body
+	helper.WriteResponse(ctx, w, http.StatusOK, transformer.ToDeliveryResponseList(deliveries))
+}
+
+// webhookIDFrom parses the webhookID of the request path, and responds with 400 when it is invalid
+func webhookIDFrom(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	webhookID, err := strconv.ParseInt(r.PathValue("webhookID"), 10, 64)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "invalid path value", slog.String("error", err.Error()))
+		helper.WriteResponse(
+			r.Context(), w, http.StatusBadRequest,
+			response.ErrorRes{Message: "Invalid request path value. Please check your http request path."},
+		)
+		return 0, false
+	}
+	return webhookID, true
+}
+
+// writeWebhookError responds with the status of a usecase error, or 500 with serverErrorMessage
+func writeWebhookError(ctx context.Context, w http.ResponseWriter, err error, serverErrorMessage string) {
+	switch {
+	case errors.Is(err, domain.ErrWebhookNotFound):
+		helper.WriteResponse(
+			ctx, w, http.StatusNotFound,
+			response.ErrorRes{Message: "The webhook is not registered."},
+		)
+	case errors.Is(err, usecase.ErrWebhooksDisabled):
+		helper.WriteResponse(
+			ctx, w, http.StatusServiceUnavailable,
+			response.ErrorRes{Message: "Webhooks are only available with the PostgreSQL backend."},
+		)
+	default:
+		helper.WriteResponse(ctx, w, http.StatusInternalServerError, response.ErrorRes{Message: serverErrorMessage})
+	}
+}
@@ -0,0 +1,207 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+)
+
+type fakeWebhookUsecase struct {
+	addWebhook        func(ctx context.Context, webhook *domain.Webhook) (int64, error)
+	fetchWebhookByID  func(ctx context.Context, webhookID int64) (*domain.Webhook, error)
+	fetchWebhookList  func(ctx context.Context) ([]*domain.Webhook, error)
+	deleteWebhook     func(ctx context.Context, webhookID int64) (int64, error)
+	fetchDeliveryList func(ctx context.Context, webhookID int64, status usecase.WebhookDeliveryStatus, limit int) ([]*usecase.WebhookDelivery, error)
+}
+
+func (f *fakeWebhookUsecase) AddWebhook(ctx context.Context, webhook *domain.Webhook) (int64, error) {
+	return f.addWebhook(ctx, webhook)
+}
+
+func (f *fakeWebhookUsecase) FetchWebhookByID(ctx context.Context, webhookID int64) (*domain.Webhook, error) {
+	return f.fetchWebhookByID(ctx, webhookID)
+}
+
+func (f *fakeWebhookUsecase) FetchWebhookList(ctx context.Context) ([]*domain.Webhook, error) {
+	return f.fetchWebhookList(ctx)
+}
+
+func (f *fakeWebhookUsecase) DeleteWebhook(ctx context.Context, webhookID int64) (int64, error) {
+	return f.deleteWebhook(ctx, webhookID)
+}
+
+func (f *fakeWebhookUsecase) FetchDeliveryList(
+	ctx context.Context, webhookID int64, status usecase.WebhookDeliveryStatus, limit int,
+) ([]*usecase.WebhookDelivery, error) {
+	return f.fetchDeliveryList(ctx, webhookID, status, limit)
+}
+
+func newWebhookRequest(method, webhookID, target, body string) *http.Request {
+	req := newRequest(method, "", body)
+	req.URL.RawQuery = target
+	if webhookID != "" {
+		req.SetPathValue("webhookID", webhookID)
+	}
+	return req
+}
+
+func TestAddWebhook(t *testing.T) {
+	const validBody = `{"url":"https://example.com/hook","secret":"0123456789abcdef","event_types":["vocabulary.created"]}`
+
+	tests := []struct {
+		name       string
+		body       string
+		addErr     error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "created",
+			body:       validBody,
+			wantStatus: http.StatusCreated,
+			wantBody:   `{"webhook_id":1}`,
+		},
+		{
+			name:       "validation error",
+			body:       `{"url":"https://example.com/hook","secret":"short","event_types":["vocabulary.created"]}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   errorBody(t, "Invalid input parameters: secret must be 16 to 256 characters."),
+		},
+		{
+			name:       "disabled",
+			body:       validBody,
+			addErr:     usecase.ErrWebhooksDisabled,
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   errorBody(t, "Webhooks are only available with the PostgreSQL backend."),
+		},
+		{
+			name:       "server error",
+			body:       validBody,
+			addErr:     errServer,
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   errorBody(t, "Failed to add the webhook due to a server error."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := &fakeWebhookUsecase{
+				addWebhook: func(ctx context.Context, webhook *domain.Webhook) (int64, error) {
+					if tt.addErr != nil {
+						return 0, tt.addErr
+					}
+					return 1, nil
+				},
+			}
+
+			rec := httptest.NewRecorder()
+			NewWebhookController(usecase, 1024).AddWebhook(rec, newWebhookRequest(http.MethodPost, "", "", tt.body))
+			checkResponse(t, rec, tt.wantStatus, tt.wantBody)
+		})
+	}
+}
+
+func TestFetchWebhookByID(t *testing.T) {
+	tests := []struct {
+		name       string
+		webhookID  string
+		fetchErr   error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			// The secret is never written to the response
+			name:       "ok",
+			webhookID:  "1",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"webhook_id":1,"url":"https://example.com/hook","event_types":["vocabulary.created"],"created_at":"2024-01-02T03:04:05Z"}`,
+		},
+		{
+			name:       "invalid path value",
+			webhookID:  "abc",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   errorBody(t, "Invalid request path value. Please check your http request path."),
+		},
+		{
+			name:       "not found",
+			webhookID:  "1",
+			fetchErr:   domain.ErrWebhookNotFound,
+			wantStatus: http.StatusNotFound,
+			wantBody:   errorBody(t, "The webhook is not registered."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := &fakeWebhookUsecase{
+				fetchWebhookByID: func(ctx context.Context, webhookID int64) (*domain.Webhook, error) {
+					if tt.fetchErr != nil {
+						return nil, tt.fetchErr
+					}
+					return &domain.Webhook{
+						WebhookID: webhookID, URL: "https://example.com/hook", Secret: "0123456789abcdef",
+						EventTypes: []string{"vocabulary.created"}, CreatedAt: createdAt,
+					}, nil
+				},
+			}
+
+			rec := httptest.NewRecorder()
+			NewWebhookController(usecase, 1024).FetchWebhookByID(rec, newWebhookRequest(http.MethodGet, tt.webhookID, "", ""))
+			checkResponse(t, rec, tt.wantStatus, tt.wantBody)
+		})
+	}
+}
+
+func TestFetchDeliveryList(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "ok",
+			query:      "status=pending&limit=10",
+			wantStatus: http.StatusOK,
+			wantBody: `[{"delivery_id":3,"event_type":"vocabulary.deleted","vocabulary_no":2,"status":"pending","attempts":1,` +
+				`"next_attempt_at":"2024-01-02T03:04:05Z","last_error":"boom","created_at":"2024-01-02T03:04:05Z"}]`,
+		},
+		{
+			name:       "invalid query",
+			query:      "status=failed",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   errorBody(t, `Invalid query parameters: status must be pending, delivered or dead, got "failed".`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeWebhookUsecase{
+				fetchDeliveryList: func(
+					ctx context.Context, webhookID int64, status usecase.WebhookDeliveryStatus, limit int,
+				) ([]*usecase.WebhookDelivery, error) {
+					if webhookID != 1 || status != usecase.DeliveryPending || limit != 10 {
+						t.Errorf("FetchDeliveryList(%d, %q, %d), want (1, pending, 10)", webhookID, status, limit)
+					}
+					return []*usecase.WebhookDelivery{{
+						DeliveryID:    3,
+						Event:         usecase.VocabularyEvent{Type: usecase.VocabularyDeleted, VocabularyNo: 2},
+						Status:        usecase.DeliveryPending,
+						Attempts:      1,
+						NextAttemptAt: createdAt,
+						LastError:     "boom",
+						CreatedAt:     createdAt,
+					}}, nil
+				},
+			}
+
+			rec := httptest.NewRecorder()
+			NewWebhookController(fake, 1024).FetchDeliveryList(rec, newWebhookRequest(http.MethodGet, "1", tt.query, ""))
+			checkResponse(t, rec, tt.wantStatus, tt.wantBody)
+		})
+	}
+}
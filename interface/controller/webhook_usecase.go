@@ -0,0 +1,22 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+)
+
+type WebhookUsecase interface {
+	AddWebhook(ctx context.Context, webhook *domain.Webhook) (int64, error)
+
+	FetchWebhookByID(ctx context.Context, webhookID int64) (*domain.Webhook, error)
+
+	FetchWebhookList(ctx context.Context) ([]*domain.Webhook, error)
+
+	DeleteWebhook(ctx context.Context, webhookID int64) (int64, error)
+
+	FetchDeliveryList(
+		ctx context.Context, webhookID int64, status usecase.WebhookDeliveryStatus, limit int,
+	) ([]*usecase.WebhookDelivery, error)
+}
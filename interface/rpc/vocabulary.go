@@ -0,0 +1,183 @@
+// Package rpc serves the vocabularies over gRPC on top of the same usecase as the REST controllers.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/domain"
+	"github.com/takumi616/golang-backend-sample/interface/controller"
+	"github.com/takumi616/golang-backend-sample/interface/controller/request"
+	"github.com/takumi616/golang-backend-sample/interface/rpc/vocabularyv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Scopes maps each method of VocabularyService to the scope an API key needs for it, as on the REST routes
+var Scopes = map[string]domain.Scope{
+	vocabularyv1.VocabularyService_Create_FullMethodName: domain.ScopeVocabWrite,
+	vocabularyv1.VocabularyService_Get_FullMethodName:    domain.ScopeVocabRead,
+	vocabularyv1.VocabularyService_List_FullMethodName:   domain.ScopeVocabRead,
+	vocabularyv1.VocabularyService_Update_FullMethodName: domain.ScopeVocabWrite,
+	vocabularyv1.VocabularyService_Delete_FullMethodName: domain.ScopeVocabWrite,
+}
+
+type VocabularyService struct {
+	vocabularyv1.UnimplementedVocabularyServiceServer
+
+	Usecase controller.VocabularyUsecase
+}
+
+func NewVocabularyService(usecase controller.VocabularyUsecase) *VocabularyService {
+	return &VocabularyService{
+		Usecase: usecase,
+	}
+}
+
+func (s *VocabularyService) Create(ctx context.Context, req *vocabularyv1.CreateRequest) (*vocabularyv1.CreateResponse, error) {
+	// Validation check shared with the REST API
+	vocabularyReq := request.VocabularyReq{Title: req.GetTitle(), Meaning: req.GetMeaning(), Sentence: req.GetSentence()}
+	if err := vocabularyReq.Validate(); err != nil {
+		slog.ErrorContext(ctx, "invalid request parameters", slog.String("error", err.Error()))
+		return nil, status.Errorf(codes.InvalidArgument, "invalid input parameters: %s", err)
+	}
+
+	// Execute the application layer logic
+	vocabularyNo, err := s.Usecase.AddVocabulary(ctx, toVocabulary(&vocabularyReq))
+	if err != nil {
+		return nil, toStatus(err, "failed to add the vocabulary")
+	}
+
+	return &vocabularyv1.CreateResponse{VocabularyNo: vocabularyNo}, nil
+}
+
+func (s *VocabularyService) Get(ctx context.Context, req *vocabularyv1.GetRequest) (*vocabularyv1.Vocabulary, error) {
+	vocabulary, err := s.Usecase.FetchVocabularyByNo(ctx, req.GetVocabularyNo())
+	if err != nil {
+		return nil, toStatus(err, "failed to get the vocabulary")
+	}
+
+	return toVocabularyMessage(vocabulary), nil
+}
+
+func (s *VocabularyService) List(req *vocabularyv1.ListRequest, stream grpc.ServerStreamingServer[vocabularyv1.Vocabulary]) error {
+	ctx := stream.Context()
+
+	// Apply the rules of the query parameters of the REST API
+	query, err := request.ParseVocabularyQuery(toQueryValues(req))
+	if err != nil {
+		slog.ErrorContext(ctx, "invalid request parameters", slog.String("error", err.Error()))
+		return status.Errorf(codes.InvalidArgument, "invalid query parameters: %s", err)
+	}
+
+	// Execute the application layer logic
+	vocabularyList, err := s.Usecase.FetchVocabularyList(ctx, query)
+	if err != nil {
+		return toStatus(err, "failed to get the vocabularies")
+	}
+
+	// Send the vocabularies one by one
+	for _, vocabulary := range vocabularyList {
+		if err := stream.Send(toVocabularyMessage(vocabulary)); err != nil {
+			slog.WarnContext(ctx, "failed to stream a vocabulary", slog.String("error", err.Error()))
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *VocabularyService) Update(ctx context.Context, req *vocabularyv1.UpdateRequest) (*vocabularyv1.UpdateResponse, error) {
+	// Validation check shared with the REST API
+	vocabularyReq := request.VocabularyReq{Title: req.GetTitle(), Meaning: req.GetMeaning(), Sentence: req.GetSentence()}
+	if err := vocabularyReq.Validate(); err != nil {
+		slog.ErrorContext(ctx, "invalid request parameters", slog.String("error", err.Error()))
+		return nil, status.Errorf(codes.InvalidArgument, "invalid input parameters: %s", err)
+	}
+
+	// Execute the application layer logic
+	vocabularyNo, err := s.Usecase.UpdateVocabulary(ctx, req.GetVocabularyNo(), toVocabulary(&vocabularyReq))
+	if err != nil {
+		return nil, toStatus(err, "failed to update the vocabulary")
+	}
+
+	return &vocabularyv1.UpdateResponse{VocabularyNo: vocabularyNo}, nil
+}
+
+func (s *VocabularyService) Delete(ctx context.Context, req *vocabularyv1.DeleteRequest) (*vocabularyv1.DeleteResponse, error) {
+	rowsAffected, err := s.Usecase.DeleteVocabulary(ctx, req.GetVocabularyNo())
+	if err != nil {
+		return nil, toStatus(err, "failed to delete the vocabulary")
+	}
+
+	return &vocabularyv1.DeleteResponse{RowsAffected: rowsAffected}, nil
+}
+
+// toStatus maps the domain errors to their gRPC status codes, and hides the others behind INTERNAL
+func toStatus(err error, serverErrorMessage string) error {
+	switch {
+	case errors.Is(err, domain.ErrVocabularyNotFound):
+		return status.Error(codes.NotFound, "the vocabulary is not registered")
+	case errors.Is(err, domain.ErrVocabularyDuplicate):
+		return status.Error(codes.AlreadyExists, "the title is already registered")
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, "the request was canceled")
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, "the request timed out")
+	default:
+		return status.Error(codes.Internal, serverErrorMessage+" due to a server error")
+	}
+}
+
+// request -> vocabulary
+func toVocabulary(req *request.VocabularyReq) *domain.Vocabulary {
+	return &domain.Vocabulary{
+		Title:    req.Title,
+		Meaning:  req.Meaning,
+		Sentence: req.Sentence,
+	}
+}
+
+// vocabulary -> message
+func toVocabularyMessage(vocabulary *domain.Vocabulary) *vocabularyv1.Vocabulary {
+	return &vocabularyv1.Vocabulary{
+		VocabularyNo: vocabulary.VocabularyNo,
+		Title:        vocabulary.Title,
+		Meaning:      vocabulary.Meaning,
+		Sentence:     vocabulary.Sentence,
+		CreatedAt:    timestamppb.New(vocabulary.CreatedAt),
+		UpdatedAt:    timestamppb.New(vocabulary.UpdatedAt),
+		CreatedBy:    vocabulary.CreatedBy,
+		UpdatedBy:    vocabulary.UpdatedBy,
+	}
+}
+
+// toQueryValues writes the set fields of the list request as the query parameters of the REST API
+func toQueryValues(req *vocabularyv1.ListRequest) url.Values {
+	values := url.Values{}
+	for name, value := range map[string]string{
+		"title_prefix":      req.GetTitlePrefix(),
+		"sentence_contains": req.GetSentenceContains(),
+		"sort":              req.GetSort(),
+	} {
+		if value != "" {
+			values.Set(name, value)
+		}
+	}
+
+	for name, timestamp := range map[string]*timestamppb.Timestamp{
+		"created_from":  req.GetCreatedFrom(),
+		"created_until": req.GetCreatedUntil(),
+		"updated_from":  req.GetUpdatedFrom(),
+		"updated_until": req.GetUpdatedUntil(),
+	} {
+		if timestamp != nil {
+			values.Set(name, timestamp.AsTime().Format(time.RFC3339Nano))
+		}
+	}
+	return values
+}
@@ -0,0 +1,233 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/takumi616/golang-backend-sample/application/usecase"
+	"github.com/takumi616/golang-backend-sample/domain"
+	"github.com/takumi616/golang-backend-sample/interface/controller"
+	"github.com/takumi616/golang-backend-sample/interface/rpc/vocabularyv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var createdAt = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// fakeVocabularyUsecase implements the methods the service calls
+type fakeVocabularyUsecase struct {
+	controller.VocabularyUsecase
+
+	addVocabulary       func(ctx context.Context, vocabulary *domain.Vocabulary) (int64, error)
+	fetchVocabularyByNo func(ctx context.Context, vocabularyNo int64) (*domain.Vocabulary, error)
+	fetchVocabularyList func(ctx context.Context, query usecase.VocabularyQuery) ([]*domain.Vocabulary, error)
+	deleteVocabulary    func(ctx context.Context, vocabularyNo int64) (int64, error)
+}
+
+func (f *fakeVocabularyUsecase) AddVocabulary(ctx context.Context, vocabulary *domain.Vocabulary) (int64, error) {
+	return f.addVocabulary(ctx, vocabulary)
+}
+
+func (f *fakeVocabularyUsecase) FetchVocabularyByNo(ctx context.Context, vocabularyNo int64) (*domain.Vocabulary, error) {
+	return f.fetchVocabularyByNo(ctx, vocabularyNo)
+}
+
+func (f *fakeVocabularyUsecase) FetchVocabularyList(ctx context.Context, query usecase.VocabularyQuery) ([]*domain.Vocabulary, error) {
+	return f.fetchVocabularyList(ctx, query)
+}
+
+func (f *fakeVocabularyUsecase) DeleteVocabulary(ctx context.Context, vocabularyNo int64) (int64, error) {
+	return f.deleteVocabulary(ctx, vocabularyNo)
+}
+
+// newClient serves the service over an in-memory connection
+func newClient(t *testing.T, u controller.VocabularyUsecase) vocabularyv1.VocabularyServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	vocabularyv1.RegisterVocabularyServiceServer(server, NewVocabularyService(u))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return vocabularyv1.NewVocabularyServiceClient(conn)
+}
+
+func checkCode(t *testing.T, err error, want codes.Code) {
+	t.Helper()
+
+	if got := status.Code(err); got != want {
+		t.Errorf("status code = %s, want %s (%v)", got, want, err)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	var added *domain.Vocabulary
+	client := newClient(t, &fakeVocabularyUsecase{
+		addVocabulary: func(ctx context.Context, vocabulary *domain.Vocabulary) (int64, error) {
+			if vocabulary.Title == "taken" {
+				return 0, domain.ErrVocabularyDuplicate
+			}
+			added = vocabulary
+			return 7, nil
+		},
+	})
+	ctx := context.Background()
+
+	res, err := client.Create(ctx, &vocabularyv1.CreateRequest{Title: " apple ", Meaning: "fruit", Sentence: "I ate an apple."})
+	if err != nil || res.GetVocabularyNo() != 7 {
+		t.Fatalf("Create = %v, %v, want 7", res, err)
+	}
+	if added.Title != "apple" {
+		t.Errorf("added title = %q, want the trimmed apple", added.Title)
+	}
+
+	_, err = client.Create(ctx, &vocabularyv1.CreateRequest{Title: "taken", Meaning: "m", Sentence: "s"})
+	checkCode(t, err, codes.AlreadyExists)
+
+	_, err = client.Create(ctx, &vocabularyv1.CreateRequest{Meaning: "m", Sentence: "s"})
+	checkCode(t, err, codes.InvalidArgument)
+}
+
+func TestGet(t *testing.T) {
+	client := newClient(t, &fakeVocabularyUsecase{
+		fetchVocabularyByNo: func(ctx context.Context, vocabularyNo int64) (*domain.Vocabulary, error) {
+			switch vocabularyNo {
+			case 1:
+				return &domain.Vocabulary{VocabularyNo: 1, Title: "apple", CreatedAt: createdAt, CreatedBy: "alice"}, nil
+			case 2:
+				return nil, domain.ErrVocabularyNotFound
+			default:
+				return nil, errors.New("connection refused")
+			}
+		},
+	})
+	ctx := context.Background()
+
+	vocabulary, err := client.Get(ctx, &vocabularyv1.GetRequest{VocabularyNo: 1})
+	if err != nil {
+		t.Fatalf("Get returned an error: %v", err)
+	}
+	if vocabulary.GetTitle() != "apple" || !vocabulary.GetCreatedAt().AsTime().Equal(createdAt) || vocabulary.GetCreatedBy() != "alice" {
+		t.Errorf("Get = %v", vocabulary)
+	}
+
+	_, err = client.Get(ctx, &vocabularyv1.GetRequest{VocabularyNo: 2})
+	checkCode(t, err, codes.NotFound)
+
+	// The cause of a server error is not sent to the client
+	_, err = client.Get(ctx, &vocabularyv1.GetRequest{VocabularyNo: 3})
+	checkCode(t, err, codes.Internal)
+	if status.Convert(err).Message() != "failed to get the vocabulary due to a server error" {
+		t.Errorf("message = %q", status.Convert(err).Message())
+	}
+}
+
+func TestList(t *testing.T) {
+	var query usecase.VocabularyQuery
+	client := newClient(t, &fakeVocabularyUsecase{
+		fetchVocabularyList: func(ctx context.Context, q usecase.VocabularyQuery) ([]*domain.Vocabulary, error) {
+			query = q
+			return []*domain.Vocabulary{{VocabularyNo: 1, Title: "apple"}, {VocabularyNo: 2, Title: "apricot"}}, nil
+		},
+	})
+	ctx := context.Background()
+
+	stream, err := client.List(ctx, &vocabularyv1.ListRequest{
+		TitlePrefix: "ap", CreatedFrom: timestamppb.New(createdAt), Sort: "-title",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var titles []string
+	for {
+		vocabulary, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv returned an error: %v", err)
+		}
+		titles = append(titles, vocabulary.GetTitle())
+	}
+	if len(titles) != 2 || titles[0] != "apple" || titles[1] != "apricot" {
+		t.Errorf("streamed titles = %v, want [apple apricot]", titles)
+	}
+
+	// The filters follow the rules of the REST API
+	if query.TitlePrefix != "ap" || !query.CreatedFrom.Equal(createdAt) || len(query.Sort) != 1 || !query.Sort[0].Descending {
+		t.Errorf("query = %+v", query)
+	}
+
+	stream, err = client.List(ctx, &vocabularyv1.ListRequest{Sort: "meaning"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = stream.Recv()
+	checkCode(t, err, codes.InvalidArgument)
+}
+
+func TestDelete(t *testing.T) {
+	client := newClient(t, &fakeVocabularyUsecase{
+		deleteVocabulary: func(ctx context.Context, vocabularyNo int64) (int64, error) {
+			if vocabularyNo != 1 {
+				return 0, domain.ErrVocabularyNotFound
+			}
+			return 1, nil
+		},
+	})
+	ctx := context.Background()
+
+	res, err := client.Delete(ctx, &vocabularyv1.DeleteRequest{VocabularyNo: 1})
+	if err != nil || res.GetRowsAffected() != 1 {
+		t.Errorf("Delete = %v, %v, want 1 row", res, err)
+	}
+
+	_, err = client.Delete(ctx, &vocabularyv1.DeleteRequest{VocabularyNo: 2})
+	checkCode(t, err, codes.NotFound)
+}
+
+func TestEveryMethodHasAScope(t *testing.T) {
+	desc := vocabularyv1.VocabularyService_ServiceDesc
+
+	var methods []string
+	for _, method := range desc.Methods {
+		methods = append(methods, method.MethodName)
+	}
+	for _, stream := range desc.Streams {
+		methods = append(methods, stream.StreamName)
+	}
+
+	for _, method := range methods {
+		scope, ok := Scopes["/"+desc.ServiceName+"/"+method]
+		if !ok {
+			t.Errorf("method %s has no scope", method)
+			continue
+		}
+
+		// Only the reads are granted to read-only keys
+		read := method == "Get" || method == "List"
+		if read != (scope == domain.ScopeVocabRead) {
+			t.Errorf("method %s takes %s", method, scope)
+		}
+	}
+}
@@ -0,0 +1,697 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: vocabulary/v1/vocabulary.proto
+
+package vocabularyv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Vocabulary struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	VocabularyNo int64                  `protobuf:"varint,1,opt,name=vocabulary_no,json=vocabularyNo,proto3" json:"vocabulary_no,omitempty"`
+	Title        string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Meaning      string                 `protobuf:"bytes,3,opt,name=meaning,proto3" json:"meaning,omitempty"`
+	Sentence     string                 `protobuf:"bytes,4,opt,name=sentence,proto3" json:"sentence,omitempty"`
+	CreatedAt    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Clients that added and last updated the vocabulary
+	CreatedBy     string `protobuf:"bytes,7,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	UpdatedBy     string `protobuf:"bytes,8,opt,name=updated_by,json=updatedBy,proto3" json:"updated_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Vocabulary) Reset() {
+	*x = Vocabulary{}
+	mi := &file_vocabulary_v1_vocabulary_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Vocabulary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Vocabulary) ProtoMessage() {}
+
+func (x *Vocabulary) ProtoReflect() protoreflect.Message {
+	mi := &file_vocabulary_v1_vocabulary_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Vocabulary.ProtoReflect.Descriptor instead.
+func (*Vocabulary) Descriptor() ([]byte, []int) {
+	return file_vocabulary_v1_vocabulary_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Vocabulary) GetVocabularyNo() int64 {
+	if x != nil {
+		return x.VocabularyNo
+	}
+	return 0
+}
+
+func (x *Vocabulary) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Vocabulary) GetMeaning() string {
+	if x != nil {
+		return x.Meaning
+	}
+	return ""
+}
+
+func (x *Vocabulary) GetSentence() string {
+	if x != nil {
+		return x.Sentence
+	}
+	return ""
+}
+
+func (x *Vocabulary) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Vocabulary) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Vocabulary) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *Vocabulary) GetUpdatedBy() string {
+	if x != nil {
+		return x.UpdatedBy
+	}
+	return ""
+}
+
+type CreateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unique title of 20 characters or fewer. Surrounding spaces are trimmed from every field.
+	Title         string `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Meaning       string `protobuf:"bytes,2,opt,name=meaning,proto3" json:"meaning,omitempty"`
+	Sentence      string `protobuf:"bytes,3,opt,name=sentence,proto3" json:"sentence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateRequest) Reset() {
+	*x = CreateRequest{}
+	mi := &file_vocabulary_v1_vocabulary_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRequest) ProtoMessage() {}
+
+func (x *CreateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vocabulary_v1_vocabulary_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRequest.ProtoReflect.Descriptor instead.
+func (*CreateRequest) Descriptor() ([]byte, []int) {
+	return file_vocabulary_v1_vocabulary_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateRequest) GetMeaning() string {
+	if x != nil {
+		return x.Meaning
+	}
+	return ""
+}
+
+func (x *CreateRequest) GetSentence() string {
+	if x != nil {
+		return x.Sentence
+	}
+	return ""
+}
+
+type CreateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	VocabularyNo  int64                  `protobuf:"varint,1,opt,name=vocabulary_no,json=vocabularyNo,proto3" json:"vocabulary_no,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateResponse) Reset() {
+	*x = CreateResponse{}
+	mi := &file_vocabulary_v1_vocabulary_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateResponse) ProtoMessage() {}
+
+func (x *CreateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vocabulary_v1_vocabulary_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateResponse.ProtoReflect.Descriptor instead.
+func (*CreateResponse) Descriptor() ([]byte, []int) {
+	return file_vocabulary_v1_vocabulary_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateResponse) GetVocabularyNo() int64 {
+	if x != nil {
+		return x.VocabularyNo
+	}
+	return 0
+}
+
+type GetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	VocabularyNo  int64                  `protobuf:"varint,1,opt,name=vocabulary_no,json=vocabularyNo,proto3" json:"vocabulary_no,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRequest) Reset() {
+	*x = GetRequest{}
+	mi := &file_vocabulary_v1_vocabulary_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRequest) ProtoMessage() {}
+
+func (x *GetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vocabulary_v1_vocabulary_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRequest.ProtoReflect.Descriptor instead.
+func (*GetRequest) Descriptor() ([]byte, []int) {
+	return file_vocabulary_v1_vocabulary_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetRequest) GetVocabularyNo() int64 {
+	if x != nil {
+		return x.VocabularyNo
+	}
+	return 0
+}
+
+// The filters and the sort of GET /api/vocabularies. An empty field is not applied.
+type ListRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Case-insensitive prefix of the title
+	TitlePrefix string `protobuf:"bytes,1,opt,name=title_prefix,json=titlePrefix,proto3" json:"title_prefix,omitempty"`
+	// Case-insensitive term the sentence contains
+	SentenceContains string `protobuf:"bytes,2,opt,name=sentence_contains,json=sentenceContains,proto3" json:"sentence_contains,omitempty"`
+	// Time ranges, each including its start and excluding its end
+	CreatedFrom  *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_from,json=createdFrom,proto3" json:"created_from,omitempty"`
+	CreatedUntil *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_until,json=createdUntil,proto3" json:"created_until,omitempty"`
+	UpdatedFrom  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_from,json=updatedFrom,proto3" json:"updated_from,omitempty"`
+	UpdatedUntil *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_until,json=updatedUntil,proto3" json:"updated_until,omitempty"`
+	// Comma-separated fields, each descending when prefixed with "-", for example "title,-vocabulary_no"
+	Sort          string `protobuf:"bytes,7,opt,name=sort,proto3" json:"sort,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRequest) Reset() {
+	*x = ListRequest{}
+	mi := &file_vocabulary_v1_vocabulary_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRequest) ProtoMessage() {}
+
+func (x *ListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vocabulary_v1_vocabulary_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRequest.ProtoReflect.Descriptor instead.
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return file_vocabulary_v1_vocabulary_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListRequest) GetTitlePrefix() string {
+	if x != nil {
+		return x.TitlePrefix
+	}
+	return ""
+}
+
+func (x *ListRequest) GetSentenceContains() string {
+	if x != nil {
+		return x.SentenceContains
+	}
+	return ""
+}
+
+func (x *ListRequest) GetCreatedFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedFrom
+	}
+	return nil
+}
+
+func (x *ListRequest) GetCreatedUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedUntil
+	}
+	return nil
+}
+
+func (x *ListRequest) GetUpdatedFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedFrom
+	}
+	return nil
+}
+
+func (x *ListRequest) GetUpdatedUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedUntil
+	}
+	return nil
+}
+
+func (x *ListRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+type UpdateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	VocabularyNo  int64                  `protobuf:"varint,1,opt,name=vocabulary_no,json=vocabularyNo,proto3" json:"vocabulary_no,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Meaning       string                 `protobuf:"bytes,3,opt,name=meaning,proto3" json:"meaning,omitempty"`
+	Sentence      string                 `protobuf:"bytes,4,opt,name=sentence,proto3" json:"sentence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateRequest) Reset() {
+	*x = UpdateRequest{}
+	mi := &file_vocabulary_v1_vocabulary_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRequest) ProtoMessage() {}
+
+func (x *UpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vocabulary_v1_vocabulary_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRequest.ProtoReflect.Descriptor instead.
+func (*UpdateRequest) Descriptor() ([]byte, []int) {
+	return file_vocabulary_v1_vocabulary_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UpdateRequest) GetVocabularyNo() int64 {
+	if x != nil {
+		return x.VocabularyNo
+	}
+	return 0
+}
+
+func (x *UpdateRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *UpdateRequest) GetMeaning() string {
+	if x != nil {
+		return x.Meaning
+	}
+	return ""
+}
+
+func (x *UpdateRequest) GetSentence() string {
+	if x != nil {
+		return x.Sentence
+	}
+	return ""
+}
+
+type UpdateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	VocabularyNo  int64                  `protobuf:"varint,1,opt,name=vocabulary_no,json=vocabularyNo,proto3" json:"vocabulary_no,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateResponse) Reset() {
+	*x = UpdateResponse{}
+	mi := &file_vocabulary_v1_vocabulary_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateResponse) ProtoMessage() {}
+
+func (x *UpdateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vocabulary_v1_vocabulary_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateResponse.ProtoReflect.Descriptor instead.
+func (*UpdateResponse) Descriptor() ([]byte, []int) {
+	return file_vocabulary_v1_vocabulary_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpdateResponse) GetVocabularyNo() int64 {
+	if x != nil {
+		return x.VocabularyNo
+	}
+	return 0
+}
+
+type DeleteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	VocabularyNo  int64                  `protobuf:"varint,1,opt,name=vocabulary_no,json=vocabularyNo,proto3" json:"vocabulary_no,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRequest) Reset() {
+	*x = DeleteRequest{}
+	mi := &file_vocabulary_v1_vocabulary_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRequest) ProtoMessage() {}
+
+func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vocabulary_v1_vocabulary_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return file_vocabulary_v1_vocabulary_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeleteRequest) GetVocabularyNo() int64 {
+	if x != nil {
+		return x.VocabularyNo
+	}
+	return 0
+}
+
+type DeleteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RowsAffected  int64                  `protobuf:"varint,1,opt,name=rows_affected,json=rowsAffected,proto3" json:"rows_affected,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteResponse) Reset() {
+	*x = DeleteResponse{}
+	mi := &file_vocabulary_v1_vocabulary_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResponse) ProtoMessage() {}
+
+func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vocabulary_v1_vocabulary_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
+func (*DeleteResponse) Descriptor() ([]byte, []int) {
+	return file_vocabulary_v1_vocabulary_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeleteResponse) GetRowsAffected() int64 {
+	if x != nil {
+		return x.RowsAffected
+	}
+	return 0
+}
+
+var File_vocabulary_v1_vocabulary_proto protoreflect.FileDescriptor
+
+const file_vocabulary_v1_vocabulary_proto_rawDesc = "" +
+	"\n" +
+	"\x1evocabulary/v1/vocabulary.proto\x12\rvocabulary.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb1\x02\n" +
+	"\n" +
+	"Vocabulary\x12#\n" +
+	"\rvocabulary_no\x18\x01 \x01(\x03R\fvocabularyNo\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x18\n" +
+	"\ameaning\x18\x03 \x01(\tR\ameaning\x12\x1a\n" +
+	"\bsentence\x18\x04 \x01(\tR\bsentence\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\a \x01(\tR\tcreatedBy\x12\x1d\n" +
+	"\n" +
+	"updated_by\x18\b \x01(\tR\tupdatedBy\"[\n" +
+	"\rCreateRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x18\n" +
+	"\ameaning\x18\x02 \x01(\tR\ameaning\x12\x1a\n" +
+	"\bsentence\x18\x03 \x01(\tR\bsentence\"5\n" +
+	"\x0eCreateResponse\x12#\n" +
+	"\rvocabulary_no\x18\x01 \x01(\x03R\fvocabularyNo\"1\n" +
+	"\n" +
+	"GetRequest\x12#\n" +
+	"\rvocabulary_no\x18\x01 \x01(\x03R\fvocabularyNo\"\xf1\x02\n" +
+	"\vListRequest\x12!\n" +
+	"\ftitle_prefix\x18\x01 \x01(\tR\vtitlePrefix\x12+\n" +
+	"\x11sentence_contains\x18\x02 \x01(\tR\x10sentenceContains\x12=\n" +
+	"\fcreated_from\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\vcreatedFrom\x12?\n" +
+	"\rcreated_until\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedUntil\x12=\n" +
+	"\fupdated_from\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vupdatedFrom\x12?\n" +
+	"\rupdated_until\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\fupdatedUntil\x12\x12\n" +
+	"\x04sort\x18\a \x01(\tR\x04sort\"\x80\x01\n" +
+	"\rUpdateRequest\x12#\n" +
+	"\rvocabulary_no\x18\x01 \x01(\x03R\fvocabularyNo\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x18\n" +
+	"\ameaning\x18\x03 \x01(\tR\ameaning\x12\x1a\n" +
+	"\bsentence\x18\x04 \x01(\tR\bsentence\"5\n" +
+	"\x0eUpdateResponse\x12#\n" +
+	"\rvocabulary_no\x18\x01 \x01(\x03R\fvocabularyNo\"4\n" +
+	"\rDeleteRequest\x12#\n" +
+	"\rvocabulary_no\x18\x01 \x01(\x03R\fvocabularyNo\"5\n" +
+	"\x0eDeleteResponse\x12#\n" +
+	"\rrows_affected\x18\x01 \x01(\x03R\frowsAffected2\xe6\x02\n" +
+	"\x11VocabularyService\x12E\n" +
+	"\x06Create\x12\x1c.vocabulary.v1.CreateRequest\x1a\x1d.vocabulary.v1.CreateResponse\x12;\n" +
+	"\x03Get\x12\x19.vocabulary.v1.GetRequest\x1a\x19.vocabulary.v1.Vocabulary\x12?\n" +
+	"\x04List\x12\x1a.vocabulary.v1.ListRequest\x1a\x19.vocabulary.v1.Vocabulary0\x01\x12E\n" +
+	"\x06Update\x12\x1c.vocabulary.v1.UpdateRequest\x1a\x1d.vocabulary.v1.UpdateResponse\x12E\n" +
+	"\x06Delete\x12\x1c.vocabulary.v1.DeleteRequest\x1a\x1d.vocabulary.v1.DeleteResponseBTZRgithub.com/takumi616/golang-backend-sample/interface/rpc/vocabularyv1;vocabularyv1b\x06proto3"
+
+var (
+	file_vocabulary_v1_vocabulary_proto_rawDescOnce sync.Once
+	file_vocabulary_v1_vocabulary_proto_rawDescData []byte
+)
+
+func file_vocabulary_v1_vocabulary_proto_rawDescGZIP() []byte {
+	file_vocabulary_v1_vocabulary_proto_rawDescOnce.Do(func() {
+		file_vocabulary_v1_vocabulary_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_vocabulary_v1_vocabulary_proto_rawDesc), len(file_vocabulary_v1_vocabulary_proto_rawDesc)))
+	})
+	return file_vocabulary_v1_vocabulary_proto_rawDescData
+}
+
+var file_vocabulary_v1_vocabulary_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_vocabulary_v1_vocabulary_proto_goTypes = []any{
+	(*Vocabulary)(nil),            // 0: vocabulary.v1.Vocabulary
+	(*CreateRequest)(nil),         // 1: vocabulary.v1.CreateRequest
+	(*CreateResponse)(nil),        // 2: vocabulary.v1.CreateResponse
+	(*GetRequest)(nil),            // 3: vocabulary.v1.GetRequest
+	(*ListRequest)(nil),           // 4: vocabulary.v1.ListRequest
+	(*UpdateRequest)(nil),         // 5: vocabulary.v1.UpdateRequest
+	(*UpdateResponse)(nil),        // 6: vocabulary.v1.UpdateResponse
+	(*DeleteRequest)(nil),         // 7: vocabulary.v1.DeleteRequest
+	(*DeleteResponse)(nil),        // 8: vocabulary.v1.DeleteResponse
+	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
+}
+var file_vocabulary_v1_vocabulary_proto_depIdxs = []int32{
+	9,  // 0: vocabulary.v1.Vocabulary.created_at:type_name -> google.protobuf.Timestamp
+	9,  // 1: vocabulary.v1.Vocabulary.updated_at:type_name -> google.protobuf.Timestamp
+	9,  // 2: vocabulary.v1.ListRequest.created_from:type_name -> google.protobuf.Timestamp
+	9,  // 3: vocabulary.v1.ListRequest.created_until:type_name -> google.protobuf.Timestamp
+	9,  // 4: vocabulary.v1.ListRequest.updated_from:type_name -> google.protobuf.Timestamp
+	9,  // 5: vocabulary.v1.ListRequest.updated_until:type_name -> google.protobuf.Timestamp
+	1,  // 6: vocabulary.v1.VocabularyService.Create:input_type -> vocabulary.v1.CreateRequest
+	3,  // 7: vocabulary.v1.VocabularyService.Get:input_type -> vocabulary.v1.GetRequest
+	4,  // 8: vocabulary.v1.VocabularyService.List:input_type -> vocabulary.v1.ListRequest
+	5,  // 9: vocabulary.v1.VocabularyService.Update:input_type -> vocabulary.v1.UpdateRequest
+	7,  // 10: vocabulary.v1.VocabularyService.Delete:input_type -> vocabulary.v1.DeleteRequest
+	2,  // 11: vocabulary.v1.VocabularyService.Create:output_type -> vocabulary.v1.CreateResponse
+	0,  // 12: vocabulary.v1.VocabularyService.Get:output_type -> vocabulary.v1.Vocabulary
+	0,  // 13: vocabulary.v1.VocabularyService.List:output_type -> vocabulary.v1.Vocabulary
+	6,  // 14: vocabulary.v1.VocabularyService.Update:output_type -> vocabulary.v1.UpdateResponse
+	8,  // 15: vocabulary.v1.VocabularyService.Delete:output_type -> vocabulary.v1.DeleteResponse
+	11, // [11:16] is the sub-list for method output_type
+	6,  // [6:11] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_vocabulary_v1_vocabulary_proto_init() }
+func file_vocabulary_v1_vocabulary_proto_init() {
+	if File_vocabulary_v1_vocabulary_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_vocabulary_v1_vocabulary_proto_rawDesc), len(file_vocabulary_v1_vocabulary_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_vocabulary_v1_vocabulary_proto_goTypes,
+		DependencyIndexes: file_vocabulary_v1_vocabulary_proto_depIdxs,
+		MessageInfos:      file_vocabulary_v1_vocabulary_proto_msgTypes,
+	}.Build()
+	File_vocabulary_v1_vocabulary_proto = out.File
+	file_vocabulary_v1_vocabulary_proto_goTypes = nil
+	file_vocabulary_v1_vocabulary_proto_depIdxs = nil
+}
@@ -0,0 +1,293 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: vocabulary/v1/vocabulary.proto
+
+package vocabularyv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	VocabularyService_Create_FullMethodName = "/vocabulary.v1.VocabularyService/Create"
+	VocabularyService_Get_FullMethodName    = "/vocabulary.v1.VocabularyService/Get"
+	VocabularyService_List_FullMethodName   = "/vocabulary.v1.VocabularyService/List"
+	VocabularyService_Update_FullMethodName = "/vocabulary.v1.VocabularyService/Update"
+	VocabularyService_Delete_FullMethodName = "/vocabulary.v1.VocabularyService/Delete"
+)
+
+// VocabularyServiceClient is the client API for VocabularyService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// VocabularyService is the gRPC counterpart of /api/vocabularies.
+// Errors are reported with the status codes NOT_FOUND, ALREADY_EXISTS, INVALID_ARGUMENT and INTERNAL.
+type VocabularyServiceClient interface {
+	// Create adds a vocabulary. The title must be unique.
+	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error)
+	// Get returns a vocabulary
+	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Vocabulary, error)
+	// List streams the vocabularies matching the filters, one message each
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Vocabulary], error)
+	// Update replaces the content of a vocabulary
+	Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateResponse, error)
+	// Delete deletes a vocabulary
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+}
+
+type vocabularyServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewVocabularyServiceClient(cc grpc.ClientConnInterface) VocabularyServiceClient {
+	return &vocabularyServiceClient{cc}
+}
+
+func (c *vocabularyServiceClient) Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateResponse)
+	err := c.cc.Invoke(ctx, VocabularyService_Create_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vocabularyServiceClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Vocabulary, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Vocabulary)
+	err := c.cc.Invoke(ctx, VocabularyService_Get_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vocabularyServiceClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Vocabulary], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &VocabularyService_ServiceDesc.Streams[0], VocabularyService_List_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListRequest, Vocabulary]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VocabularyService_ListClient = grpc.ServerStreamingClient[Vocabulary]
+
+func (c *vocabularyServiceClient) Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateResponse)
+	err := c.cc.Invoke(ctx, VocabularyService_Update_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vocabularyServiceClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, VocabularyService_Delete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// VocabularyServiceServer is the server API for VocabularyService service.
+// All implementations must embed UnimplementedVocabularyServiceServer
+// for forward compatibility.
+//
+// VocabularyService is the gRPC counterpart of /api/vocabularies.
+// Errors are reported with the status codes NOT_FOUND, ALREADY_EXISTS, INVALID_ARGUMENT and INTERNAL.
+type VocabularyServiceServer interface {
+	// Create adds a vocabulary. The title must be unique.
+	Create(context.Context, *CreateRequest) (*CreateResponse, error)
+	// Get returns a vocabulary
+	Get(context.Context, *GetRequest) (*Vocabulary, error)
+	// List streams the vocabularies matching the filters, one message each
+	List(*ListRequest, grpc.ServerStreamingServer[Vocabulary]) error
+	// Update replaces the content of a vocabulary
+	Update(context.Context, *UpdateRequest) (*UpdateResponse, error)
+	// Delete deletes a vocabulary
+	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	mustEmbedUnimplementedVocabularyServiceServer()
+}
+
+// UnimplementedVocabularyServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedVocabularyServiceServer struct{}
+
+func (UnimplementedVocabularyServiceServer) Create(context.Context, *CreateRequest) (*CreateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (UnimplementedVocabularyServiceServer) Get(context.Context, *GetRequest) (*Vocabulary, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (UnimplementedVocabularyServiceServer) List(*ListRequest, grpc.ServerStreamingServer[Vocabulary]) error {
+	return status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedVocabularyServiceServer) Update(context.Context, *UpdateRequest) (*UpdateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+}
+func (UnimplementedVocabularyServiceServer) Delete(context.Context, *DeleteRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedVocabularyServiceServer) mustEmbedUnimplementedVocabularyServiceServer() {}
+func (UnimplementedVocabularyServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeVocabularyServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to VocabularyServiceServer will
+// result in compilation errors.
+type UnsafeVocabularyServiceServer interface {
+	mustEmbedUnimplementedVocabularyServiceServer()
+}
+
+func RegisterVocabularyServiceServer(s grpc.ServiceRegistrar, srv VocabularyServiceServer) {
+	// If the following call pancis, it indicates UnimplementedVocabularyServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&VocabularyService_ServiceDesc, srv)
+}
+
+func _VocabularyService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VocabularyServiceServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VocabularyService_Create_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VocabularyServiceServer).Create(ctx, req.(*CreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VocabularyService_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VocabularyServiceServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VocabularyService_Get_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VocabularyServiceServer).Get(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VocabularyService_List_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(VocabularyServiceServer).List(m, &grpc.GenericServerStream[ListRequest, Vocabulary]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VocabularyService_ListServer = grpc.ServerStreamingServer[Vocabulary]
+
+func _VocabularyService_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VocabularyServiceServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VocabularyService_Update_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VocabularyServiceServer).Update(ctx, req.(*UpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VocabularyService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VocabularyServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VocabularyService_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VocabularyServiceServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// VocabularyService_ServiceDesc is the grpc.ServiceDesc for VocabularyService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var VocabularyService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vocabulary.v1.VocabularyService",
+	HandlerType: (*VocabularyServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _VocabularyService_Create_Handler,
+		},
+		{
+			MethodName: "Get",
+			Handler:    _VocabularyService_Get_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _VocabularyService_Update_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _VocabularyService_Delete_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "List",
+			Handler:       _VocabularyService_List_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "vocabulary/v1/vocabulary.proto",
+}
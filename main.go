@@ -2,56 +2,426 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
+	"net/http"
+	"net/netip"
 	"os"
 
-	_ "github.com/lib/pq"
+	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/takumi616/golang-backend-sample/application/usecase"
 	"github.com/takumi616/golang-backend-sample/config"
+	"github.com/takumi616/golang-backend-sample/domain"
 	"github.com/takumi616/golang-backend-sample/infrastructure/db"
 	"github.com/takumi616/golang-backend-sample/infrastructure/db/repository"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db/repository/cache"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db/repository/memory"
+	"github.com/takumi616/golang-backend-sample/infrastructure/db/repository/sqlite"
+	"github.com/takumi616/golang-backend-sample/infrastructure/event"
+	"github.com/takumi616/golang-backend-sample/infrastructure/grpcserver"
+	"github.com/takumi616/golang-backend-sample/infrastructure/job"
 	"github.com/takumi616/golang-backend-sample/infrastructure/web"
+	"github.com/takumi616/golang-backend-sample/infrastructure/webhook"
+	"github.com/takumi616/golang-backend-sample/interface/cli"
 	"github.com/takumi616/golang-backend-sample/interface/controller"
+	"github.com/takumi616/golang-backend-sample/interface/rpc"
+	"github.com/takumi616/golang-backend-sample/migrations"
 )
 
-func run(ctx context.Context) error {
+const usage = `Usage: golang-backend-sample [config flags] <command> [arguments]
+
+Commands:
+  serve                          Run the http server (default)
+  migrate                        Apply pending database migrations
+  import <file>                  Add the vocabularies in a JSON file
+  export <file>                  Write every vocabulary to a JSON file
+  seed                           Add sample vocabularies
+  vocab get <vocabularyNo>       Show a vocabulary
+  vocab list                     Show every vocabulary
+  vocab add --title <title> --meaning <meaning> --sentence <sentence>
+                                 Add a vocabulary
+  vocab delete <vocabularyNo>    Delete a vocabulary
+  apikey create --name <name> --scopes <scope,...> [--expires-in <duration>]
+                                 Create an API key and print its token once
+  apikey list                    Show every API key
+  apikey revoke <keyID>          Revoke an API key
+  config print                   Show the effective configuration with secrets redacted
+
+Commands except serve and migrate accept --output text|json.
+
+Configuration is read from defaults, then the YAML or TOML file given by
+--config or CONFIG_FILE, then environment variables (or NAME_FILE for a file
+holding the value), then config flags such as --app-port 8080.
+
+Exit codes:
+  0 success, 1 failure, 2 usage error, 3 not found, 4 conflict
+`
+
+func run(ctx context.Context, args []string) error {
+	if len(args) > 0 && (args[0] == "help" || args[0] == "-h" || args[0] == "--help") {
+		fmt.Fprint(os.Stdout, usage)
+		return nil
+	}
+
+	// Load the configuration layers; the flags come before the command
+	cfg, args, loadErr := config.Load(args)
+
+	// Serve the http server when no command is given
+	command := "serve"
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+
+	switch command {
+	case "help":
+		fmt.Fprint(os.Stdout, usage)
+		return nil
+	case "serve", "migrate", "import", "export", "seed", "vocab", "apikey", "config":
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		return cli.UsageError("unknown command %q", command)
+	}
+
 	// Initialize a structure logger
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	// Logs of the cli commands go to stderr so that they don't mix with the output
+	var logOutput io.Writer = os.Stderr
+	if command == "serve" {
+		logOutput = os.Stdout
+	}
+	logger := slog.New(slog.NewJSONHandler(logOutput, nil))
 	slog.SetDefault(logger)
 
-	// Get the environment variables
-	cfg, err := config.NewConfig(ctx)
-	if err != nil {
-		return err
+	// Flags or the config file could not be parsed at all
+	if cfg == nil {
+		slog.ErrorContext(ctx, "failed to load the configuration", "error", loadErr)
+		return &cli.ExitError{Code: cli.ExitUsage, Err: loadErr}
+	}
+
+	// Print the configuration even if it is invalid, to help fixing it
+	validateErr := errors.Join(loadErr, cfg.Validate())
+	if command == "config" {
+		if err := cli.PrintConfig(os.Stdout, os.Stderr, args, cfg.Redacted()); err != nil {
+			return err
+		}
+	}
+
+	if validateErr != nil {
+		slog.ErrorContext(ctx, "invalid configuration", "error", validateErr)
+		return &cli.ExitError{Code: cli.ExitUsage, Err: validateErr}
+	}
+
+	if command == "config" {
+		return nil
 	}
 
 	// Open the DB
-	db, err := db.Open(ctx, cfg)
+	backend, err := openBackend(ctx, cfg)
 	if err != nil {
 		return err
 	}
+	defer backend.Close()
+
+	// Cache the queries in front of the backend
+	if cfg.CacheEnabled {
+		cachedRepository := cache.NewVocabularyRepository(backend.Repository, cfg.CacheSize, cfg.CacheTTL)
+		backend.Repository = cachedRepository
+		backend.TxManager = cache.NewTxManager(backend.TxManager, cachedRepository)
+	}
+
+	if command == "migrate" {
+		return migrate(ctx, cfg, backend.SQLDB, args)
+	}
 
 	//Set up dependencies between layers
-	vocabularyRepository := repository.NewVocabularyRepository(db)
-	vocabularyUsecase := usecase.NewVocabularyUsecase(vocabularyRepository)
-	vocabularyController := controller.NewVocabularyController(vocabularyUsecase)
+	jobUsecase := usecase.NewJobUsecase(
+		backend.JobRepository,
+		usecase.RetryPolicy{
+			MaxAttempts: cfg.JobsMaxAttempts, InitialBackoff: cfg.JobsInitialBackoff, MaxBackoff: cfg.JobsMaxBackoff,
+		},
+		cfg.JobsLease,
+	)
+	var webhookAllowed []netip.Prefix
+	for _, cidr := range cfg.WebhookAllowedCIDRs {
+		// Checked by Validate
+		webhookAllowed = append(webhookAllowed, netip.MustParsePrefix(cidr))
+	}
+	webhookUsecase := usecase.NewWebhookUsecase(
+		backend.WebhookRepository,
+		backend.JobRepository,
+		webhook.NewSender(cfg.WebhookTimeout, "golang-backend-sample-webhook/1.0", webhookAllowed),
+		usecase.RetryPolicy{
+			MaxAttempts: cfg.WebhookMaxAttempts, InitialBackoff: cfg.WebhookInitialBackoff, MaxBackoff: cfg.WebhookMaxBackoff,
+		},
+	)
+
+	// The changes are enqueued for the webhooks whichever command makes them
+	var outbox usecase.VocabularyOutbox
+	if backend.WebhookRepository != nil && backend.JobRepository != nil {
+		outbox = webhookUsecase
+	}
+
+	apiKeyUsecase := usecase.NewAPIKeyUsecase(backend.APIKeyRepository, cfg.APIKeysLastUsedInterval)
+
+	if command == "serve" {
+		// Changes made through the server are streamed to its own clients only
+		broker := event.NewBroker(cfg.EventsReplaySize, cfg.EventsSubscriberBuffer)
+		vocabularyUsecase := usecase.NewVocabularyUsecase(backend.Repository, backend.TxManager, broker, outbox)
+		return serve(ctx, cfg, backend, vocabularyUsecase, apiKeyUsecase, webhookUsecase, jobUsecase, broker)
+	}
+	vocabularyUsecase := usecase.NewVocabularyUsecase(backend.Repository, backend.TxManager, nil, outbox)
+	vocabularyCommand := cli.NewVocabularyCommand(vocabularyUsecase, os.Stdout, os.Stderr)
+
+	// Attribute the writes of the commands to the CLI
+	ctx = usecase.WithActor(ctx, "cli")
+
+	switch command {
+	case "apikey":
+		return cli.NewAPIKeyCommand(apiKeyUsecase, os.Stdout, os.Stderr).APIKey(ctx, args)
+	case "import":
+		return vocabularyCommand.Import(ctx, args)
+	case "export":
+		return vocabularyCommand.Export(ctx, args)
+	case "seed":
+		return vocabularyCommand.Seed(ctx, args)
+	default:
+		return vocabularyCommand.Vocab(ctx, args)
+	}
+}
+
+// backend is the storage selected by DB_DRIVER
+type backend struct {
+	Repository usecase.VocabularyRepository
+	TxManager  usecase.TxManager
+
+	// Used for the migrations. nil for the in-memory backend.
+	SQLDB *sql.DB
+
+	// Shared by every instance of the server. nil when the keys are kept in process memory.
+	IdempotencyStore usecase.IdempotencyStore
+
+	// nil when the backend keeps no webhooks
+	WebhookRepository usecase.WebhookRepository
+
+	// nil when the backend keeps no jobs
+	JobRepository usecase.JobRepository
+
+	APIKeyRepository usecase.APIKeyRepository
+
+	// Releases every connection of the backend
+	Close func()
+}
+
+func openBackend(ctx context.Context, cfg *config.Config) (*backend, error) {
+	switch cfg.DBDriver {
+	case config.DBDriverMemory:
+		repository := memory.NewVocabularyRepository()
+		return &backend{
+			Repository:       repository,
+			TxManager:        memory.NewTxManager(repository),
+			APIKeyRepository: memory.NewAPIKeyRepository(),
+			Close:            func() {},
+		}, nil
+	case config.DBDriverSQLite:
+		sqlDB, err := db.OpenSQLite(ctx, cfg.SQLitePath)
+		if err != nil {
+			return nil, err
+		}
+		return &backend{
+			Repository:       sqlite.NewVocabularyRepository(sqlDB),
+			TxManager:        sqlite.NewTxManager(sqlDB),
+			SQLDB:            sqlDB,
+			APIKeyRepository: sqlite.NewAPIKeyRepository(sqlDB),
+			Close:            func() { sqlDB.Close() },
+		}, nil
+	default:
+		isolation, err := usecase.ParseIsolationLevel(cfg.DBTxIsolation)
+		if err != nil {
+			return nil, err
+		}
+
+		router, err := db.OpenRouter(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		// The migrations run through database/sql on top of the primary pool
+		sqlDB := stdlib.OpenDBFromPool(router.Primary)
+		return &backend{
+			Repository:        repository.NewVocabularyRepository(router),
+			TxManager:         repository.NewTxManager(router, isolation, cfg.DBTxMaxRetries),
+			SQLDB:             sqlDB,
+			IdempotencyStore:  repository.NewIdempotencyStore(router),
+			WebhookRepository: repository.NewWebhookRepository(router),
+			JobRepository:     repository.NewJobRepository(router),
+			APIKeyRepository:  repository.NewAPIKeyRepository(router),
+			Close: func() {
+				sqlDB.Close()
+				router.Close()
+			},
+		}, nil
+	}
+}
+
+func serve(
+	ctx context.Context, cfg *config.Config, backend *backend, vocabularyUsecase *usecase.VocabularyUsecase,
+	apiKeyUsecase *usecase.APIKeyUsecase, webhookUsecase *usecase.WebhookUsecase, jobUsecase *usecase.JobUsecase,
+	broker *event.Broker,
+) error {
+	vocabularyController := controller.NewVocabularyController(
+		vocabularyUsecase, cfg.MaxBodyBytes, cfg.BatchMaxOperations, cfg.EventsHeartbeatInterval,
+	)
+
+	webhookController := controller.NewWebhookController(webhookUsecase, cfg.MaxBodyBytes)
+
+	// Register the job handlers and schedule the cron-style jobs
+	jobUsecase.Register(usecase.HandleJob(usecase.PruneJobsKind, jobUsecase.PruneJobs))
+	jobUsecase.Register(webhookUsecase.JobHandler())
+
+	scheduler := job.NewScheduler(jobUsecase)
+	if cfg.JobsPruneSchedule != "" {
+		pruneJob, err := usecase.NewJob(usecase.PruneJobsKind, usecase.PruneJobsArgs{Retention: cfg.JobsRetention})
+		if err != nil {
+			return err
+		}
+		if err := scheduler.Add(cfg.JobsPruneSchedule, pruneJob); err != nil {
+			slog.ErrorContext(ctx, "invalid JOBS_PRUNE_SCHEDULE", "error", err)
+			return &cli.ExitError{Code: cli.ExitUsage, Err: err}
+		}
+	}
+
+	// Without a key, clients may only read the vocabularies and only when it is enabled
+	var anonymousScopes []domain.Scope
+	if cfg.APIKeysAnonymousRead {
+		anonymousScopes = []domain.Scope{domain.ScopeVocabRead}
+		slog.WarnContext(
+			ctx, "anonymous vocabulary reads are deprecated and will be refused by default in the next release; "+
+				"give the clients an API key with vocab:read and set API_KEYS_ANONYMOUS_READ=false",
+		)
+	}
+
+	// The CLI cannot reach the keys of the memory backend, so each run starts with an admin key
+	if cfg.DBDriver == config.DBDriverMemory {
+		token, err := apiKeyUsecase.CreateAPIKey(ctx, &domain.APIKey{Name: "memory backend", Scopes: domain.Scopes})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "API key of this run of the memory backend: %s\n", token)
+	}
+
+	apiKeyController := controller.NewAPIKeyController(apiKeyUsecase, cfg.MaxBodyBytes)
 
 	// Register the handlers
-	serveMux := web.NewServeMux(vocabularyController)
-	mux := serveMux.RegisterHandler()
+	serveMux := web.NewServeMux(vocabularyController, webhookController, apiKeyController, cfg.HandlerTimeout)
+
+	var handler http.Handler = serveMux.RegisterHandler()
+
+	// Wrap the handlers with the middlewares
+	if cfg.DBReadYourWrites {
+		handler = web.NewReadYourWrites(cfg.DBReadYourWritesWindow).Middleware(handler)
+	}
+
+	if cfg.IdempotencyEnabled {
+		idempotencyStore := backend.IdempotencyStore
+		if idempotencyStore == nil {
+			idempotencyStore = web.NewMemoryIdempotencyStore()
+		}
+		idempotency := web.NewIdempotency(idempotencyStore, cfg.IdempotencyKeyTTL, cfg.MaxBodyBytes)
+		handler = idempotency.Middleware(handler)
+	}
+
+	if cfg.RateLimitEnabled {
+		rateLimiter := web.NewRateLimiter(
+			web.NewMemoryRateLimitStore(),
+			web.RateLimit{Rate: cfg.RateLimitReadRate, Burst: cfg.RateLimitReadBurst},
+			web.RateLimit{Rate: cfg.RateLimitWriteRate, Burst: cfg.RateLimitWriteBurst},
+		)
+		handler = rateLimiter.Middleware(handler)
+	}
+
+	// Check the API key before the rate limit, which needs to know the client
+	handler = web.NewAuthenticator(apiKeyUsecase, anonymousScopes).Middleware(handler)
+
+	// CORS is the outermost so that rejected requests also carry the CORS headers
+	if len(cfg.CORSAllowedOrigins) > 0 {
+		cors := web.NewCORS(
+			cfg.CORSAllowedOrigins, cfg.CORSAllowedMethods, cfg.CORSAllowedHeaders,
+			cfg.CORSAllowCredentials, cfg.CORSMaxAge,
+		)
+		handler = cors.Middleware(handler)
+	}
+
+	// Load the TLS certificate
+	var tlsReloader *web.TLSReloader
+	if cfg.TLSCertFile != "" {
+		var err error
+		tlsReloader, err = web.NewTLSReloader(cfg.TLSCertFile, cfg.TLSKeyFile, cfg.TLSClientCAFile)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to load the TLS certificate", "error", err)
+			return err
+		}
+	}
 
 	// Run the http server
-	server := web.NewServer(cfg.Port, mux)
-	if err = server.Run(ctx); err != nil {
-		return err
+	timeouts := web.ServerTimeouts{
+		ReadHeader: cfg.ReadHeaderTimeout,
+		Read:       cfg.ReadTimeout,
+		Write:      cfg.WriteTimeout,
+		Idle:       cfg.IdleTimeout,
+		Shutdown:   cfg.ShutdownTimeout,
+	}
+	server := web.NewServer(cfg.Port, handler, timeouts, tlsReloader)
+
+	// Close the event streams so that the shutdown does not wait for them
+	server.OnShutdown = append(server.OnShutdown, broker.Close)
+
+	// Run the workers in the errgroup of the server, so that they are drained on shutdown
+	if backend.JobRepository != nil && cfg.JobsWorkerEnabled {
+		worker := job.NewWorker(jobUsecase, cfg.JobsPollInterval, cfg.JobsConcurrency, cfg.JobsDrainTimeout)
+		server.Background = append(server.Background, worker.Run, scheduler.Run)
 	}
 
-	return nil
+	// Run the grpc server next to the http server, so that both shut down together
+	if cfg.GRPCEnabled {
+		grpcServer := grpcserver.NewServer(
+			cfg.GRPCPort, rpc.NewVocabularyService(vocabularyUsecase), cfg.ShutdownTimeout, tlsReloader,
+		)
+		grpcServer.APIKeys = apiKeyUsecase
+		grpcServer.Scopes = rpc.Scopes
+		grpcServer.AnonymousScopes = anonymousScopes
+		if cfg.DBReadYourWrites {
+			grpcServer.Contexts = append(grpcServer.Contexts, db.WithReadYourWrites)
+		}
+		server.Background = append(server.Background, grpcServer.Run)
+	}
+
+	return server.Run(ctx)
+}
+
+func migrate(ctx context.Context, cfg *config.Config, sqlDB *sql.DB, args []string) error {
+	if len(args) != 0 {
+		return cli.UsageError("migrate takes no arguments")
+	}
+
+	// The sqlite schema is created when the database is opened, and memory has no schema
+	if cfg.DBDriver != config.DBDriverPostgres {
+		slog.InfoContext(ctx, "no migration is needed", slog.String("driver", cfg.DBDriver))
+		return nil
+	}
+
+	_, err := db.Migrate(ctx, sqlDB, migrations.FS)
+	return err
 }
 
 func main() {
 	ctx := context.Background()
-	if err := run(ctx); err != nil {
-		slog.ErrorContext(ctx, "Golang application could not start", "err", err)
+	err := run(ctx, os.Args[1:])
+	if err != nil {
+		slog.ErrorContext(ctx, "Golang application failed", "err", err)
+		fmt.Fprintln(os.Stderr, "error:", err)
 	}
+
+	os.Exit(cli.ExitCode(err))
 }
@@ -0,0 +1 @@
+ALTER TABLE vocabularies DROP COLUMN IF EXISTS version;
@@ -0,0 +1 @@
+ALTER TABLE vocabularies ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
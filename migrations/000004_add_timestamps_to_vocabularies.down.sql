@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS vocabularies_updated_at_idx;
+DROP INDEX IF EXISTS vocabularies_created_at_idx;
+
+ALTER TABLE vocabularies
+    DROP COLUMN IF EXISTS updated_by,
+    DROP COLUMN IF EXISTS created_by,
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS created_at;
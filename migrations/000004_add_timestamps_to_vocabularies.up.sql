@@ -0,0 +1,8 @@
+ALTER TABLE vocabularies
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN IF NOT EXISTS created_by TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS updated_by TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS vocabularies_created_at_idx ON vocabularies (created_at);
+CREATE INDEX IF NOT EXISTS vocabularies_updated_at_idx ON vocabularies (updated_at);
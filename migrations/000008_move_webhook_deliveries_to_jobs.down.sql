@@ -0,0 +1,33 @@
+DROP INDEX IF EXISTS jobs_webhook_id_idx;
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    delivery_id BIGSERIAL PRIMARY KEY,
+    webhook_id BIGINT NOT NULL REFERENCES webhooks (webhook_id) ON DELETE CASCADE,
+    event JSONB NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_status_code INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    delivered_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_due_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_id_idx ON webhook_deliveries (webhook_id, delivery_id);
+
+INSERT INTO webhook_deliveries (webhook_id, event, status, attempts, next_attempt_at, last_error, created_at, delivered_at)
+SELECT
+    (payload->>'webhook_id')::bigint,
+    payload - 'webhook_id',
+    CASE status WHEN 'succeeded' THEN 'delivered' ELSE status END,
+    attempts,
+    run_at,
+    last_error,
+    created_at,
+    CASE status WHEN 'succeeded' THEN finished_at END
+FROM jobs
+WHERE kind = 'webhook.deliver' AND (payload->>'webhook_id')::bigint IN (SELECT webhook_id FROM webhooks)
+ORDER BY job_id;
+
+DELETE FROM jobs WHERE kind = 'webhook.deliver';
@@ -0,0 +1,18 @@
+-- Webhook deliveries become jobs of the kind webhook.deliver. The payload keeps the event with its webhook.
+INSERT INTO jobs (kind, payload, status, attempts, run_at, last_error, created_at, finished_at)
+SELECT
+    'webhook.deliver',
+    event || jsonb_build_object('webhook_id', webhook_id),
+    CASE status WHEN 'delivered' THEN 'succeeded' ELSE status END,
+    attempts,
+    next_attempt_at,
+    last_error,
+    created_at,
+    CASE WHEN status <> 'pending' THEN COALESCE(delivered_at, next_attempt_at) END
+FROM webhook_deliveries
+ORDER BY delivery_id;
+
+DROP TABLE IF EXISTS webhook_deliveries;
+
+CREATE INDEX IF NOT EXISTS jobs_webhook_id_idx ON jobs (((payload->>'webhook_id')::bigint), job_id)
+    WHERE kind = 'webhook.deliver';
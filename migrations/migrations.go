@@ -0,0 +1,8 @@
+package migrations
+
+import "embed"
+
+// FS holds the SQL migration files so that they are shipped inside the binary
+//
+//go:embed *.sql
+var FS embed.FS